@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	DefaultClosestAchievements = 10
+
+	AchievementsSnapshotsParamDescription = `show unlock timeline of achievements, from a .json file of snapshots (eg. saved with 'watch -snapshots')`
+	AchievementsTopParamDescription       = `number of achievements closest to unlock (0 for all)`
+	AchievementsJsonParamDescription      = `print report in json`
+)
+
+// show completion of achievements of a player, with unlock timeline and ones closest to unlock
+func runAchievements(args []string) error {
+	flags := flag.NewFlagSet("achievements", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	snapshotsFile := flags.String("snapshots", "", AchievementsSnapshotsParamDescription)
+	top := flags.Int("top", DefaultClosestAchievements, AchievementsTopParamDescription)
+	toJson := flags.Bool("json", false, AchievementsJsonParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []stat.Snapshot
+	if *snapshotsFile != "" {
+		if snapshots, err = loadSnapshots(*snapshotsFile); err != nil {
+			return err
+		}
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+	if result.Private {
+		return errorWithCode(ExitCodeError, "Achievements of %s are not public", p.battleTag)
+	}
+
+	report := stat.NewAchievementReport(result, snapshots)
+	if *top > 0 && len(report.Closest) > *top {
+		report.Closest = report.Closest[:*top]
+	}
+
+	var output []byte
+	if *toJson {
+		if output, err = json.MarshalIndent(report, "", "\t"); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	} else {
+		output = []byte(formatAchievementReport(report))
+	}
+	return writeOutput(*outFile, output, *suppressOutput)
+}
+
+// format achievement report in human-readable format
+func formatAchievementReport(report stat.AchievementReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Achievements: %d / %d (%.1f%%)\n", report.Overall.Achieved, report.Overall.Total, report.Overall.Percent)
+	for _, category := range report.Categories {
+		fmt.Fprintf(&b, "  %-16s %3d / %3d (%5.1f%%)\n", category.Category, category.Achieved, category.Total, category.Percent)
+	}
+
+	if len(report.Timeline) > 0 {
+		fmt.Fprintf(&b, "\nUnlocked:\n")
+		for _, unlock := range report.Timeline {
+			when := unlock.UnlockedAt.Format("2006-01-02")
+			if unlock.Initial {
+				when = "<= " + when
+			}
+			fmt.Fprintf(&b, "  %-13s %s (%s)\n", when, unlock.Title, unlock.Category)
+		}
+	}
+
+	if len(report.Closest) > 0 {
+		fmt.Fprintf(&b, "\nClosest to unlock:\n")
+		for _, progress := range report.Closest {
+			fmt.Fprintf(&b, "  %s (%s): %s\n", progress.Title, progress.Category, progress.Description)
+			if progress.Measured {
+				fmt.Fprintf(&b, "    %.0f / %.0f (%.0f%%)\n", progress.Current, progress.Target, progress.Progress*100)
+			} else if progress.Hero != "" {
+				timePlayed := progress.TimePlayed
+				if timePlayed == "" {
+					timePlayed = "not played"
+				}
+				stats := []string{}
+				for _, kv := range progress.Stats {
+					stats = append(stats, fmt.Sprintf("%s: %s", kv.Key, kv.Value))
+				}
+				fmt.Fprintf(&b, "    %s: %s", progress.Hero, timePlayed)
+				if len(stats) > 0 {
+					fmt.Fprintf(&b, " / %s", strings.Join(stats, ", "))
+				}
+				fmt.Fprintf(&b, "\n")
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
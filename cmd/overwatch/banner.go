@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	BannerOutFileParamDescription = `banner file to create (its format is determined by -format, or its extension)`
+	BannerHeroParamDescription    = `create a .png card of given hero instead, eg. "Ana"`
+)
+
+// create a banner (or a card of a hero) of a player
+func runBanner(args []string) error {
+	flags := flag.NewFlagSet("banner", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	outFile := flags.String("out", "", BannerOutFileParamDescription)
+	bf := addBannerFlags(flags, "")
+	hero := flags.String("hero", "", BannerHeroParamDescription)
+	flags.Parse(args)
+
+	if *outFile == "" {
+		return usageError(flags, "Banner file was not given")
+	}
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	var spec stat.BannerSpec
+	var format string
+	if *hero == "" {
+		if spec, format, err = bf.spec(p, *outFile); err != nil {
+			return err
+		}
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+
+	if *hero != "" {
+		if err := stat.RenderHeroBannerToPngFile(result, *hero, stat.HeroBannerOptions{}, *outFile); err != nil {
+			return fmt.Errorf("Failed to create a hero banner file: %s", err)
+		}
+		return nil
+	}
+	return bf.render(result, spec, format, *outFile)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meinside/overwatch-go/metrics"
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// exit codes
+const (
+	ExitCodeOk       = 0
+	ExitCodeError    = 1 // other errors (eg. failed to render or save results)
+	ExitCodeUsage    = 2 // unknown command, malformed flags, or missing battle tag
+	ExitCodeNotFound = 3 // no such player
+	ExitCodeNetwork  = 4 // failed to reach the site, or it responded with an unexpected status
+	ExitCodeParse    = 5 // failed to parse the career page (layout of the site may have changed)
+	ExitCodeDrifted  = 6 // (doctor) layout of the career page has drifted
+	ExitCodeConfig   = 7 // malformed config file
+)
+
+// an error with the exit code of the program
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+// create an error which exits the program with given code
+func errorWithCode(code int, format string, a ...interface{}) error {
+	return exitError{code: code, err: fmt.Errorf(format, a...)}
+}
+
+// create an error of wrong usage, after printing usage of given flags
+func usageError(flags *flag.FlagSet, format string, a ...interface{}) error {
+	flags.Usage()
+
+	return errorWithCode(ExitCodeUsage, format, a...)
+}
+
+// create an error of failed fetch, with the exit code of its cause
+func fetchError(err error, format string, a ...interface{}) error {
+	code := ExitCodeError
+	if err == stat.ErrPlayerNotFound {
+		code = ExitCodeNotFound
+	} else {
+		switch err.(type) {
+		case *url.Error, net.Error, stat.StatusError:
+			code = ExitCodeNetwork
+		case stat.ParseError:
+			code = ExitCodeParse
+		}
+	}
+
+	return errorWithCode(code, "%s: %s", fmt.Sprintf(format, a...), err)
+}
+
+// exit the program with given error: its message on stderr, and its exit code
+func exit(err error) {
+	if err == nil {
+		os.Exit(ExitCodeOk)
+	}
+
+	code := ExitCodeError
+	if e, ok := err.(exitError); ok {
+		code = e.code
+	}
+	fmt.Fprintf(os.Stderr, "* %s\n", err)
+
+	os.Exit(code)
+}
+
+// flags of a player
+type playerFlags struct {
+	platform  *string
+	region    *string
+	language  *string
+	battleTag *string
+	verbose   *bool
+}
+
+// add flags of a player to given flag set
+func addPlayerFlags(flags *flag.FlagSet, defaultBattleTag, defaultRegion string) playerFlags {
+	p := addPlayerOptionFlags(flags, defaultRegion)
+	p.battleTag = flags.String("battletag", defaultBattleTag, BattleTagParamDescription)
+	return p
+}
+
+// add flags of a player except the battle tag (eg. for multiple players) to given flag set
+func addPlayerOptionFlags(flags *flag.FlagSet, defaultRegion string) playerFlags {
+	return playerFlags{
+		platform: flags.String("platform", conf.Platform, PlatformParamDescription),
+		region:   flags.String("region", defaultRegion, RegionParamDescription),
+		language: flags.String("language", conf.Language, LanguageParamDescription),
+		verbose:  flags.Bool("verbose", false, VerboseParamDescription),
+	}
+}
+
+// a player to fetch stats of
+type player struct {
+	battleTag string
+	name      string
+	number    int
+	platform  string
+	region    string
+	language  string
+
+	bannerTheme string // banner theme of the named player (if any)
+
+	warned bool // whether the warning of private profile was printed
+}
+
+// get the player from parsed flags (battle tag, or name of a player in the config, can also be given as the first argument)
+func (p playerFlags) player(flags *flag.FlagSet) (*player, error) {
+	battleTag := *p.battleTag
+	if flags.NArg() > 0 && !isFlagSet(flags, "battletag") {
+		battleTag = flags.Arg(0)
+	}
+	if battleTag == "" {
+		return nil, usageError(flags, "Battle Tag was not given")
+	}
+
+	return p.resolve(flags, battleTag)
+}
+
+// get the player of given battle tag, or of given name of a player in the config
+//
+// (values of the named player are used unless they are given with flags)
+func (p playerFlags) resolve(flags *flag.FlagSet, battleTag string) (*player, error) {
+	stat.Verbose = *p.verbose
+
+	platform, region, language, bannerTheme := *p.platform, *p.region, *p.language, ""
+	if named, exists := conf.Players[battleTag]; exists {
+		if named.BattleTag == "" {
+			return nil, errorWithCode(ExitCodeConfig, "Battle tag of player '%s' is missing in the config", battleTag)
+		}
+
+		battleTag = named.BattleTag
+		for _, v := range []struct {
+			flag  string
+			dst   *string
+			value string
+		}{
+			{"platform", &platform, named.Platform},
+			{"region", &region, named.Region},
+			{"language", &language, named.Language},
+		} {
+			if v.value != "" && !isFlagSet(flags, v.flag) {
+				*v.dst = v.value
+			}
+		}
+		bannerTheme = named.BannerTheme
+	}
+
+	name, number, err := stat.ParseBattleTag(battleTag, platform)
+	if err != nil {
+		return nil, errorWithCode(ExitCodeUsage, "%s", err)
+	}
+
+	if !strings.EqualFold(platform, stat.PlatformPc) {
+		region = "" // XXX - not needed
+	}
+
+	return &player{
+		battleTag:   battleTag,
+		name:        name,
+		number:      number,
+		platform:    platform,
+		region:      region,
+		language:    language,
+		bannerTheme: bannerTheme,
+	}, nil
+}
+
+// banner theme of the player (if named in the config), unless the flag of given name is set explicitly
+func (p *player) bannerThemeOr(flags *flag.FlagSet, flagName, theme string) string {
+	if p.bannerTheme != "" && !isFlagSet(flags, flagName) {
+		return p.bannerTheme
+	}
+	return theme
+}
+
+// fetch stat of the player
+//
+// (when the career profile is private, it warns once and returns public information only)
+func (p *player) fetch() (stat.Stat, error) {
+	result, err := stat.FetchStat(p.name, p.number, p.platform, p.region, p.language)
+	if err == stat.ErrPrivateProfile {
+		if !p.warned {
+			fmt.Fprintf(os.Stderr, "* Career profile of %s is private, so only public information is available.\n  (change 'Career Profile Visibility' to 'Public' in the game's social options to show stats)\n", p.battleTag)
+			p.warned = true
+		}
+
+		return result, nil
+	}
+	if err != nil {
+		return result, fetchError(err, "Failed to fetch stat of %s", p.battleTag)
+	}
+	return result, nil
+}
+
+// check if given flag was set explicitly
+func isFlagSet(flags *flag.FlagSet, name string) (set bool) {
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// flags of banners
+type bannerFlags struct {
+	flags  *flag.FlagSet
+	prefix string
+
+	theme       *string
+	format      *string
+	quality     *int
+	animated    *bool
+	delay       *time.Duration
+	linkImages  *bool
+	endorsement *bool
+}
+
+// add flags of banners to given flag set, with given prefix of names (eg. "banner-")
+func addBannerFlags(flags *flag.FlagSet, prefix string) bannerFlags {
+	return bannerFlags{
+		flags:       flags,
+		prefix:      prefix,
+		theme:       flags.String(prefix+"theme", conf.BannerTheme, BannerThemeParamDescription),
+		format:      flags.String(prefix+"format", conf.Output.BannerFormat, BannerFormatParamDescription),
+		quality:     flags.Int(prefix+"quality", stat.DefaultJpegQuality, BannerQualityParamDescription),
+		animated:    flags.Bool(prefix+"animated", false, BannerAnimatedDescription),
+		delay:       flags.Duration(prefix+"delay", stat.DefaultAnimatedGifDelay, BannerDelayParamDescription),
+		linkImages:  flags.Bool(prefix+"link-images", false, BannerLinkImagesDescription),
+		endorsement: flags.Bool(prefix+"endorsement", false, BannerEndorsementDescription),
+	}
+}
+
+// check flags of banners, and return the spec and format of the banner file for given player
+func (b bannerFlags) spec(p *player, outFilepath string) (spec stat.BannerSpec, format string, err error) {
+	if spec, err = stat.BannerTheme(p.bannerThemeOr(b.flags, b.prefix+"theme", *b.theme)); err != nil {
+		return spec, "", errorWithCode(ExitCodeUsage, "%s", err)
+	}
+	if *b.endorsement {
+		spec.Endorsement.Show = true
+	}
+
+	format = *b.format
+	if format == "" {
+		format = bannerFormatOf(outFilepath)
+	}
+	switch format {
+	case BannerFormatPng, BannerFormatSvg, BannerFormatJpeg, BannerFormatGif:
+	default:
+		return spec, "", errorWithCode(ExitCodeUsage, "Unsupported banner format: %s", format)
+	}
+	if *b.animated && format != BannerFormatGif {
+		return spec, "", errorWithCode(ExitCodeUsage, "Animated banners are only supported in %s format", BannerFormatGif)
+	}
+
+	return spec, format, nil
+}
+
+// render a banner file of given stat
+func (b bannerFlags) render(result stat.Stat, spec stat.BannerSpec, format, outFilepath string) (err error) {
+	switch format {
+	case BannerFormatSvg:
+		err = stat.RenderStatToSvgFile(result, spec, !*b.linkImages, outFilepath)
+	case BannerFormatJpeg:
+		err = stat.RenderStatToJpegFile(result, spec, nil, nil, *b.quality, outFilepath)
+	case BannerFormatGif:
+		if *b.animated {
+			err = stat.RenderStatToAnimatedGifFile(result, spec, nil, nil, *b.delay, outFilepath)
+		} else {
+			err = stat.RenderStatToGifFile(result, spec, nil, nil, outFilepath)
+		}
+	default:
+		err = stat.RenderStatToPngFileWithSpec(result, spec, nil, nil, outFilepath)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to create a banner file: %s", err)
+	}
+	return nil
+}
+
+// format of a banner file, by its extension (default: png)
+func bannerFormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		return BannerFormatSvg
+	case ".jpg", ".jpeg":
+		return BannerFormatJpeg
+	case ".gif":
+		return BannerFormatGif
+	default:
+		return BannerFormatPng
+	}
+}
+
+// save output to given file, or print it to stdout (unless quiet)
+func writeOutput(outFilepath string, output []byte, quiet bool) error {
+	if outFilepath != "" {
+		if err := saveToFile(outFilepath, output); err != nil {
+			return fmt.Errorf("Failed to save %s: %s", outFilepath, err)
+		}
+	} else if !quiet {
+		fmt.Printf("%s\n", string(output)) // print to stdout
+	}
+	return nil
+}
+
+// load snapshots from given .json file of snapshots
+func loadSnapshots(path string) (snapshots []stat.Snapshot, err error) {
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(bytes, &snapshots); err != nil {
+		return nil, fmt.Errorf("Malformed snapshots file %s: %s", path, err)
+	}
+	return snapshots, nil
+}
+
+func saveToFile(filepath string, bytes []byte) error {
+	return ioutil.WriteFile(filepath, bytes, 0640)
+}
+
+// stat with its derived metrics, encoded in json with "metrics" key
+type statWithMetrics struct {
+	stat.Stat
+	Metrics metrics.Report `json:"metrics"`
+}
+
+// encode given stat in json (with derived metrics, if needed)
+func statToJson(result stat.Stat, withMetrics bool) ([]byte, error) {
+	if withMetrics {
+		return json.MarshalIndent(statWithMetrics{Stat: result, Metrics: metrics.CalculateStat(result)}, "", "\t")
+	}
+	return json.MarshalIndent(result, "", "\t")
+}
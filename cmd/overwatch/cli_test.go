@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestFetchError(t *testing.T) {
+	_, parseErr := stat.ParseStat(strings.NewReader("<html></html>"), "nobody", 1, stat.PlatformPc, "us")
+
+	for _, test := range []struct {
+		err      error
+		expected int
+	}{
+		{stat.ErrPlayerNotFound, ExitCodeNotFound},
+		{stat.StatusError{Url: stattest.FixturePcCompetitive.Path(), StatusCode: 503}, ExitCodeNetwork},
+		{&url.Error{Op: "Get", URL: "https://playoverwatch.com", Err: errors.New("timeout")}, ExitCodeNetwork},
+		{parseErr, ExitCodeParse},
+		{errors.New("something else"), ExitCodeError},
+	} {
+		err := fetchError(test.err, "Failed to fetch")
+		if e, ok := err.(exitError); !ok || e.code != test.expected {
+			t.Errorf("expected exit code %d for '%v', got %+v", test.expected, test.err, err)
+		}
+	}
+}
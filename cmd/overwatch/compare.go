@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	CompareBattleTagParamDescription   = `battle tag of a player to compare (repeat it for each player), eg. "meinside#3155"`
+	CompareCompetitiveParamDescription = `compare stats of competitive play, instead of quick play`
+	ComparePngFileParamDescription     = `create an image of the comparison in .png format`
+)
+
+// flag which can be given multiple times
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// compare stats of two or more players side by side
+func runCompare(args []string) error {
+	var battleTags stringsFlag
+
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	pf := addPlayerOptionFlags(flags, conf.Region)
+	flags.Var(&battleTags, "battletag", CompareBattleTagParamDescription)
+	competitive := flags.Bool("competitive", false, CompareCompetitiveParamDescription)
+	toHtml := flags.Bool("html", false, ToHtmlParamDescription)
+	htmlInline := flags.Bool("html-inline", conf.Output.HtmlInline, HtmlInlineParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	pngFile := flags.String("png", "", ComparePngFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
+
+	// (battle tags, or names of players in the config, can also be given as arguments)
+	battleTags = append(battleTags, flags.Args()...)
+
+	if len(battleTags) < 2 {
+		return usageError(flags, "At least two battle tags are needed for comparison")
+	}
+
+	stats := []stat.Stat{}
+	for _, battleTag := range battleTags {
+		p, err := pf.resolve(flags, battleTag)
+		if err != nil {
+			return err
+		}
+
+		result, err := p.fetch()
+		if err != nil {
+			return err
+		}
+		stats = append(stats, result)
+	}
+
+	comparison := stat.CompareStats(stats, *competitive)
+
+	// print or save result
+	var output []byte
+	if *toHtml {
+		if html, err := stat.RenderComparisonToHtml(comparison, stat.SampleComparisonHtmlTemplate); err == nil {
+			if *htmlInline {
+				html = stat.InlineHtmlAssets(html)
+			}
+			output = []byte(html)
+		} else {
+			return fmt.Errorf("HTML encode error: %s", err)
+		}
+	} else {
+		if bytes, err := json.MarshalIndent(comparison, "", "\t"); err == nil {
+			output = bytes
+		} else {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	}
+	if err := writeOutput(*outFile, output, *suppressOutput); err != nil {
+		return err
+	}
+
+	// if requested, create an image file
+	if *pngFile != "" {
+		if err := stat.RenderComparisonToPngFile(comparison, nil, *pngFile); err != nil {
+			return fmt.Errorf("Failed to create an image file: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	ConfigDirName  = "overwatch"
+	ConfigFileName = "config.toml"
+)
+
+// environment variables which override values of the config file
+const (
+	EnvConfigFile  = "OVERWATCH_CONFIG" // path of the config file
+	EnvPlatform    = "OVERWATCH_PLATFORM"
+	EnvRegion      = "OVERWATCH_REGION"
+	EnvLanguage    = "OVERWATCH_LANGUAGE"
+	EnvBattleTag   = "OVERWATCH_BATTLETAG"
+	EnvCacheDir    = "OVERWATCH_CACHE_DIR"
+	EnvBannerTheme = "OVERWATCH_BANNER_THEME"
+)
+
+// config file, eg:
+//
+//	platform = "pc"
+//	region = "kr"
+//	language = "ko-kr"
+//	battletag = "meinside#3155"
+//	cache_dir = "~/.cache/overwatch"
+//	banner_theme = "signature"
+//
+//	[output]
+//	quiet = false
+//	html_inline = true
+//	banner_format = "svg"
+//
+//	[players.tank-main]
+//	battletag = "someone#1234"
+//	region = "us"
+//	language = "en-us"
+//
+//	[rosters.scrim]
+//	name = "Scrim Team"
+//	members = [
+//		{ player = "tank-main", role = "tank" },
+//		{ battletag = "other#5678", role = "support" },
+//	]
+//
+//	[[goals]]
+//	name = "reach 3000 SR"
+//	metric = "competitive_rank"
+//	target = 3000
+//
+//	[[goals]]
+//	player = "tank-main"
+//	name = "win rate above 55% on tanks"
+//	metric = "win_rate"
+//	role = "tank"
+//	mode = "competitive_play"
+//	target = 55
+type config struct {
+	Platform    string `toml:"platform"`
+	Region      string `toml:"region"`
+	Language    string `toml:"language"`
+	BattleTag   string `toml:"battletag"` // default player (can be a name of players)
+	CacheDir    string `toml:"cache_dir"` // directory for caching images and fonts
+	BannerTheme string `toml:"banner_theme"`
+
+	Output outputConfig `toml:"output"`
+
+	// named players, eg. `overwatch fetch tank-main`
+	Players map[string]playerConfig `toml:"players"`
+
+	// named rosters, eg. `overwatch roster scrim`
+	Rosters map[string]rosterConfig `toml:"rosters"`
+
+	// goals of players, eg. `overwatch goals tank-main`
+	Goals []goalConfig `toml:"goals"`
+}
+
+// output preferences
+type outputConfig struct {
+	Quiet        bool   `toml:"quiet"`
+	HtmlInline   bool   `toml:"html_inline"`
+	BannerFormat string `toml:"banner_format"`
+}
+
+// a named player (empty values fall back to the defaults)
+type playerConfig struct {
+	BattleTag   string `toml:"battletag"`
+	Platform    string `toml:"platform"`
+	Region      string `toml:"region"`
+	Language    string `toml:"language"`
+	BannerTheme string `toml:"banner_theme"`
+}
+
+// a named roster
+type rosterConfig struct {
+	Name    string               `toml:"name"` // (default: name of the roster in the config)
+	Members []rosterMemberConfig `toml:"members"`
+}
+
+// a member of a roster: a named player, or a battle tag (empty values fall back to the defaults)
+type rosterMemberConfig struct {
+	Player    string `toml:"player"` // name of a player in the config
+	Name      string `toml:"name"`   // (default: name of the player, or the battle tag)
+	BattleTag string `toml:"battletag"`
+	Platform  string `toml:"platform"`
+	Region    string `toml:"region"`
+	Language  string `toml:"language"`
+	Role      string `toml:"role"` // eg. "tank", "damage", "support", or "flex"
+}
+
+// a goal of a player (see stat.Goal)
+type goalConfig struct {
+	Player string  `toml:"player"` // name of a player in the config, or a battle tag (default: the default player)
+	Name   string  `toml:"name"`
+	Metric string  `toml:"metric"` // eg. "competitive_rank", "games_played", "win_rate", ...
+	Hero   string  `toml:"hero"`
+	Role   string  `toml:"role"`
+	Mode   string  `toml:"mode"` // "quick_play", "competitive_play", or empty for both
+	Target float64 `toml:"target"`
+}
+
+// loaded config, with environment variables and default values applied
+var conf = config{
+	Platform:    DefaultPlatform,
+	Region:      DefaultRegion,
+	Language:    DefaultLanguage,
+	BannerTheme: stat.BannerThemeDefault,
+}
+
+// path of the config file: $OVERWATCH_CONFIG, or $XDG_CONFIG_HOME/overwatch/config.toml (default: ~/.config/overwatch/config.toml)
+func configFilepath() string {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, ConfigDirName, ConfigFileName)
+}
+
+// load the config file (if any), and apply environment variables to it
+func loadConfig() error {
+	path := configFilepath()
+	if _, err := os.Stat(path); err == nil {
+		var loaded config
+		if meta, err := toml.DecodeFile(path, &loaded); err == nil {
+			for _, key := range meta.Undecoded() {
+				fmt.Fprintf(os.Stderr, "* Unknown key in %s: %s\n", path, key)
+			}
+			conf.merge(loaded)
+		} else {
+			return errorWithCode(ExitCodeConfig, "Malformed config file %s: %s", path, err)
+		}
+	} else if !os.IsNotExist(err) || os.Getenv(EnvConfigFile) != "" {
+		return errorWithCode(ExitCodeConfig, "Failed to read config file: %s", err)
+	}
+
+	conf.merge(config{
+		Platform:    os.Getenv(EnvPlatform),
+		Region:      os.Getenv(EnvRegion),
+		Language:    os.Getenv(EnvLanguage),
+		BattleTag:   os.Getenv(EnvBattleTag),
+		CacheDir:    os.Getenv(EnvCacheDir),
+		BannerTheme: os.Getenv(EnvBannerTheme),
+	})
+
+	if conf.CacheDir != "" {
+		stat.AssetCacheDir = expandHome(conf.CacheDir)
+	}
+
+	return nil
+}
+
+// overwrite values with non-empty ones of given config
+func (c *config) merge(other config) {
+	overwrite := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+
+	overwrite(&c.Platform, other.Platform)
+	overwrite(&c.Region, other.Region)
+	overwrite(&c.Language, other.Language)
+	overwrite(&c.BattleTag, other.BattleTag)
+	overwrite(&c.CacheDir, other.CacheDir)
+	overwrite(&c.BannerTheme, other.BannerTheme)
+
+	if other.Output.Quiet {
+		c.Output.Quiet = true
+	}
+	if other.Output.HtmlInline {
+		c.Output.HtmlInline = true
+	}
+	overwrite(&c.Output.BannerFormat, other.Output.BannerFormat)
+
+	if other.Players != nil {
+		c.Players = other.Players
+	}
+	if other.Rosters != nil {
+		c.Rosters = other.Rosters
+	}
+	if other.Goals != nil {
+		c.Goals = other.Goals
+	}
+}
+
+// names of players in the config, sorted
+func (c config) playerNames() []string {
+	names := []string{}
+	for name := range c.Players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// names of rosters in the config, sorted
+func (c config) rosterNames() []string {
+	names := []string{}
+	for name := range c.Rosters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expand leading "~/" of given path to the home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[2:])
+	}
+	return path
+}
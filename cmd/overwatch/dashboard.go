@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// views of the dashboard
+const (
+	tuiViewOverview = iota
+	tuiViewHeroes
+	tuiViewAchievements
+)
+
+var tuiViewNames = []string{"Overview", "Career Stats", "Achievements"}
+
+// colors of the dashboard
+const (
+	tuiColorText     = termbox.ColorDefault
+	tuiColorTitle    = termbox.ColorYellow | termbox.AttrBold
+	tuiColorLabel    = termbox.ColorCyan
+	tuiColorSelected = termbox.ColorBlack
+	tuiColorBar      = termbox.ColorYellow
+	tuiColorError    = termbox.ColorRed
+	tuiColorDim      = termbox.ColorBlue
+)
+
+const tuiHelp = "[m] quick/competitive  [tab] view  [1/2/3] overview/career stats/achievements  [←/→] select  [↑/↓] scroll  [r] refresh  [q] quit"
+
+// state of the dashboard (without terminal i/o, which is done in tui.go)
+type dashboard struct {
+	battleTag string
+	language  string // (used until a stat is fetched)
+
+	stat     stat.Stat
+	err      error // error of the last fetch
+	updated  time.Time
+	fetching bool
+
+	competitive bool
+	view        int
+	selected    int // index of top heroes' comparison, hero, or achievement category (depends on view)
+	scroll      int
+}
+
+// result of a fetch
+type fetchResult struct {
+	stat stat.Stat
+	err  error
+}
+
+// a line of the body
+type tuiLine struct {
+	indent int
+	text   string
+	fg     termbox.Attribute
+	value  string
+	valueX int // x position of value (when it is not a bar)
+	bar    int // length of bar (when it is a bar chart)
+}
+
+// handle a key event, and return whether the dashboard should quit or start a new fetch
+func (d *dashboard) handleKey(ev termbox.Event) (quit, fetch bool) {
+	switch {
+	case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyCtrlC || ev.Ch == 'q':
+		return true, false
+	case ev.Ch == 'r':
+		return false, d.startFetch()
+	case ev.Ch == 'm':
+		d.competitive = !d.competitive
+		d.selected, d.scroll = 0, 0
+	case ev.Key == termbox.KeyTab:
+		d.view = (d.view + 1) % len(tuiViewNames)
+		d.selected, d.scroll = 0, 0
+	case ev.Ch >= '1' && ev.Ch <= '3':
+		d.view = int(ev.Ch - '1')
+		d.selected, d.scroll = 0, 0
+	case ev.Key == termbox.KeyArrowLeft:
+		if d.selected > 0 {
+			d.selected--
+			d.scroll = 0
+		}
+	case ev.Key == termbox.KeyArrowRight:
+		if d.selected < d.selectable()-1 {
+			d.selected++
+			d.scroll = 0
+		}
+	case ev.Key == termbox.KeyArrowUp:
+		if d.scroll > 0 {
+			d.scroll--
+		}
+	case ev.Key == termbox.KeyArrowDown:
+		d.scroll++
+	}
+	return false, false
+}
+
+// mark the dashboard as fetching, and return whether a new fetch should be started
+//
+// (false when a fetch is already in progress)
+func (d *dashboard) startFetch() bool {
+	if d.fetching {
+		return false
+	}
+	d.fetching = true
+	return true
+}
+
+// apply the result of a fetch
+//
+// (on errors, the last fetched stat is kept)
+func (d *dashboard) apply(result fetchResult, now time.Time) {
+	d.fetching = false
+	d.err = result.err
+	if result.err == nil {
+		d.stat = result.stat
+		d.updated = now
+	}
+}
+
+// play stat of the selected mode
+func (d *dashboard) playStat() stat.PlayStat {
+	if d.competitive {
+		return d.stat.CompetitivePlay
+	}
+	return d.stat.QuickPlay
+}
+
+// number of selectable items in the current view
+func (d *dashboard) selectable() int {
+	switch d.view {
+	case tuiViewOverview:
+		return len(d.playStat().TopHeroes)
+	case tuiViewHeroes:
+		return len(d.playStat().CareerStats)
+	case tuiViewAchievements:
+		return len(d.stat.Achievements)
+	}
+	return 0
+}
+
+// localize given label to the language of the stat (or of the player, before a stat is fetched)
+func (d *dashboard) localize(label string) string {
+	if d.stat.Language != "" {
+		return stat.Localize(d.stat.Language, label)
+	}
+	return stat.Localize(d.language, label)
+}
+
+// status of fetches, shown at the top right
+func (d *dashboard) status() string {
+	switch {
+	case d.fetching:
+		return d.localize("Fetching...")
+	case !d.updated.IsZero():
+		return fmt.Sprintf("%s %s", d.localize("Updated"), d.updated.Format("15:04:05"))
+	}
+	return ""
+}
+
+// error of the last fetch, shown above the help (empty when there was no error)
+func (d *dashboard) errorLine() string {
+	if d.err == nil {
+		return ""
+	}
+	return fmt.Sprintf("* %s: %s", d.localize("Fetch error"), d.err)
+}
+
+// lines of the body in the current view
+func (d *dashboard) bodyLines(width int) []tuiLine {
+	switch {
+	case d.stat.Name == "":
+		if d.fetching {
+			return []tuiLine{{text: d.localize("Loading...")}}
+		}
+		return []tuiLine{}
+	case d.stat.Private && d.view != tuiViewOverview:
+		return []tuiLine{{text: d.localize("Stats and achievements of this player are not public.")}}
+	}
+
+	switch d.view {
+	case tuiViewHeroes:
+		return d.heroLines()
+	case tuiViewAchievements:
+		return d.achievementLines()
+	}
+	return d.overviewLines(width)
+}
+
+// featured stats, and bar chart of the selected top heroes' comparison
+func (d *dashboard) overviewLines(width int) []tuiLine {
+	lines := []tuiLine{}
+	playStat := d.playStat()
+
+	if d.stat.Private {
+		return append(lines, tuiLine{text: d.localize("Private Profile"), fg: tuiColorTitle})
+	}
+
+	lines = append(lines, tuiLine{text: d.localize("Featured Stats"), fg: tuiColorTitle})
+	labelWidth := 0
+	for _, featured := range playStat.FeaturedStats {
+		if w := runewidth.StringWidth(featured.Key); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	for _, featured := range playStat.FeaturedStats {
+		lines = append(lines, tuiLine{indent: 2, text: featured.Key, fg: tuiColorLabel, value: featured.Value, valueX: labelWidth + 4})
+	}
+	lines = append(lines, tuiLine{})
+
+	if len(playStat.TopHeroes) <= 0 {
+		return lines
+	}
+	comparison := playStat.TopHeroes[d.selected%len(playStat.TopHeroes)]
+	lines = append(lines, tuiLine{text: fmt.Sprintf("%s: ◀ %s ▶", d.localize("Top Heroes"), comparison.Name), fg: tuiColorTitle})
+
+	nameWidth := 0
+	max := 0.0
+	values := []float64{}
+	for _, hero := range comparison.Heroes {
+		if w := runewidth.StringWidth(hero.Name); w > nameWidth {
+			nameWidth = w
+		}
+		value := tuiNumber(hero.Value)
+		if value > max {
+			max = value
+		}
+		values = append(values, value)
+	}
+	barWidth := width - nameWidth - 20
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	for i, hero := range comparison.Heroes {
+		bar := 0
+		if max > 0 {
+			bar = int(values[i] / max * float64(barWidth))
+		}
+		if bar <= 0 {
+			bar = 1
+		}
+		name := hero.Name + strings.Repeat(" ", nameWidth-runewidth.StringWidth(hero.Name))
+		lines = append(lines, tuiLine{indent: 2, text: name, fg: tuiColorLabel, value: hero.Value, bar: bar})
+	}
+	return lines
+}
+
+// career stats of the selected hero
+func (d *dashboard) heroLines() []tuiLine {
+	lines := []tuiLine{}
+	careerStats := d.playStat().CareerStats
+	if len(careerStats) <= 0 {
+		return lines
+	}
+	careerStat := careerStats[d.selected%len(careerStats)]
+
+	lines = append(lines, tuiLine{text: fmt.Sprintf("◀ %s ▶  (%d/%d)", careerStat.HeroName, d.selected%len(careerStats)+1, len(careerStats)), fg: tuiColorTitle})
+	for _, category := range careerStat.Categories {
+		lines = append(lines, tuiLine{})
+		lines = append(lines, tuiLine{indent: 2, text: category.Name, fg: tuiColorTitle})
+
+		labelWidth := 0
+		for _, value := range category.Values {
+			if w := runewidth.StringWidth(value.Key); w > labelWidth {
+				labelWidth = w
+			}
+		}
+		for _, value := range category.Values {
+			lines = append(lines, tuiLine{indent: 4, text: value.Key, fg: tuiColorLabel, value: value.Value, valueX: labelWidth + 6})
+		}
+	}
+	return lines
+}
+
+// achievements of the selected category
+func (d *dashboard) achievementLines() []tuiLine {
+	lines := []tuiLine{}
+	if len(d.stat.Achievements) <= 0 {
+		return lines
+	}
+	category := d.stat.Achievements[d.selected%len(d.stat.Achievements)]
+
+	achieved, total := len(category.Achieved), len(category.Achieved)+len(category.NonAchieved)
+	lines = append(lines, tuiLine{text: fmt.Sprintf("◀ %s ▶  (%d/%d)", category.Name, achieved, total), fg: tuiColorTitle})
+	for _, achievement := range category.Achieved {
+		lines = append(lines, tuiLine{indent: 2, text: "✔ " + achievement.Title, fg: tuiColorLabel})
+		lines = append(lines, tuiLine{indent: 6, text: achievement.Description})
+	}
+	for _, achievement := range category.NonAchieved {
+		lines = append(lines, tuiLine{indent: 2, text: "  " + achievement.Title, fg: tuiColorDim})
+		lines = append(lines, tuiLine{indent: 6, text: achievement.Description, fg: tuiColorDim})
+	}
+	return lines
+}
+
+// numeric value of given stat value for bar charts (durations in seconds)
+func tuiNumber(value string) float64 {
+	if duration, err := stat.ParseDuration(value); err == nil {
+		return duration.Seconds()
+	}
+	if number, err := stat.ParseNumber(value); err == nil {
+		return number
+	}
+	return 0
+}
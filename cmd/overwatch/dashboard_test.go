@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestDashboardHandleKey(t *testing.T) {
+	d := &dashboard{stat: stattest.FixturePcCompetitive.Stat(t)}
+	key := func(ev termbox.Event) {
+		if quit, fetch := d.handleKey(ev); quit || fetch {
+			t.Fatalf("unexpected quit or fetch for %+v", ev)
+		}
+	}
+
+	// selection is bounded by selectable items
+	key(termbox.Event{Key: termbox.KeyArrowLeft})
+	if d.selected != 0 {
+		t.Errorf("selection should not go below 0, got %d", d.selected)
+	}
+	for i := 0; i < d.selectable()+2; i++ {
+		key(termbox.Event{Key: termbox.KeyArrowRight})
+	}
+	if d.selected != d.selectable()-1 {
+		t.Errorf("selection should stop at the last one (%d), got %d", d.selectable()-1, d.selected)
+	}
+
+	// switching modes and views resets selection and scroll
+	key(termbox.Event{Key: termbox.KeyArrowDown})
+	key(termbox.Event{Ch: 'm'})
+	if !d.competitive || d.selected != 0 || d.scroll != 0 {
+		t.Errorf("unexpected state after switching modes: %+v", d)
+	}
+	key(termbox.Event{Key: termbox.KeyTab})
+	if d.view != tuiViewHeroes || d.selectable() != len(d.stat.CompetitivePlay.CareerStats) {
+		t.Errorf("expected career stats view, got %d", d.view)
+	}
+	key(termbox.Event{Ch: '3'})
+	if d.view != tuiViewAchievements || d.selectable() != len(d.stat.Achievements) {
+		t.Errorf("expected achievements view, got %d", d.view)
+	}
+	key(termbox.Event{Key: termbox.KeyTab})
+	if d.view != tuiViewOverview {
+		t.Errorf("views should cycle, got %d", d.view)
+	}
+
+	// refresh only when not fetching
+	if _, fetch := d.handleKey(termbox.Event{Ch: 'r'}); !fetch || !d.fetching {
+		t.Errorf("refresh should start a fetch")
+	}
+	if _, fetch := d.handleKey(termbox.Event{Ch: 'r'}); fetch {
+		t.Errorf("refresh should not start another fetch while fetching")
+	}
+
+	for _, ev := range []termbox.Event{{Ch: 'q'}, {Key: termbox.KeyEsc}, {Key: termbox.KeyCtrlC}} {
+		if quit, _ := d.handleKey(ev); !quit {
+			t.Errorf("%+v should quit", ev)
+		}
+	}
+}
+
+func TestDashboardApply(t *testing.T) {
+	d := &dashboard{language: "ko-kr"}
+
+	// before the first fetch
+	d.startFetch()
+	if lines := d.bodyLines(80); len(lines) != 1 || lines[0].text != "불러오는 중..." || d.status() != "가져오는 중..." {
+		t.Errorf("unexpected body and status while loading: %+v, %s", lines, d.status())
+	}
+
+	fetched := stattest.FixturePcCompetitiveKorean.Stat(t)
+	now := time.Date(2018, 6, 1, 12, 34, 56, 0, time.UTC)
+	d.apply(fetchResult{stat: fetched}, now)
+	if d.fetching || d.status() != "업데이트 12:34:56" || d.errorLine() != "" {
+		t.Errorf("unexpected status after a fetch: %s, %s", d.status(), d.errorLine())
+	}
+
+	// a failed fetch keeps the last stat, and is shown as a status line
+	d.startFetch()
+	d.apply(fetchResult{err: errors.New("timeout")}, now.Add(time.Minute))
+	if d.stat.Name != fetched.Name || !d.updated.Equal(now) {
+		t.Errorf("the last stat should be kept on errors")
+	}
+	if errorLine := d.errorLine(); !strings.Contains(errorLine, "가져오기 오류") || !strings.Contains(errorLine, "timeout") {
+		t.Errorf("unexpected error line: %s", errorLine)
+	}
+	if lines := d.bodyLines(80); len(lines) <= 1 || lines[0].text != "주요 통계" {
+		t.Errorf("the body should show the last stat on errors: %+v", lines)
+	}
+}
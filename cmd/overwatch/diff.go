@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	DiffJsonParamDescription = `print changes in json`
+)
+
+// show changes between two saved stats, or a saved stat and the current one (with -battletag)
+//
+// saved stats can be .json files of stats (eg. from fetch command), or of snapshots (the latest one is used)
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	toJson := flags.Bool("json", false, DiffJsonParamDescription)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: overwatch diff [flags] <old.json> [new.json]\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var old, new stat.Stat
+	var err error
+	switch {
+	case flags.NArg() == 2 && !isFlagSet(flags, "battletag"):
+		if old, err = loadStatFile(flags.Arg(0)); err != nil {
+			return err
+		}
+		if new, err = loadStatFile(flags.Arg(1)); err != nil {
+			return err
+		}
+	case flags.NArg() == 1 && isFlagSet(flags, "battletag"):
+		if old, err = loadStatFile(flags.Arg(0)); err != nil {
+			return err
+		}
+
+		var p *player
+		if p, err = pf.player(flags); err != nil {
+			return err
+		}
+		if new, err = p.fetch(); err != nil {
+			return err
+		}
+	default:
+		return usageError(flags, "Two stat files, or a stat file with -battletag should be given")
+	}
+
+	changes := stat.DiffStats(old, new)
+
+	if *toJson {
+		bytes, err := json.MarshalIndent(changes, "", "\t")
+		if err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+		fmt.Printf("%s\n", string(bytes))
+	} else {
+		if len(changes) == 0 {
+			fmt.Printf("* No changes\n")
+		}
+		for _, change := range changes {
+			fmt.Printf("%s\n", change)
+		}
+	}
+
+	return nil
+}
+
+// load a stat from a .json file of a stat, or of snapshots (the latest one)
+func loadStatFile(path string) (result stat.Stat, err error) {
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(path); err != nil {
+		return result, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(bytes)), "[") {
+		var snapshots []stat.Snapshot
+		if err = json.Unmarshal(bytes, &snapshots); err != nil {
+			return result, fmt.Errorf("Malformed snapshots file %s: %s", path, err)
+		}
+		if len(snapshots) == 0 {
+			return result, fmt.Errorf("No snapshots in %s", path)
+		}
+
+		latest := snapshots[0]
+		for _, snapshot := range snapshots[1:] {
+			if snapshot.Time.After(latest.Time) {
+				latest = snapshot
+			}
+		}
+		return latest.Stat, nil
+	}
+
+	if err = json.Unmarshal(bytes, &result); err != nil {
+		return result, fmt.Errorf("Malformed stat file %s: %s", path, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	// a known profile which has both quick play and competitive play stats
+	DefaultDoctorBattleTag = "meinside#3155"
+	DefaultDoctorRegion    = "kr"
+
+	HtmlFileParamDescription   = `read career page from a saved .html file, instead of fetching it`
+	JsonReportParamDescription = `print report in json`
+)
+
+// check if the parser still works with the official site
+//
+// exits with ExitCodeDrifted when any selector or field doesn't match the expectation
+func runDoctor(args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	pf := addPlayerFlags(flags, DefaultDoctorBattleTag, DefaultDoctorRegion)
+	htmlFile := flags.String("file", "", HtmlFileParamDescription)
+	toJson := flags.Bool("json", false, JsonReportParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	var report stat.LayoutReport
+	if *htmlFile != "" {
+		var file *os.File
+		if file, err = os.Open(*htmlFile); err != nil {
+			return fmt.Errorf("Failed to open career page: %s", err)
+		}
+		defer file.Close()
+
+		report, err = stat.DiagnoseLayoutFromReader(file, p.name, p.number, p.platform, p.region)
+	} else {
+		report, err = stat.DiagnoseLayout(p.name, p.number, p.platform, p.region, p.language)
+	}
+	if err != nil {
+		return fetchError(err, "Failed to load career page")
+	}
+
+	if *toJson {
+		if bytes, err := json.MarshalIndent(report, "", "\t"); err == nil {
+			fmt.Printf("%s\n", string(bytes))
+		} else {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	} else {
+		printLayoutReport(report)
+	}
+
+	if report.Drifted() {
+		return errorWithCode(ExitCodeDrifted, "Layout of the career page has drifted")
+	}
+	return nil
+}
+
+// print layout report in human-readable format
+func printLayoutReport(report stat.LayoutReport) {
+	if report.Url != "" {
+		fmt.Printf("> %s\n\n", report.Url)
+	}
+
+	section := ""
+	for _, check := range report.Checks {
+		if check.Section != section {
+			section = check.Section
+			fmt.Printf("[%s]\n", section)
+		}
+
+		result := "ok"
+		if !check.Ok {
+			result = "DRIFTED"
+		}
+		expected := fmt.Sprintf(">= %d", check.Expected)
+		if check.Exact {
+			expected = fmt.Sprintf("== %d", check.Expected)
+		}
+		fmt.Printf("  %-8s %-28s matched: %4d (expected %s)\n", result, check.Name, check.Matched, expected)
+		if !check.Ok {
+			fmt.Printf("           selector: %s\n", check.Selector)
+		}
+	}
+
+	if report.Private {
+		fmt.Printf("\n* Career profile is private, so only the masthead was checked.\n")
+	}
+
+	if report.ParseError != "" {
+		fmt.Printf("\n* Parse error: %s\n", report.ParseError)
+	}
+
+	if len(report.EmptyFields) > 0 {
+		fmt.Printf("\n* Empty fields:\n")
+		for _, field := range report.EmptyFields {
+			fmt.Printf("  - %s\n", field)
+		}
+	}
+
+	if !report.Drifted() {
+		fmt.Printf("\n* Everything looks fine.\n")
+	}
+}
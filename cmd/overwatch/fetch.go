@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// fetch stat of a player, and print (or save) it in json
+func runFetch(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	withMetrics := flags.Bool("metrics", false, MetricsParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+
+	bytes, err := statToJson(result, *withMetrics)
+	if err != nil {
+		return fmt.Errorf("JSON encode error: %s", err)
+	}
+	return writeOutput(*outFile, bytes, *suppressOutput)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	GoalsFileParamDescription      = `.json file of goals (eg. [{"name": "reach 3000 SR", "metric": "competitive_rank", "target": 3000}, ...]), instead of ones in the config`
+	GoalsListParamDescription      = `list goals (of all players in the config), without checking them`
+	GoalsSnapshotsParamDescription = `.json file of snapshots (eg. saved with 'watch -snapshots') for baselines, trends, and ETAs of goals (the current stat is appended to it)`
+	GoalsTrendParamDescription     = `trends of goals are taken from snapshots in this duration`
+	GoalsJsonParamDescription      = `print goals in json`
+)
+
+// goals of a player, printed in json
+type goalsReport struct {
+	BattleTag string              `json:"battletag"`
+	Goals     []stat.GoalProgress `json:"goals"`
+	Events    []stat.GoalEvent    `json:"events"` // goals reached since the last snapshot
+}
+
+// a listed goal, printed in json
+type listedGoal struct {
+	Player string `json:"player"`
+	stat.Goal
+}
+
+// list goals, or check progresses of goals of a player
+func runGoals(args []string) error {
+	flags := flag.NewFlagSet("goals", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	goalsFile := flags.String("file", "", GoalsFileParamDescription)
+	list := flags.Bool("list", false, GoalsListParamDescription)
+	snapshotsFile := flags.String("snapshots", "", GoalsSnapshotsParamDescription)
+	trend := flags.Duration("trend", stat.DefaultGoalTrendWindow, GoalsTrendParamDescription)
+	toJson := flags.Bool("json", false, GoalsJsonParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
+
+	if *list {
+		return listGoals(*goalsFile, *toJson, *outFile, *suppressOutput)
+	}
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+	goals, err := goalsOf(p, *goalsFile)
+	if err != nil {
+		return err
+	}
+	if len(goals) == 0 {
+		source := configFilepath()
+		if *goalsFile != "" {
+			source = *goalsFile
+		}
+		return errorWithCode(ExitCodeConfig, "No goals of %s in %s", p.battleTag, source)
+	}
+
+	snapshots := []stat.Snapshot{}
+	if *snapshotsFile != "" {
+		if _, err := os.Stat(*snapshotsFile); err == nil {
+			if snapshots, err = loadSnapshots(*snapshotsFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+	current := stat.Snapshot{Time: time.Now(), Stat: result}
+
+	report := goalsReport{
+		BattleTag: p.battleTag,
+		Goals:     stat.EvaluateGoals(goals, current, snapshots, *trend),
+		Events:    []stat.GoalEvent{},
+	}
+	if len(snapshots) > 0 {
+		last := snapshots[0]
+		for _, snapshot := range snapshots {
+			if snapshot.Time.After(last.Time) {
+				last = snapshot
+			}
+		}
+		report.Events = stat.GoalEvents(goals, last, current)
+	}
+
+	if *snapshotsFile != "" {
+		if err := saveJson(*snapshotsFile, append(snapshots, current)); err != nil {
+			return err
+		}
+	}
+
+	var output []byte
+	if *toJson {
+		if output, err = json.MarshalIndent(report, "", "\t"); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	} else {
+		output = []byte(formatGoalsReport(report))
+	}
+	return writeOutput(*outFile, output, *suppressOutput)
+}
+
+// list goals in given .json file, or in the config
+func listGoals(path string, toJson bool, outFile string, quiet bool) error {
+	listed := []listedGoal{}
+	if path != "" {
+		goals, err := loadGoals(path)
+		if err != nil {
+			return err
+		}
+		for _, goal := range goals {
+			listed = append(listed, listedGoal{Goal: goal})
+		}
+	} else {
+		for _, g := range conf.Goals {
+			listed = append(listed, listedGoal{Player: goalPlayer(g), Goal: goalOf(g)})
+		}
+	}
+
+	var output []byte
+	if toJson {
+		var err error
+		if output, err = json.MarshalIndent(listed, "", "\t"); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	} else {
+		var b strings.Builder
+		for _, goal := range listed {
+			definition := goal.Goal
+			definition.Name = ""
+
+			if goal.Player != "" {
+				fmt.Fprintf(&b, "%s: ", goal.Player)
+			}
+			if goal.Name != "" {
+				fmt.Fprintf(&b, "%s (%s)\n", goal.Name, definition)
+			} else {
+				fmt.Fprintf(&b, "%s\n", definition)
+			}
+		}
+		output = []byte(strings.TrimRight(b.String(), "\n"))
+	}
+	return writeOutput(outFile, output, quiet)
+}
+
+// goals of given player: all goals in given .json file, or ones of the player in the config
+func goalsOf(p *player, path string) (goals []stat.Goal, err error) {
+	if path != "" {
+		if goals, err = loadGoals(path); err != nil {
+			return nil, err
+		}
+	} else {
+		goals = []stat.Goal{}
+		for _, g := range conf.Goals {
+			battleTag := goalPlayer(g)
+			if named, exists := conf.Players[battleTag]; exists {
+				battleTag = named.BattleTag
+			}
+			if strings.EqualFold(battleTag, p.battleTag) {
+				goals = append(goals, goalOf(g))
+			}
+		}
+	}
+
+	for _, goal := range goals {
+		if err := goal.Validate(); err != nil {
+			return nil, errorWithCode(ExitCodeConfig, "Malformed goal: %s", err)
+		}
+	}
+	return goals, nil
+}
+
+// load goals from given .json file
+func loadGoals(path string) (goals []stat.Goal, err error) {
+	if bytes, err := ioutil.ReadFile(path); err == nil {
+		if err := json.Unmarshal(bytes, &goals); err != nil {
+			return nil, errorWithCode(ExitCodeConfig, "Malformed goals file %s: %s", path, err)
+		}
+	} else {
+		return nil, fmt.Errorf("Failed to read goals file: %s", err)
+	}
+	return goals, nil
+}
+
+// player of given goal in the config (name of a player, or a battle tag)
+func goalPlayer(g goalConfig) string {
+	if g.Player != "" {
+		return g.Player
+	}
+	return conf.BattleTag
+}
+
+func goalOf(g goalConfig) stat.Goal {
+	return stat.Goal{
+		Name:   g.Name,
+		Metric: g.Metric,
+		Hero:   g.Hero,
+		Role:   g.Role,
+		Mode:   g.Mode,
+		Target: g.Target,
+	}
+}
+
+// format progresses of goals in human-readable format
+func formatGoalsReport(report goalsReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Goals of %s:\n", report.BattleTag)
+	for _, progress := range report.Goals {
+		if !progress.Available {
+			fmt.Fprintf(&b, "  [?] %s: not available\n", progress.Goal)
+			continue
+		}
+
+		mark := " "
+		if progress.Reached {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "  [%s] %s: %s / %s (%.0f%%)", mark, progress.Goal, formatGoalValue(progress.Current), formatGoalValue(progress.Goal.Target), progress.Progress)
+		if progress.ReachedAt != nil {
+			fmt.Fprintf(&b, ", since %s", progress.ReachedAt.Format("2006-01-02"))
+		}
+		if progress.Trend != 0 && !progress.Reached {
+			fmt.Fprintf(&b, ", %+.2f/day", progress.Trend)
+			if progress.Eta != nil {
+				fmt.Fprintf(&b, ", ETA %s", progress.Eta.Format("2006-01-02"))
+			}
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	if len(report.Events) > 0 {
+		fmt.Fprintf(&b, "\n")
+		for _, event := range report.Events {
+			fmt.Fprintf(&b, "%s\n", event)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// format a value of goals (eg. "3000", "52.3")
+func formatGoalValue(value float64) string {
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d", int64(value))
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// render stat of a player to html, and print (or save) it
+func runHtml(args []string) error {
+	flags := flag.NewFlagSet("html", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	inline := flags.Bool("inline", conf.Output.HtmlInline, HtmlInlineParamDescription)
+	templateFile := flags.String("template", "", TemplateParamDescription)
+	templateDir := flags.String("template-dir", "", TemplateDirParamDescription)
+	snapshotsFile := flags.String("snapshots", "", SnapshotsFileParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+
+	html, err := renderHtml(result, *templateFile, *templateDir, *snapshotsFile)
+	if err != nil {
+		return fmt.Errorf("HTML encode error: %s", err)
+	}
+	if *inline {
+		html = stat.InlineHtmlAssets(html)
+	}
+	return writeOutput(*outFile, []byte(html), *suppressOutput)
+}
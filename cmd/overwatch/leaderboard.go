@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/meinside/overwatch-go/metrics"
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	LeaderboardRosterParamDescription    = `name of a roster in the config, whose members are fetched and ranked`
+	LeaderboardHistoryParamDescription   = `rank players of the latest snapshots in a .json file of stats over time (eg. saved with 'roster -history'), instead of fetching a roster`
+	LeaderboardMinGamesParamDescription  = `players with fewer games played (in the mode of the path, of its hero) than this are unranked`
+	LeaderboardAscendingParamDescription = `lower values rank higher (eg. for deaths)`
+	LeaderboardTableParamDescription     = `print leaderboards as text tables, not json`
+	LeaderboardHtmlParamDescription      = `print html, not json`
+)
+
+// rank players of a roster (or of saved stats) by values of given paths
+func runLeaderboard(args []string) error {
+	flags := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	pf := addPlayerOptionFlags(flags, conf.Region)
+	rosterName := flags.String("roster", "", LeaderboardRosterParamDescription)
+	rosterFile := flags.String("file", "", RosterFileParamDescription)
+	historyFile := flags.String("history", "", LeaderboardHistoryParamDescription)
+	concurrency := flags.Int("concurrency", stat.DefaultRosterConcurrency, RosterConcurrencyParamDescription)
+	minGames := flags.Float64("min-games", 0, LeaderboardMinGamesParamDescription)
+	ascending := flags.Bool("ascending", false, LeaderboardAscendingParamDescription)
+	toTable := flags.Bool("table", false, LeaderboardTableParamDescription)
+	toHtml := flags.Bool("html", false, LeaderboardHtmlParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), `Usage: overwatch leaderboard [flags] <path> [<path> ...]
+
+Paths:
+  competitive_rank, level, ...                           (values of stats, as in 'diff')
+  competitive_play.metrics[Ana][eliminations_per_10m]    (derived metrics of a hero, or of "all" heroes)
+  quick_play.values[all][games_won]                      (values of career stats)
+
+`)
+		if names := conf.rosterNames(); len(names) > 0 {
+			fmt.Fprintf(flags.Output(), "Rosters (in %s):\n  %s\n\n", configFilepath(), strings.Join(names, ", "))
+		}
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	stat.Verbose = *pf.verbose
+
+	if flags.NArg() == 0 {
+		return usageError(flags, "Paths of leaderboards were not given")
+	}
+
+	var players []metrics.LeaderboardPlayer
+	if *rosterName != "" || *rosterFile != "" {
+		roster, err := loadRoster(flags, *rosterFile, *rosterName)
+		if err != nil {
+			return err
+		}
+		results, err := fetchRoster(roster, pf, *concurrency)
+		if err != nil {
+			return err
+		}
+		players = metrics.PlayersOfRoster(results)
+	} else if *historyFile != "" {
+		history, err := loadRosterHistory(*historyFile)
+		if err != nil {
+			return err
+		}
+		players = metrics.PlayersOfHistory(history)
+	} else {
+		return usageError(flags, "Roster (-roster or -file) or history (-history) was not given")
+	}
+
+	boards, err := metrics.NewLeaderboards(players, flags.Args(), metrics.LeaderboardOptions{
+		MinGames:  *minGames,
+		Ascending: *ascending,
+	})
+	if err != nil {
+		return errorWithCode(ExitCodeUsage, "%s", err)
+	}
+
+	var output []byte
+	if *toHtml {
+		html, err := metrics.RenderLeaderboardsToHtml(boards, metrics.SampleLeaderboardHtmlTemplate)
+		if err != nil {
+			return fmt.Errorf("HTML encode error: %s", err)
+		}
+		output = []byte(html)
+	} else if *toTable {
+		output = []byte(formatLeaderboards(boards))
+	} else {
+		if output, err = json.MarshalIndent(boards, "", "\t"); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	}
+	return writeOutput(*outFile, output, *suppressOutput)
+}
+
+// format leaderboards as text tables
+func formatLeaderboards(boards []metrics.Leaderboard) string {
+	var b strings.Builder
+
+	for i, board := range boards {
+		if i > 0 {
+			fmt.Fprintf(&b, "\n")
+		}
+		fmt.Fprintf(&b, "%s\n", board.Path)
+
+		width := len("player")
+		for _, entry := range board.Entries {
+			if len(entry.Name) > width {
+				width = len(entry.Name)
+			}
+		}
+		fmt.Fprintf(&b, "  %-4s %-*s %10s %7s %10s\n", "#", width, "player", "value", "games", "percentile")
+		for _, entry := range board.Entries {
+			rank := fmt.Sprintf("%d", entry.Rank)
+			if entry.Tied {
+				rank += "="
+			}
+			fmt.Fprintf(&b, "  %-4s %-*s %10s %7s %10.1f\n", rank, width, entry.Name, metrics.FormatValue(entry.Value), metrics.FormatValue(entry.Games), entry.Percentile)
+		}
+
+		if len(board.Unranked) > 0 {
+			unranked := []string{}
+			for _, player := range board.Unranked {
+				unranked = append(unranked, fmt.Sprintf("%s (%s)", player.Name, player.Reason))
+			}
+			fmt.Fprintf(&b, "  unranked: %s\n", strings.Join(unranked, ", "))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -1,11 +1,10 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
-	"strconv"
+	"html/template"
+	"os"
 	"strings"
 
 	"github.com/meinside/overwatch-go/stat"
@@ -22,96 +21,213 @@ const (
 	VerboseParamDescription        = `show verbose messages for debugging purpose`
 	BattleTagParamDescription      = `battle tag, eg. "meinside#3155"`
 	ToHtmlParamDescription         = `print html, not json`
+	HtmlInlineParamDescription     = `inline images and fonts in html as data uris, so it can be viewed offline`
 	OutFileParamDescription        = `save result to a file`
-	BannerFileParamDescription     = `create a banner file in .png format`
+	BannerFileParamDescription     = `create a banner file (in the format given with -banner-format)`
+	BannerFormatParamDescription   = `format of the banner file: "png", "svg", "jpeg", or "gif" (default: by its extension, or "png")`
+	BannerQualityParamDescription  = `quality of .jpeg banners (1 ~ 100)`
+	BannerAnimatedDescription      = `create an animated .gif banner which cycles through profile, rank, top heroes, and featured stats`
+	BannerDelayParamDescription    = `delay between panels of animated .gif banners`
+	BannerLinkImagesDescription    = `link images with their urls in .svg banners, instead of embedding them`
+	BannerEndorsementDescription   = `draw endorsement level on the banner`
+	BannerThemeParamDescription    = `theme of the banner: "default", "signature", "square", or "twitter"`
+	SnapshotsFileParamDescription  = `embed charts of snapshots in html, from a .json file of snapshots (eg. [{"time": "2018-06-01T00:00:00Z", "stat": {...}}, ...])`
+	TemplateParamDescription       = `template file (or directory with index.tmpl and partials) for html, instead of the sample one`
+	TemplateDirParamDescription    = `directory of partial templates (*.tmpl) for -template`
+	HeroBannerFileParamDescription = `create a .png card of the hero given with -hero`
+	HeroParamDescription           = `name of the hero for -hero-banner, eg. "Ana"`
 	SuppressOutputParamDescription = `be quiet, no output on stdout`
+	MetricsParamDescription        = `include derived metrics (eg. KDA, per-10-minute averages, and win rate) in json, with "metrics" key`
 )
 
+// formats of banner files
+const (
+	BannerFormatPng  = "png"
+	BannerFormatSvg  = "svg"
+	BannerFormatJpeg = "jpeg"
+	BannerFormatGif  = "gif"
+)
+
+// a subcommand
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+// subcommands, in the order of usage
+var commands = []command{
+	{"fetch", "fetch stat of a player, in json", runFetch},
+	{"banner", "create a banner (or a card of a hero) of a player", runBanner},
+	{"html", "render stat of a player to html", runHtml},
+	{"diff", "show changes between saved stats (or a saved one and the current one)", runDiff},
+	{"achievements", "show completion of achievements, their unlock timeline, and ones closest to unlock", runAchievements},
+	{"goals", "list goals of players, or check their progresses with trends and ETAs", runGoals},
+	{"compare", "compare stats of two or more players side by side", runCompare},
+	{"watch", "poll stat of a player, and print changes (optionally saving snapshots)", runWatch},
+	{"serve", "serve html, json, banner, and overlay of a player over http", runServe},
+	{"overlay", "serve a browser-source overlay (eg. for OBS) of a player", runOverlay},
+	{"roster", "fetch stats of a team roster, and report ranks, roles, hero pool, and inactive members", runRoster},
+	{"leaderboard", "rank players of a roster (or of saved stats) by stats or derived metrics, with ties and percentiles", runLeaderboard},
+	{"tui", "show an interactive dashboard of a player in the terminal", runTui},
+	{"doctor", "check if the parser still works with the official site", runDoctor},
+}
+
 func main() {
-	// parse flags
-	platform := flag.String("platform", DefaultPlatform, PlatformParamDescription)
-	region := flag.String("region", DefaultRegion, RegionParamDescription)
-	language := flag.String("language", DefaultLanguage, LanguageParamDescription)
-	verbose := flag.Bool("verbose", false, VerboseParamDescription)
-	battleTag := flag.String("battletag", "", BattleTagParamDescription)
-	toHtml := flag.Bool("html", false, ToHtmlParamDescription)
-	outFile := flag.String("out", "", OutFileParamDescription)
-	bannerFile := flag.String("banner", "", BannerFileParamDescription)
-	suppressOutput := flag.Bool("quiet", false, SuppressOutputParamDescription)
-	flag.Parse()
-
-	if *battleTag == "" {
-		fmt.Printf("* Battle Tag was not given\n")
-
-		flag.PrintDefaults()
-	} else {
-		stat.Verbose = *verbose
+	// usage is printed even with a malformed config file (with a warning)
+	if len(os.Args) < 2 {
+		printUsageWithConfig()
+		os.Exit(ExitCodeUsage)
+	}
+	switch os.Args[1] {
+	case "help", "-h", "-help", "--help":
+		printUsageWithConfig()
+		os.Exit(ExitCodeOk)
+	}
 
-		var battleTags []string
-		var battleTagNumber int
-		if strings.EqualFold(*platform, stat.PlatformPc) { // PC
-			battleTags = strings.Split(*battleTag, "#")
+	if err := loadConfig(); err != nil {
+		exit(err)
+	}
 
-			if len(battleTags) != 2 {
-				fmt.Printf("* Malformed battle tag: %s\n", *battleTag)
-				return
-			}
+	name, args := os.Args[1], os.Args[2:]
 
-			var err error
-			if battleTagNumber, err = strconv.Atoi(battleTags[1]); err != nil {
-				fmt.Printf("* Malformed battle tag: %s (%s)\n", *battleTag, err)
-				return
-			}
-		} else { // Console (XBL, PSN)
-			battleTags = []string{*battleTag}
-			battleTagNumber = 0 // XXX - not needed
-			*region = ""        // XXX - not needed
+	// XXX - flags without a subcommand (deprecated)
+	if strings.HasPrefix(name, "-") {
+		exit(runLegacy(os.Args[1:]))
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			exit(c.run(args))
 		}
+	}
 
-		if result, err := stat.FetchStat(battleTags[0], int(battleTagNumber), *platform, *region, *language); err == nil {
-			// print or save result
-			if *toHtml {
-				if html, err := stat.RenderStatToHtml(result, stat.SampleHtmlTemplate); err == nil {
-					if *outFile != "" {
-						if err := saveToFile(*outFile, []byte(html)); err != nil {
-							fmt.Printf("* Failed to save %s: %s\n", *outFile, err)
-						}
-					} else {
-						if !*suppressOutput {
-							fmt.Printf("%s\n", html) // print to stdout
-						}
-					}
-				} else {
-					fmt.Printf("* HTML encode error: %s\n", err)
-				}
-			} else {
-				if bytes, err := json.MarshalIndent(result, "", "\t"); err == nil {
-					if *outFile != "" {
-						if err := saveToFile(*outFile, bytes); err != nil {
-							fmt.Printf("* Failed to save %s: %s\n", *outFile, err)
-						}
-					} else {
-						if !*suppressOutput {
-							fmt.Printf("%s\n", string(bytes)) // print to stdout
-						}
-					}
-				} else {
-					fmt.Printf("* JSON encode error: %s\n", err)
-				}
-			}
+	printUsage()
+	exit(errorWithCode(ExitCodeUsage, "Unknown command: %s", name))
+}
+
+// load the config (warning on errors), then print usage of commands to stderr
+func printUsageWithConfig() {
+	if err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "* %s\n\n", err)
+	}
+	printUsage()
+}
+
+// print usage of commands to stderr
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: overwatch <command> [flags] [battle tag, or name of a player in the config]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.description)
+	}
+	if names := conf.playerNames(); len(names) > 0 {
+		fmt.Fprintf(os.Stderr, "\nPlayers (in %s):\n  %s\n", configFilepath(), strings.Join(names, ", "))
+	}
+	if names := conf.rosterNames(); len(names) > 0 {
+		fmt.Fprintf(os.Stderr, "\nRosters (in %s):\n  %s\n", configFilepath(), strings.Join(names, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'overwatch <command> -h' for flags of each command.\n")
+}
+
+// run with flags of older versions, which had no subcommands
+func runLegacy(args []string) error {
+	flags := flag.NewFlagSet("overwatch", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	toHtml := flags.Bool("html", false, ToHtmlParamDescription)
+	htmlInline := flags.Bool("html-inline", conf.Output.HtmlInline, HtmlInlineParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	bannerFile := flags.String("banner", "", BannerFileParamDescription)
+	bf := addBannerFlags(flags, "banner-")
+	templateFile := flags.String("template", "", TemplateParamDescription)
+	templateDir := flags.String("template-dir", "", TemplateDirParamDescription)
+	snapshotsFile := flags.String("snapshots", "", SnapshotsFileParamDescription)
+	heroBannerFile := flags.String("hero-banner", "", HeroBannerFileParamDescription)
+	hero := flags.String("hero", "", HeroParamDescription)
+	withMetrics := flags.Bool("metrics", false, MetricsParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Parse(args)
 
-			// if requested, create a banner file
-			if *bannerFile != "" {
-				if stat.RenderStatToPngFile(result, nil, nil, *bannerFile); err != nil {
-					fmt.Printf("* Failed to create a banner file: %s\n", err)
-				}
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	var spec stat.BannerSpec
+	var format string
+	if *bannerFile != "" {
+		if spec, format, err = bf.spec(p, *bannerFile); err != nil {
+			return err
+		}
+	}
+	if *heroBannerFile != "" && *hero == "" {
+		return errorWithCode(ExitCodeUsage, "Hero was not given for the hero banner")
+	}
+
+	result, err := p.fetch()
+	if err != nil {
+		return err
+	}
+
+	// print or save result
+	var output []byte
+	if *toHtml {
+		if html, err := renderHtml(result, *templateFile, *templateDir, *snapshotsFile); err == nil {
+			if *htmlInline {
+				html = stat.InlineHtmlAssets(html)
 			}
+			output = []byte(html)
 		} else {
-			fmt.Printf("* Fetch error: %s\n", err)
+			return fmt.Errorf("HTML encode error: %s", err)
+		}
+	} else {
+		if output, err = statToJson(result, *withMetrics); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	}
+	if err := writeOutput(*outFile, output, *suppressOutput); err != nil {
+		return err
+	}
+
+	// if requested, create a banner file
+	if *bannerFile != "" {
+		if err := bf.render(result, spec, format, *bannerFile); err != nil {
+			return err
+		}
+	}
+
+	// if requested, create a hero banner file
+	if *heroBannerFile != "" {
+		if err := stat.RenderHeroBannerToPngFile(result, *hero, stat.HeroBannerOptions{}, *heroBannerFile); err != nil {
+			return fmt.Errorf("Failed to create a hero banner file: %s", err)
 		}
 	}
+
+	return nil
 }
 
-func saveToFile(filepath string, bytes []byte) error {
-	return ioutil.WriteFile(filepath, bytes, 0640)
+// render stat to html with given template files (or the sample one), and charts of snapshots in given .json file (if any)
+func renderHtml(result stat.Stat, templateFile, templateDir, snapshotsFile string) (string, error) {
+	var tmpl *template.Template
+	var err error
+	if templateFile != "" {
+		partialDirs := []string{}
+		if templateDir != "" {
+			partialDirs = append(partialDirs, templateDir)
+		}
+		tmpl, err = stat.LoadHtmlTemplate(templateFile, partialDirs...)
+	} else {
+		tmpl, err = stat.ParseHtmlTemplate(stat.SampleHtmlTemplate)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var snapshots []stat.Snapshot
+	if snapshotsFile != "" {
+		if snapshots, err = loadSnapshots(snapshotsFile); err != nil {
+			return "", err
+		}
+	}
+
+	return stat.RenderStatToHtmlWithTemplate(result, snapshots, tmpl)
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	DefaultOverlayAddr = "localhost:8080"
+
+	OverlayAddrParamDescription     = `address to serve the overlay at (open http://<addr>/ as a browser source)`
+	OverlayLayoutParamDescription   = `layout of the overlay: "bar", "box", or "minimal"`
+	OverlayIntervalParamDescription = `interval of polling stats`
+)
+
+// serve a browser-source overlay (eg. for OBS) of a player, which is updated whenever the stat changes
+func runOverlay(args []string) error {
+	flags := flag.NewFlagSet("overlay", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	addr := flags.String("addr", DefaultOverlayAddr, OverlayAddrParamDescription)
+	layout := flags.String("layout", stat.OverlayLayoutBar, OverlayLayoutParamDescription)
+	interval := flags.Duration("interval", stat.DefaultOverlayPollInterval, OverlayIntervalParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	overlays := stat.NewOverlayServer(p.name, p.number, p.platform, p.region, p.language, *layout)
+	overlays.Interval = *interval
+	overlays.Fetch = p.fetch
+
+	fmt.Fprintf(os.Stderr, "> Serving overlay of %s at: http://%s/\n", p.battleTag, *addr)
+	if err := overlays.ListenAndServe(*addr); err != nil {
+		return fmt.Errorf("Failed to serve overlay: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	RosterFileParamDescription        = `.json file of a roster (eg. {"name": "team", "members": [{"battletag": "meinside#3155", "role": "tank"}, ...]}), instead of one in the config`
+	RosterHistoryParamDescription     = `.json file of stats of members over time, for checking inactive members (a snapshot of each member is appended to it, and unchanged ones are compacted)`
+	RosterInactiveParamDescription    = `members are inactive when their stats haven't changed for this duration (needs -history)`
+	RosterConcurrencyParamDescription = `number of members fetched at the same time`
+	RosterHtmlParamDescription        = `print html, not json`
+	RosterPngParamDescription         = `create a .png image of the team report`
+)
+
+// fetch stats of all members of a roster, and print (or save) a team report of them
+func runRoster(args []string) error {
+	flags := flag.NewFlagSet("roster", flag.ExitOnError)
+	pf := addPlayerOptionFlags(flags, conf.Region)
+	rosterFile := flags.String("file", "", RosterFileParamDescription)
+	historyFile := flags.String("history", "", RosterHistoryParamDescription)
+	inactiveAfter := flags.Duration("inactive", stat.DefaultInactiveAfter, RosterInactiveParamDescription)
+	concurrency := flags.Int("concurrency", stat.DefaultRosterConcurrency, RosterConcurrencyParamDescription)
+	toHtml := flags.Bool("html", false, RosterHtmlParamDescription)
+	inline := flags.Bool("inline", conf.Output.HtmlInline, HtmlInlineParamDescription)
+	pngFile := flags.String("png", "", RosterPngParamDescription)
+	outFile := flags.String("out", "", OutFileParamDescription)
+	suppressOutput := flags.Bool("quiet", conf.Output.Quiet, SuppressOutputParamDescription)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: overwatch roster [flags] <name of a roster in the config>\n\n")
+		if names := conf.rosterNames(); len(names) > 0 {
+			fmt.Fprintf(flags.Output(), "Rosters (in %s):\n  %s\n\n", configFilepath(), strings.Join(names, ", "))
+		}
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	stat.Verbose = *pf.verbose
+
+	roster, err := loadRoster(flags, *rosterFile, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	history := map[string][]stat.Snapshot{}
+	if *historyFile != "" {
+		if history, err = loadRosterHistory(*historyFile); err != nil {
+			return err
+		}
+	}
+
+	results, err := fetchRoster(roster, pf, *concurrency)
+	if err != nil {
+		return err
+	}
+
+	report := stat.NewTeamReport(roster.Name, results, history, *inactiveAfter)
+
+	// append snapshots of fetched members to the history (unchanged and old ones are dropped)
+	if *historyFile != "" {
+		for _, result := range results {
+			if result.Err == nil {
+				snapshots := append(history[result.Member.BattleTag], stat.Snapshot{Time: result.FetchedAt, Stat: result.Stat})
+				history[result.Member.BattleTag] = stat.CompactSnapshots(snapshots, stat.RosterHistorySize)
+			}
+		}
+		if err := saveJson(*historyFile, history); err != nil {
+			return err
+		}
+	}
+
+	if *pngFile != "" {
+		if err := stat.RenderTeamReportToPngFile(report, nil, *pngFile); err != nil {
+			return fmt.Errorf("Failed to create a .png file: %s", err)
+		}
+	}
+
+	var output []byte
+	if *toHtml {
+		html, err := stat.RenderTeamReportToHtml(report, stat.SampleTeamHtmlTemplate)
+		if err != nil {
+			return fmt.Errorf("HTML encode error: %s", err)
+		}
+		if *inline {
+			html = stat.InlineHtmlAssets(html)
+		}
+		output = []byte(html)
+	} else {
+		if output, err = json.MarshalIndent(report, "", "\t"); err != nil {
+			return fmt.Errorf("JSON encode error: %s", err)
+		}
+	}
+	return writeOutput(*outFile, output, *suppressOutput)
+}
+
+// fetch stats of all members of given roster (fails only when all members failed)
+func fetchRoster(roster stat.Roster, pf playerFlags, concurrency int) ([]stat.RosterStat, error) {
+	if len(roster.Members) == 0 {
+		return nil, errorWithCode(ExitCodeConfig, "Roster '%s' has no members", roster.Name)
+	}
+	for i, member := range roster.Members {
+		var err error
+		if roster.Members[i], err = resolveRosterMember(member, *pf.platform, *pf.region, *pf.language); err != nil {
+			return nil, err
+		}
+	}
+
+	results := stat.FetchRoster(roster, concurrency)
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "* Failed to fetch stat of %s: %s\n", result.Member.BattleTag, result.Err)
+			failed++
+		} else if result.Stat.Private {
+			fmt.Fprintf(os.Stderr, "* Career profile of %s is private, so only public information is available.\n", result.Member.BattleTag)
+		}
+	}
+	if failed == len(results) {
+		return nil, fetchError(results[0].Err, "Failed to fetch stats of roster '%s'", roster.Name)
+	}
+	return results, nil
+}
+
+// load a roster from given .json file, or from the config by given name
+func loadRoster(flags *flag.FlagSet, path, name string) (roster stat.Roster, err error) {
+	if path != "" {
+		if bytes, err := ioutil.ReadFile(path); err == nil {
+			if err := json.Unmarshal(bytes, &roster); err != nil {
+				return roster, errorWithCode(ExitCodeConfig, "Malformed roster file %s: %s", path, err)
+			}
+		} else {
+			return roster, fmt.Errorf("Failed to read roster file: %s", err)
+		}
+		if roster.Name == "" {
+			roster.Name = path
+		}
+		return roster, nil
+	}
+
+	if name == "" {
+		return roster, usageError(flags, "Name of a roster (or -file) was not given")
+	}
+	named, exists := conf.Rosters[name]
+	if !exists {
+		return roster, usageError(flags, "No such roster in the config: %s", name)
+	}
+
+	roster.Name = named.Name
+	if roster.Name == "" {
+		roster.Name = name
+	}
+	for _, m := range named.Members {
+		member := stat.RosterMember{
+			Name:      m.Name,
+			BattleTag: m.BattleTag,
+			Platform:  m.Platform,
+			Region:    m.Region,
+			Language:  m.Language,
+			Role:      m.Role,
+		}
+		if m.Player != "" {
+			p, exists := conf.Players[m.Player]
+			if !exists {
+				return roster, errorWithCode(ExitCodeConfig, "No such player in the config: %s (of roster '%s')", m.Player, name)
+			}
+
+			if member.Name == "" {
+				member.Name = m.Player
+			}
+			for _, v := range []struct {
+				dst   *string
+				value string
+			}{
+				{&member.BattleTag, p.BattleTag},
+				{&member.Platform, p.Platform},
+				{&member.Region, p.Region},
+				{&member.Language, p.Language},
+			} {
+				if *v.dst == "" {
+					*v.dst = v.value
+				}
+			}
+		}
+		roster.Members = append(roster.Members, member)
+	}
+	return roster, nil
+}
+
+// fill empty values of given roster member with the defaults, and check its battle tag
+func resolveRosterMember(member stat.RosterMember, platform, region, language string) (stat.RosterMember, error) {
+	if member.BattleTag == "" {
+		return member, errorWithCode(ExitCodeConfig, "Battle tag of a roster member '%s' is missing", member.Name)
+	}
+	if member.Platform == "" {
+		member.Platform = platform
+	}
+	if member.Region == "" {
+		member.Region = region
+	}
+	if member.Language == "" {
+		member.Language = language
+	}
+
+	if _, _, err := stat.ParseBattleTag(member.BattleTag, member.Platform); err != nil {
+		return member, errorWithCode(ExitCodeConfig, "%s", err)
+	}
+	return member, nil
+}
+
+// load snapshots of roster members from given .json file (empty if it doesn't exist)
+func loadRosterHistory(path string) (map[string][]stat.Snapshot, error) {
+	history := map[string][]stat.Snapshot{}
+	if bytes, err := ioutil.ReadFile(path); err == nil {
+		if err := json.Unmarshal(bytes, &history); err != nil {
+			return nil, fmt.Errorf("Malformed history file %s: %s", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return history, nil
+}
+
+// save given value to a .json file
+func saveJson(path string, v interface{}) error {
+	if bytes, err := json.MarshalIndent(v, "", "\t"); err == nil {
+		if err := saveToFile(path, bytes); err != nil {
+			return fmt.Errorf("Failed to save %s: %s", path, err)
+		}
+		return nil
+	} else {
+		return fmt.Errorf("JSON encode error: %s", err)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	ServeAddrParamDescription = `address to serve at (html: /, json: /stat.json, banner: /banner.png, overlay: /overlay/)`
+)
+
+// serve html, json, banner, and overlay of a player, with stats polled periodically
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	addr := flags.String("addr", DefaultOverlayAddr, ServeAddrParamDescription)
+	interval := flags.Duration("interval", stat.DefaultOverlayPollInterval, OverlayIntervalParamDescription)
+	layout := flags.String("layout", stat.OverlayLayoutBar, OverlayLayoutParamDescription)
+	templateFile := flags.String("template", "", TemplateParamDescription)
+	templateDir := flags.String("template-dir", "", TemplateDirParamDescription)
+	bannerTheme := flags.String("theme", conf.BannerTheme, BannerThemeParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	spec, err := stat.BannerTheme(p.bannerThemeOr(flags, "theme", *bannerTheme))
+	if err != nil {
+		return errorWithCode(ExitCodeUsage, "%s", err)
+	}
+
+	// stats are polled by the overlay server, and shared with other handlers
+	overlays := stat.NewOverlayServer(p.name, p.number, p.platform, p.region, p.language, *layout)
+	overlays.Interval = *interval
+	overlays.Fetch = p.fetch
+
+	// the first fetch should succeed
+	if _, err := overlays.Poll(); err != nil {
+		return err
+	}
+
+	// handler which needs a fetched stat
+	withStat := func(handle func(w http.ResponseWriter, s stat.Stat) error) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if s, fetched := overlays.Stat(); fetched {
+				if err := handle(w, s); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			} else {
+				http.Error(w, "stat is not fetched yet", http.StatusServiceUnavailable)
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/overlay/", http.StripPrefix("/overlay", overlays))
+	mux.HandleFunc("/stat.json", withStat(func(w http.ResponseWriter, s stat.Stat) error {
+		bytes, err := statToJson(s, true)
+		if err == nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write(bytes)
+		}
+		return err
+	}))
+	mux.HandleFunc("/banner.png", withStat(func(w http.ResponseWriter, s stat.Stat) error {
+		bytes, err := stat.RenderStatToPngBytesWithSpec(s, spec, nil, nil)
+		if err == nil {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(bytes)
+		}
+		return err
+	}))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		withStat(func(w http.ResponseWriter, s stat.Stat) error {
+			html, err := renderHtml(s, *templateFile, *templateDir, "")
+			if err == nil {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte(html))
+			}
+			return err
+		})(w, r)
+	})
+
+	// (polled once already, so start polling after an interval)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-time.After(*interval):
+			overlays.Run(stop)
+		case <-stop:
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "> Serving %s at: http://%s/\n", p.battleTag, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		return fmt.Errorf("Failed to serve: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	DefaultTuiRefreshInterval = 5 * time.Minute
+
+	TuiRefreshParamDescription = `interval of refreshing stats`
+)
+
+// show an interactive dashboard of a player in the terminal
+func runTui(args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	refresh := flags.Duration("refresh", DefaultTuiRefreshInterval, TuiRefreshParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	if err := termbox.Init(); err != nil {
+		return fmt.Errorf("Failed to initialize terminal: %s", err)
+	}
+	defer termbox.Close()
+
+	// fetch in background
+	results := make(chan fetchResult)
+	fetch := func() {
+		s, err := stat.FetchStat(p.name, p.number, p.platform, p.region, p.language)
+		if err == stat.ErrPrivateProfile {
+			err = nil
+		}
+		results <- fetchResult{stat: s, err: err}
+	}
+
+	// terminal events
+	events := make(chan termbox.Event)
+	go func() {
+		for {
+			events <- termbox.PollEvent()
+		}
+	}()
+
+	ticker := time.NewTicker(*refresh)
+	defer ticker.Stop()
+
+	d := &dashboard{battleTag: p.battleTag, language: p.language}
+	if d.startFetch() {
+		go fetch()
+	}
+
+	for {
+		tuiDraw(d)
+
+		select {
+		case ev := <-events:
+			switch ev.Type {
+			case termbox.EventKey:
+				quit, refetch := d.handleKey(ev)
+				if quit {
+					return nil
+				}
+				if refetch {
+					go fetch()
+				}
+			case termbox.EventError:
+				return nil
+			}
+		case result := <-results:
+			d.apply(result, time.Now())
+		case <-ticker.C:
+			if d.startFetch() {
+				go fetch()
+			}
+		}
+	}
+}
+
+// draw the whole dashboard
+func tuiDraw(d *dashboard) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	width, height := termbox.Size()
+
+	// header: player info
+	y := 0
+	x := tuiPrint(0, y, tuiColorTitle, termbox.ColorDefault, d.battleTag)
+	if d.stat.Name != "" {
+		x = tuiPrint(x+2, y, tuiColorText, termbox.ColorDefault, fmt.Sprintf("%s %d", d.localize("Level"), d.stat.Level))
+		if d.stat.CompetitiveRank != stat.NoCompetitiveRank {
+			x = tuiPrint(x+2, y, tuiColorText, termbox.ColorDefault, fmt.Sprintf("%s %d", d.localize("Competitive Rank"), d.stat.CompetitiveRank))
+		}
+		if d.stat.EndorsementLevel != stat.NoEndorsementLevel {
+			x = tuiPrint(x+2, y, tuiColorText, termbox.ColorDefault, fmt.Sprintf("%s %d", d.localize("Endorsement Level"), d.stat.EndorsementLevel))
+		}
+	}
+	status := d.status()
+	tuiPrint(width-runewidth.StringWidth(status), y, tuiColorDim, termbox.ColorDefault, status)
+	y++
+	if d.stat.Detail != "" {
+		tuiPrint(0, y, tuiColorDim, termbox.ColorDefault, d.stat.Detail)
+	}
+	y++
+
+	// tabs: modes and views
+	y++
+	x = 0
+	for i, mode := range []string{"Quick Play", "Competitive Play"} {
+		x = tuiTab(x, y, d.localize(mode), d.competitive == (i == 1))
+	}
+	x += 2
+	for i, view := range tuiViewNames {
+		x = tuiTab(x, y, d.localize(view), d.view == i)
+	}
+	y += 2
+
+	// footer: error of the last fetch, and help
+	bottom := height - 1
+	if errorLine := d.errorLine(); errorLine != "" {
+		bottom--
+		tuiPrint(0, bottom, tuiColorError, termbox.ColorDefault, errorLine)
+	}
+	tuiPrint(0, height-1, tuiColorDim, termbox.ColorDefault, tuiHelp)
+
+	// body (scrolled)
+	body := d.bodyLines(width)
+	visible := bottom - y
+	if maxScroll := len(body) - visible; d.scroll > maxScroll {
+		if d.scroll = maxScroll; d.scroll < 0 {
+			d.scroll = 0
+		}
+	}
+	for i := d.scroll; i < len(body) && y < bottom; i++ {
+		line := body[i]
+		x := tuiPrint(line.indent, y, line.fg, termbox.ColorDefault, line.text)
+		if line.bar > 0 {
+			for j := 0; j < line.bar; j++ {
+				termbox.SetCell(x+1+j, y, '█', tuiColorBar, termbox.ColorDefault)
+			}
+			tuiPrint(x+2+line.bar, y, tuiColorText, termbox.ColorDefault, line.value)
+		} else if line.value != "" {
+			tuiPrint(line.valueX, y, tuiColorText, termbox.ColorDefault, line.value)
+		}
+		y++
+	}
+
+	termbox.Flush()
+}
+
+// print given text at (x, y), and return the x position after it
+func tuiPrint(x, y int, fg, bg termbox.Attribute, text string) int {
+	for _, r := range text {
+		termbox.SetCell(x, y, r, fg, bg)
+		x += runewidth.RuneWidth(r)
+	}
+	return x
+}
+
+// print a tab at (x, y), and return the x position after it
+func tuiTab(x, y int, title string, selected bool) int {
+	if selected {
+		return tuiPrint(x, y, tuiColorSelected, tuiColorBar, " "+title+" ") + 1
+	}
+	return tuiPrint(x, y, tuiColorText, termbox.ColorDefault, " "+title+" ") + 1
+}
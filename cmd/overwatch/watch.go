@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+const (
+	DefaultWatchInterval = 10 * time.Minute
+
+	WatchIntervalParamDescription  = `interval of polling stats`
+	WatchSnapshotsParamDescription = `append a snapshot to given .json file whenever the stat changes (can be used for charts with -snapshots)`
+	WatchJsonParamDescription      = `print changes in json (one object per line)`
+	WatchGoalsParamDescription     = `print events when goals of the player (in the config) are reached`
+)
+
+// a change printed in json
+type watchedChange struct {
+	Time time.Time `json:"time"`
+	stat.StatChange
+}
+
+// poll stat of a player periodically, and print its changes (until interrupted)
+func runWatch(args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	pf := addPlayerFlags(flags, conf.BattleTag, conf.Region)
+	interval := flags.Duration("interval", DefaultWatchInterval, WatchIntervalParamDescription)
+	snapshotsFile := flags.String("snapshots", "", WatchSnapshotsParamDescription)
+	toJson := flags.Bool("json", false, WatchJsonParamDescription)
+	withGoals := flags.Bool("goals", false, WatchGoalsParamDescription)
+	flags.Parse(args)
+
+	p, err := pf.player(flags)
+	if err != nil {
+		return err
+	}
+
+	goals := []stat.Goal{}
+	if *withGoals {
+		if goals, err = goalsOf(p, ""); err != nil {
+			return err
+		}
+		if len(goals) == 0 {
+			return errorWithCode(ExitCodeConfig, "No goals of %s in %s", p.battleTag, configFilepath())
+		}
+	}
+
+	// the first fetch should succeed
+	current, err := p.fetch()
+	if err != nil {
+		return err
+	}
+	currentTime := time.Now()
+	if *snapshotsFile != "" {
+		if err := appendSnapshot(*snapshotsFile, stat.Snapshot{Time: currentTime, Stat: current}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "> Watching %s (every %s)\n", p.battleTag, *interval)
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-interrupted:
+			return nil
+		}
+
+		polled, err := p.fetch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "* %s\n", err)
+			continue
+		}
+
+		now := time.Now()
+		changes := stat.DiffStats(current, polled)
+		if len(changes) == 0 {
+			continue
+		}
+		for _, change := range changes {
+			if *toJson {
+				if bytes, err := json.Marshal(watchedChange{Time: now, StatChange: change}); err == nil {
+					fmt.Printf("%s\n", string(bytes))
+				}
+			} else {
+				fmt.Printf("[%s] %s\n", now.Format(time.RFC3339), change)
+			}
+		}
+		for _, event := range stat.GoalEvents(goals, stat.Snapshot{Time: currentTime, Stat: current}, stat.Snapshot{Time: now, Stat: polled}) {
+			if *toJson {
+				if bytes, err := json.Marshal(event); err == nil {
+					fmt.Printf("%s\n", string(bytes))
+				}
+			} else {
+				fmt.Printf("[%s] %s\n", now.Format(time.RFC3339), event)
+			}
+		}
+		current, currentTime = polled, now
+
+		if *snapshotsFile != "" {
+			if err := appendSnapshot(*snapshotsFile, stat.Snapshot{Time: now, Stat: current}); err != nil {
+				fmt.Fprintf(os.Stderr, "* %s\n", err)
+			}
+		}
+	}
+}
+
+// append a snapshot to given .json file of snapshots (created if it doesn't exist)
+func appendSnapshot(path string, snapshot stat.Snapshot) error {
+	snapshots := []stat.Snapshot{}
+	if bytes, err := ioutil.ReadFile(path); err == nil {
+		if err := json.Unmarshal(bytes, &snapshots); err != nil {
+			return fmt.Errorf("Malformed snapshots file %s: %s", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	snapshots = append(snapshots, snapshot)
+
+	return saveJson(path, snapshots)
+}
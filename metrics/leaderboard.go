@@ -0,0 +1,408 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// modes of leaderboard paths
+const (
+	ModeQuickPlay       = "quick_play"
+	ModeCompetitivePlay = "competitive_play"
+)
+
+// name of all heroes in leaderboard paths, eg. "competitive_play.values[all][games_won]"
+const LeaderboardAllHeroes = "all"
+
+// reasons of unranked players
+const (
+	UnrankedPrivate  = "private"
+	UnrankedNoValue  = "no value"
+	UnrankedFewGames = "not enough games"
+)
+
+const (
+	SampleLeaderboardHtmlTemplate = `<html>
+	<head>
+		<title>Overwatch: Leaderboards</title>
+		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+		<meta name="viewport" content="user-scalable=yes, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0, width=device-width">
+		<style>
+			body {
+				display: block;
+				padding: 3px;
+				margin: 3px;
+				background-color: #405275;
+				color: #f0edf2;
+				font-family: Futura,century gothic,arial,sans-serif;
+			}
+			h2 {
+				font-family: Koverwatch, sans-serif;
+			}
+			table {
+				border-collapse: collapse;
+			}
+			th, td {
+				padding: 4px 10px;
+				text-align: left;
+			}
+			td.number {
+				text-align: right;
+			}
+			tr.leader {
+				color: #f99e1a;
+			}
+			.unranked {
+				color: #c0c8d8;
+			}
+		</style>
+	</head>
+	<body>
+		{{range .}}
+			<h2>{{.Path}}</h2>
+			<table>
+				<tr><th>#</th><th>Player</th><th>Value</th><th>Games</th><th>Percentile</th></tr>
+				{{range .Entries}}
+					<tr{{if eq .Rank 1}} class="leader"{{end}}>
+						<td class="number">{{.Rank}}{{if .Tied}}={{end}}</td>
+						<td>{{.Name}}</td>
+						<td class="number">{{number .Value}}</td>
+						<td class="number">{{number .Games}}</td>
+						<td class="number">{{printf "%.1f" .Percentile}}</td>
+					</tr>
+				{{end}}
+			</table>
+			{{if .Unranked}}
+				<p class="unranked">Unranked: {{range $i, $p := .Unranked}}{{if $i}}, {{end}}{{$p.Name}} ({{$p.Reason}}){{end}}</p>
+			{{end}}
+		{{end}}
+	</body>
+</html>`
+)
+
+// paths of derived metrics and values, eg. "competitive_play.metrics[Ana][eliminations_per_10m]"
+var leaderboardPathRegexp = regexp.MustCompile(`^(quick_play|competitive_play)\.(metrics|values)\[([^\]]+)\]\[([^\]]+)\]$`)
+
+// paths of career stats, eg. "competitive_play.career_stats[Ana][Combat][Eliminations]"
+var careerStatPathRegexp = regexp.MustCompile(`^(quick_play|competitive_play)\.career_stats\[([^\]]+)\]`)
+
+// a player on leaderboards
+type LeaderboardPlayer struct {
+	Name string
+	Stat stat.Stat
+}
+
+// options of leaderboards
+type LeaderboardOptions struct {
+	MinGames  float64 // players with fewer games played than this are unranked (see LeaderboardEntry.Games)
+	Ascending bool    // lower values rank higher (eg. deaths)
+}
+
+// a ranked player on a leaderboard
+type LeaderboardEntry struct {
+	Rank       int     `json:"rank"` // tied players share the same rank, eg. 1, 2, 2, 4
+	Name       string  `json:"name"`
+	Value      float64 `json:"value"`
+	Games      float64 `json:"games"`      // games played in the mode of the path, of its hero (0 when unknown)
+	Percentile float64 `json:"percentile"` // percentage of ranked players whose values are not better than this one (100 for the first)
+	Tied       bool    `json:"tied"`
+}
+
+// a player who is not ranked on a leaderboard
+type UnrankedPlayer struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"` // UnrankedPrivate, UnrankedNoValue, or UnrankedFewGames
+}
+
+// players ranked by a value
+type Leaderboard struct {
+	Path      string             `json:"path"`
+	Ascending bool               `json:"ascending"`
+	MinGames  float64            `json:"min_games"`
+	Entries   []LeaderboardEntry `json:"entries"`
+	Unranked  []UnrankedPlayer   `json:"unranked"` // in the order of given players
+}
+
+// a parsed path of leaderboards
+type leaderboardPath struct {
+	path string
+	mode string
+	kind string // "metrics", "values", or empty for paths of stat.StatValue
+	hero string // (empty for all heroes)
+	key  string
+}
+
+// rank given players by the value of given path
+//
+// paths can be:
+//
+// - derived metrics, eg. "competitive_play.metrics[Ana][eliminations_per_10m]", "quick_play.metrics[all][kda]"
+//
+// - values of career stats with canonical keys (or labels), eg. "competitive_play.values[all][games_won]"
+//
+// - paths of stat.StatValue, eg. "competitive_rank", "competitive_play.career_stats[ALL HEROES][Game][Games Won]"
+//
+// (heroes in paths can be ids or names in any language; games are counted in competitive play for paths without a mode)
+func NewLeaderboard(players []LeaderboardPlayer, path string, options LeaderboardOptions) (Leaderboard, error) {
+	board := Leaderboard{
+		Path:      path,
+		Ascending: options.Ascending,
+		MinGames:  options.MinGames,
+		Entries:   []LeaderboardEntry{},
+		Unranked:  []UnrankedPlayer{},
+	}
+
+	p, err := parseLeaderboardPath(path)
+	if err != nil {
+		return board, err
+	}
+
+	for _, player := range players {
+		if player.Stat.Private {
+			board.Unranked = append(board.Unranked, UnrankedPlayer{Name: player.Name, Reason: UnrankedPrivate})
+			continue
+		}
+		value, exists := p.value(player.Stat)
+		if !exists {
+			board.Unranked = append(board.Unranked, UnrankedPlayer{Name: player.Name, Reason: UnrankedNoValue})
+			continue
+		}
+		games := p.games(player.Stat)
+		if games < options.MinGames {
+			board.Unranked = append(board.Unranked, UnrankedPlayer{Name: player.Name, Reason: UnrankedFewGames})
+			continue
+		}
+
+		board.Entries = append(board.Entries, LeaderboardEntry{Name: player.Name, Value: value, Games: games})
+	}
+
+	// sort by values (then by names), and rank them with ties
+	better := func(a, b float64) bool {
+		if options.Ascending {
+			return a < b
+		}
+		return a > b
+	}
+	sort.SliceStable(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Value != b.Value {
+			return better(a.Value, b.Value)
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	for i := range board.Entries {
+		entry := &board.Entries[i]
+		if i > 0 && board.Entries[i-1].Value == entry.Value {
+			entry.Rank = board.Entries[i-1].Rank
+			entry.Tied = true
+			board.Entries[i-1].Tied = true
+		} else {
+			entry.Rank = i + 1
+		}
+	}
+	for i := range board.Entries {
+		entry := &board.Entries[i]
+		entry.Percentile = float64(len(board.Entries)-entry.Rank+1) / float64(len(board.Entries)) * 100
+	}
+
+	return board, nil
+}
+
+// rank given players by values of each path
+func NewLeaderboards(players []LeaderboardPlayer, paths []string, options LeaderboardOptions) ([]Leaderboard, error) {
+	boards := []Leaderboard{}
+	for _, path := range paths {
+		board, err := NewLeaderboard(players, path, options)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+// players of fetched stats of a roster, named by their battle tags as in PlayersOfHistory (members who failed to be fetched are skipped)
+func PlayersOfRoster(stats []stat.RosterStat) []LeaderboardPlayer {
+	players := []LeaderboardPlayer{}
+	for _, s := range stats {
+		if s.Err == nil {
+			players = append(players, LeaderboardPlayer{Name: s.Member.BattleTag, Stat: s.Stat})
+		}
+	}
+	return players
+}
+
+// players of the latest snapshots in given history (eg. of roster members, keyed by battle tags), sorted by their keys
+func PlayersOfHistory(history map[string][]stat.Snapshot) []LeaderboardPlayer {
+	players := []LeaderboardPlayer{}
+	for battleTag, snapshots := range history {
+		if len(snapshots) == 0 {
+			continue
+		}
+
+		latest := snapshots[0]
+		for _, snapshot := range snapshots {
+			if snapshot.Time.After(latest.Time) {
+				latest = snapshot
+			}
+		}
+		players = append(players, LeaderboardPlayer{Name: battleTag, Stat: latest.Stat})
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+	return players
+}
+
+// render given leaderboards to html with given template string
+func RenderLeaderboardsToHtml(boards []Leaderboard, templateStr string) (result string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = template.New("leaderboards").Funcs(template.FuncMap{
+		"number": FormatValue,
+	}).Parse(templateStr); err == nil {
+		var buffer bytes.Buffer
+		if err = tmpl.Execute(&buffer, boards); err == nil {
+			return buffer.String(), nil
+		}
+	}
+	return "", err
+}
+
+// format given value of metrics (integers without decimal places, others with 2)
+func FormatValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return fmt.Sprintf("%d", int64(value))
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// parse and check given path of leaderboards
+func parseLeaderboardPath(path string) (p leaderboardPath, err error) {
+	p = leaderboardPath{path: path, mode: ModeCompetitivePlay}
+
+	if matches := leaderboardPathRegexp.FindStringSubmatch(path); matches != nil {
+		p.mode, p.kind, p.hero, p.key = matches[1], matches[2], matches[3], matches[4]
+
+		if p.kind == "metrics" {
+			registered := false
+			for _, metric := range Metrics() {
+				if metric.Name == p.key {
+					registered = true
+					break
+				}
+			}
+			if !registered {
+				return p, fmt.Errorf("no such metric: %s (in path '%s')", p.key, path)
+			}
+		}
+	} else if matches := careerStatPathRegexp.FindStringSubmatch(path); matches != nil {
+		p.mode, p.hero = matches[1], matches[2]
+	} else if strings.HasPrefix(path, ModeQuickPlay+".") {
+		p.mode = ModeQuickPlay
+	}
+
+	if strings.EqualFold(p.hero, LeaderboardAllHeroes) || stat.IsAllHeroes(p.hero) {
+		p.hero = ""
+	} else if p.hero != "" && p.kind != "" {
+		if _, exists := heroInfoOf(p.hero); !exists {
+			return p, fmt.Errorf("no such hero: %s (in path '%s')", p.hero, path)
+		}
+	}
+
+	return p, nil
+}
+
+// value of the path in given stat
+func (p leaderboardPath) value(s stat.Stat) (float64, bool) {
+	playStat := p.playStat(s)
+
+	switch p.kind {
+	case "metrics":
+		result := CalculatePlayStat(playStat)
+		metrics := result.Overall
+		if p.hero != "" {
+			metrics = nil
+			for _, h := range result.Heroes {
+				if sameHero(p.hero, h.Hero) {
+					metrics = h.Metrics
+					break
+				}
+			}
+		}
+		value, exists := metrics[p.key]
+		return value, exists
+	case "values":
+		if values, exists := p.values(playStat); exists {
+			if key, exists := canonicalKey(p.key); exists {
+				return values.Get(key)
+			}
+			return values.Get(p.key)
+		}
+		return 0, false
+	}
+
+	value, exists := stat.StatValue(s, p.path)
+	if !exists {
+		return 0, false
+	}
+	if p.path == "competitive_rank" && s.CompetitiveRank <= 0 { // unranked
+		return 0, false
+	}
+	if number, err := stat.ParseNumber(value); err == nil {
+		return number, true
+	}
+	if duration, err := stat.ParseDuration(value); err == nil {
+		return duration.Seconds(), true
+	}
+	return 0, false
+}
+
+// games played in the mode of the path, of its hero (0 when unknown)
+func (p leaderboardPath) games(s stat.Stat) float64 {
+	if values, exists := p.values(p.playStat(s)); exists {
+		return values.Or0(GamesPlayed)
+	}
+	return 0
+}
+
+// values of the career stat of the path's hero (or all heroes)
+func (p leaderboardPath) values(playStat stat.PlayStat) (Values, bool) {
+	for _, careerStat := range playStat.CareerStats {
+		if p.hero == "" && stat.IsAllHeroes(careerStat.HeroName) || p.hero != "" && sameHero(p.hero, careerStat.HeroName) {
+			return ValuesOf(careerStat), true
+		}
+	}
+	return nil, false
+}
+
+func (p leaderboardPath) playStat(s stat.Stat) stat.PlayStat {
+	if p.mode == ModeQuickPlay {
+		return s.QuickPlay
+	}
+	return s.CompetitivePlay
+}
+
+// find metadata of a hero with given id or name
+func heroInfoOf(hero string) (stat.HeroInfo, bool) {
+	if info, exists := stat.HeroById(hero); exists {
+		return info, true
+	}
+	return stat.HeroByName(hero)
+}
+
+// check if given names (or ids) are of the same hero
+func sameHero(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	infoA, existsA := heroInfoOf(a)
+	infoB, existsB := heroInfoOf(b)
+	return existsA && existsB && infoA.Id == infoB.Id
+}
@@ -0,0 +1,123 @@
+package metrics_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/metrics"
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestNewLeaderboard(t *testing.T) {
+	english := stattest.FixturePcCompetitive.Stat(t)
+	players := []metrics.LeaderboardPlayer{
+		{Name: "korean", Stat: stattest.FixturePcCompetitiveKorean.Stat(t)},
+		{Name: "english", Stat: english},
+		{Name: "unranked", Stat: stattest.FixturePcNoCompetitive.Stat(t)},
+		{Name: "private", Stat: stat.Stat{Private: true}},
+		{Name: "English", Stat: english},
+	}
+
+	// ties share the same rank
+	board, err := metrics.NewLeaderboard(players, "competitive_rank", metrics.LeaderboardOptions{})
+	if err != nil {
+		t.Fatalf("failed to create a leaderboard: %s", err)
+	}
+	if len(board.Entries) != 3 {
+		t.Fatalf("expected 3 ranked players, got %+v", board.Entries)
+	}
+	for i, expected := range []metrics.LeaderboardEntry{
+		{Rank: 1, Name: "english", Value: 3537, Games: 191, Percentile: 100, Tied: true},
+		{Rank: 1, Name: "English", Value: 3537, Games: 191, Percentile: 100, Tied: true},
+		{Rank: 3, Name: "korean", Value: 2679, Games: 259, Percentile: 100.0 / 3, Tied: false},
+	} {
+		if entry := board.Entries[i]; entry.Rank != expected.Rank || entry.Name != expected.Name || entry.Value != expected.Value || entry.Games != expected.Games || math.Abs(entry.Percentile-expected.Percentile) > 0.001 || entry.Tied != expected.Tied {
+			t.Errorf("expected %+v at %d, got %+v", expected, i, entry)
+		}
+	}
+	if len(board.Unranked) != 2 || board.Unranked[0].Reason != metrics.UnrankedNoValue || board.Unranked[1].Reason != metrics.UnrankedPrivate {
+		t.Errorf("unexpected unranked players: %+v", board.Unranked)
+	}
+
+	// derived metrics of a hero with localized names, and a minimum number of games
+	board, _ = metrics.NewLeaderboard(players, "competitive_play.metrics[ana][eliminations_per_10m]", metrics.LeaderboardOptions{MinGames: 200})
+	if len(board.Entries) != 2 || board.Entries[0].Value != 11.37 || board.Entries[0].Games != 228 {
+		t.Errorf("unexpected entries of eliminations per 10 minutes: %+v", board.Entries)
+	}
+	if len(board.Unranked) != 3 || board.Unranked[0].Name != "korean" || board.Unranked[0].Reason != metrics.UnrankedFewGames {
+		t.Errorf("players with fewer games should be unranked: %+v", board.Unranked)
+	}
+
+	// lower values rank higher
+	board, _ = metrics.NewLeaderboard(players, "quick_play.values[all][games_won]", metrics.LeaderboardOptions{Ascending: true})
+	if len(board.Entries) != 4 || board.Entries[0].Name != "korean" || !board.Entries[1].Tied || board.Entries[3].Rank != 4 {
+		t.Errorf("unexpected entries in ascending order: %+v", board.Entries)
+	}
+
+	// malformed paths
+	for _, path := range []string{
+		"competitive_play.metrics[ana][no_such_metric]",
+		"quick_play.values[Nobody][games_won]",
+	} {
+		if _, err := metrics.NewLeaderboard(players, path, metrics.LeaderboardOptions{}); err == nil {
+			t.Errorf("path '%s' should be malformed", path)
+		}
+	}
+}
+
+func TestLeaderboardPlayers(t *testing.T) {
+	english := stattest.FixturePcCompetitive.Stat(t)
+	korean := stattest.FixturePcCompetitiveKorean.Stat(t)
+
+	// players of a roster and of its history are named by battle tags
+	roster := metrics.PlayersOfRoster([]stat.RosterStat{
+		{Member: stat.RosterMember{Name: "Korean", BattleTag: "korean#1"}, Stat: korean},
+		{Member: stat.RosterMember{Name: "Failed", BattleTag: "failed#1"}, Err: stat.ErrPlayerNotFound},
+		{Member: stat.RosterMember{BattleTag: "english#1"}, Stat: english},
+	})
+	history := metrics.PlayersOfHistory(map[string][]stat.Snapshot{
+		"korean#1":  {{Time: time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC), Stat: english}, {Time: time.Date(2018, 6, 2, 0, 0, 0, 0, time.UTC), Stat: korean}},
+		"english#1": {{Time: time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC), Stat: english}},
+	})
+	for _, players := range [][]metrics.LeaderboardPlayer{roster, history} {
+		board, err := metrics.NewLeaderboard(players, "competitive_rank", metrics.LeaderboardOptions{})
+		if err != nil {
+			t.Fatalf("failed to create a leaderboard: %s", err)
+		}
+		if len(board.Entries) != 2 || board.Entries[0].Name != "english#1" || board.Entries[1].Name != "korean#1" || board.Entries[1].Value != 2679 {
+			t.Errorf("unexpected entries: %+v", board.Entries)
+		}
+	}
+
+	// all heroes in any language
+	for _, hero := range []string{"all", "ALL HEROES", "모든 영웅", "TOUS LES HÉROS"} {
+		board, err := metrics.NewLeaderboard(roster, "competitive_play.values["+hero+"][games_won]", metrics.LeaderboardOptions{})
+		if err != nil || len(board.Entries) != 2 || board.Entries[0].Value == 0 {
+			t.Errorf("unexpected leaderboard of %s: %+v (%v)", hero, board.Entries, err)
+		}
+	}
+}
+
+func TestRenderLeaderboardsToHtml(t *testing.T) {
+	players := []metrics.LeaderboardPlayer{
+		{Name: "english", Stat: stattest.FixturePcCompetitive.Stat(t)},
+		{Name: "private", Stat: stat.Stat{Private: true}},
+	}
+	boards, err := metrics.NewLeaderboards(players, []string{"level", "competitive_play.metrics[all][kda]"}, metrics.LeaderboardOptions{})
+	if err != nil {
+		t.Fatalf("failed to create leaderboards: %s", err)
+	}
+
+	html, err := metrics.RenderLeaderboardsToHtml(boards, metrics.SampleLeaderboardHtmlTemplate)
+	if err != nil {
+		t.Fatalf("failed to render leaderboards: %s", err)
+	}
+	for _, expected := range []string{"competitive_play.metrics[all][kda]", "<td class=\"number\">1.88</td>", "private (private)"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("rendered html doesn't contain %s", expected)
+		}
+	}
+}
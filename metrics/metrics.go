@@ -0,0 +1,245 @@
+// Package metrics calculates derived metrics (eg. KDA, per-10-minute averages, and win rate)
+// from parsed career stats of the stat package.
+//
+//	report := metrics.CalculateStat(s)
+//	fmt.Printf("KDA: %.2f\n", report.CompetitivePlay.Overall["kda"])
+//
+// Custom metrics can be registered with formulas:
+//
+//	metrics.Register(metrics.Metric{
+//		Name:        "objective_kills_per_10m",
+//		Description: "objective kills per 10 minutes",
+//		Formula:     metrics.Per10Minutes("Objective Kills"),
+//	})
+//
+// Players can be ranked on leaderboards by stats or derived metrics:
+//
+//	board, err := metrics.NewLeaderboard(players, "competitive_play.metrics[Ana][kda]", metrics.LeaderboardOptions{MinGames: 10})
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// names of standard metrics
+const (
+	KDA                    = "kda"
+	EliminationsPer10m     = "eliminations_per_10m"
+	FinalBlowsPer10m       = "final_blows_per_10m"
+	DeathsPer10m           = "deaths_per_10m"
+	DamagePer10m           = "damage_per_10m"
+	HealingPer10m          = "healing_per_10m"
+	WinRate                = "win_rate"
+	AccuracyWeightedDamage = "accuracy_weighted_damage"
+	HealingPerLife         = "healing_per_life"
+)
+
+// a formula of a metric, which returns false when it cannot be calculated with given values
+type Formula func(values Values) (float64, bool)
+
+// a derived metric
+type Metric struct {
+	Name        string  `json:"name"` // key in results, eg. "kda"
+	Description string  `json:"description"`
+	Formula     Formula `json:"-"`
+}
+
+// registered metrics, in the order of registration
+var (
+	registry   []Metric
+	registryMu sync.RWMutex
+)
+
+func init() {
+	for _, metric := range []Metric{
+		{KDA, "(eliminations + offensive and defensive assists) / deaths", kda},
+		{EliminationsPer10m, "eliminations per 10 minutes", Per10Minutes(Eliminations)},
+		{FinalBlowsPer10m, "final blows per 10 minutes", Per10Minutes(FinalBlows)},
+		{DeathsPer10m, "deaths per 10 minutes", Per10Minutes(Deaths)},
+		{DamagePer10m, "damage done per 10 minutes", Per10Minutes(DamageDone)},
+		{HealingPer10m, "healing done per 10 minutes", Per10Minutes(HealingDone)},
+		{WinRate, "percentage of games won", winRate},
+		{AccuracyWeightedDamage, "damage done per 10 minutes, weighted by weapon accuracy", accuracyWeightedDamage},
+		{HealingPerLife, "healing done / deaths", Ratio(HealingDone, Deaths)},
+	} {
+		if err := Register(metric); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// register a metric, which will be calculated after the registered ones
+func Register(metric Metric) error {
+	if metric.Name == "" || metric.Formula == nil {
+		return fmt.Errorf("name and formula of a metric are required")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, m := range registry {
+		if m.Name == metric.Name {
+			return fmt.Errorf("metric already registered: %s", metric.Name)
+		}
+	}
+	registry = append(registry, metric)
+
+	return nil
+}
+
+// unregister a metric with given name (returns false when there is no such metric)
+func Unregister(name string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, m := range registry {
+		if m.Name == name {
+			registry = append(registry[:i:i], registry[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// registered metrics, in order
+func Metrics() []Metric {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Metric{}, registry...)
+}
+
+// formula of a value per 10 minutes of time played
+func Per10Minutes(key string) Formula {
+	return func(values Values) (float64, bool) {
+		value, exists := values.Get(key)
+		seconds := values.Or0(TimePlayed)
+		if !exists || seconds <= 0 {
+			return 0, false
+		}
+		return value / (seconds / 600), true
+	}
+}
+
+// formula of a value divided by another one (not calculated when the divisor is 0)
+func Ratio(numeratorKey, denominatorKey string) Formula {
+	return func(values Values) (float64, bool) {
+		numerator, exists := values.Get(numeratorKey)
+		denominator := values.Or0(denominatorKey)
+		if !exists || denominator == 0 {
+			return 0, false
+		}
+		return numerator / denominator, true
+	}
+}
+
+func kda(values Values) (float64, bool) {
+	eliminations, exists := values.Get(Eliminations)
+	deaths := values.Or0(Deaths)
+	if !exists || deaths == 0 {
+		return 0, false
+	}
+	return (eliminations + values.Or0(OffensiveAssists) + values.Or0(DefensiveAssists)) / deaths, true
+}
+
+// (with percentage of won games in top heroes, when the number of played games is unknown)
+func winRate(values Values) (float64, bool) {
+	if played := values.Or0(GamesPlayed); played > 0 {
+		return values.Or0(GamesWon) / played * 100, true
+	}
+	return values.Get(WinPercentage)
+}
+
+func accuracyWeightedDamage(values Values) (float64, bool) {
+	accuracy, exists := values.Get(WeaponAccuracy)
+	if !exists {
+		return 0, false
+	}
+	if damage, ok := Per10Minutes(DamageDone)(values); ok {
+		return damage * accuracy / 100, true
+	}
+	return 0, false
+}
+
+// derived metrics of a hero
+type HeroMetrics struct {
+	Hero    string             `json:"hero"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// derived metrics of a play stat
+type PlayMetrics struct {
+	Overall map[string]float64 `json:"overall"` // of all heroes
+	Heroes  []HeroMetrics      `json:"heroes"`  // in the order of career stats
+}
+
+// derived metrics of a stat
+type Report struct {
+	QuickPlay       PlayMetrics `json:"quick_play"`
+	CompetitivePlay PlayMetrics `json:"competitive_play"`
+}
+
+// calculate registered metrics with given values
+//
+// (metrics which cannot be calculated are omitted, and results are rounded to 2 decimal places)
+func Calculate(values Values) map[string]float64 {
+	results := map[string]float64{}
+	for _, metric := range Metrics() {
+		if value, ok := metric.Formula(values); ok && !math.IsNaN(value) && !math.IsInf(value, 0) {
+			results[metric.Name] = math.Round(value*100) / 100
+		}
+	}
+	return results
+}
+
+// calculate registered metrics of each hero and all heroes in given play stat
+//
+// (weapon accuracies and win percentages are taken from top heroes, when career stats don't have them)
+func CalculatePlayStat(playStat stat.PlayStat) PlayMetrics {
+	result := PlayMetrics{Overall: map[string]float64{}, Heroes: []HeroMetrics{}}
+
+	var overall Values
+	accuracies, seconds := 0.0, 0.0 // for the overall weapon accuracy, weighted by time played
+	for _, careerStat := range playStat.CareerStats {
+		values := ValuesOf(careerStat)
+		if stat.IsAllHeroes(careerStat.HeroName) {
+			overall = values
+			continue
+		}
+
+		for _, key := range []string{WeaponAccuracy, WinPercentage} {
+			if _, exists := values[key]; !exists {
+				if value, exists := topHeroValue(playStat, key, careerStat.HeroName); exists {
+					values[key] = value
+				}
+			}
+		}
+		if accuracy, exists := values.Get(WeaponAccuracy); exists {
+			accuracies += accuracy * values.Or0(TimePlayed)
+			seconds += values.Or0(TimePlayed)
+		}
+
+		result.Heroes = append(result.Heroes, HeroMetrics{Hero: careerStat.HeroName, Metrics: Calculate(values)})
+	}
+
+	if overall != nil {
+		if _, exists := overall[WeaponAccuracy]; !exists && seconds > 0 {
+			overall[WeaponAccuracy] = accuracies / seconds
+		}
+		result.Overall = Calculate(overall)
+	}
+
+	return result
+}
+
+// calculate registered metrics of quick and competitive play of given stat
+func CalculateStat(s stat.Stat) Report {
+	return Report{
+		QuickPlay:       CalculatePlayStat(s.QuickPlay),
+		CompetitivePlay: CalculatePlayStat(s.CompetitivePlay),
+	}
+}
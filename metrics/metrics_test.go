@@ -0,0 +1,105 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/meinside/overwatch-go/metrics"
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func heroMetrics(t *testing.T, playMetrics metrics.PlayMetrics, hero string) map[string]float64 {
+	for _, h := range playMetrics.Heroes {
+		if h.Hero == hero {
+			return h.Metrics
+		}
+	}
+	t.Fatalf("no metrics of %s", hero)
+	return nil
+}
+
+func TestCalculateStat(t *testing.T) {
+	report := metrics.CalculateStat(stattest.FixturePcCompetitive.Stat(t))
+
+	// of a hero, with weapon accuracy from top heroes
+	ana := heroMetrics(t, report.CompetitivePlay, "Ana")
+	for name, expected := range map[string]float64{
+		metrics.KDA:                    1.62,
+		metrics.EliminationsPer10m:     11.37,
+		metrics.HealingPerLife:         305.99,
+		metrics.WinRate:                46.49,
+		metrics.AccuracyWeightedDamage: 2159.44,
+	} {
+		if ana[name] != expected {
+			t.Errorf("expected %s of Ana to be %.2f, got %.2f", name, expected, ana[name])
+		}
+	}
+
+	// of all heroes, with weapon accuracy weighted by time played of heroes
+	overall := report.CompetitivePlay.Overall
+	if overall[metrics.KDA] != 1.88 || overall[metrics.WinRate] != 38.74 || overall[metrics.AccuracyWeightedDamage] != 2753.01 {
+		t.Errorf("unexpected overall metrics: %+v", overall)
+	}
+	if len(report.CompetitivePlay.Heroes) != 5 {
+		t.Errorf("expected metrics of 5 heroes, got %d", len(report.CompetitivePlay.Heroes))
+	}
+
+	// no games played in quick play: win rate from top heroes
+	if reaper := heroMetrics(t, report.QuickPlay, "Reaper"); reaper[metrics.WinRate] == 0 {
+		t.Errorf("win rate of Reaper should be taken from top heroes: %+v", reaper)
+	}
+
+	// localized career stats
+	korean := metrics.CalculateStat(stattest.FixturePcCompetitiveKorean.Stat(t))
+	if ana := heroMetrics(t, korean.CompetitivePlay, "아나"); ana[metrics.KDA] != 1.57 || len(ana) != len(metrics.Metrics()) {
+		t.Errorf("unexpected metrics of Ana in korean: %+v", ana)
+	}
+	values := metrics.ValuesOf(stat.CareerStat{HeroName: "TOUS LES HÉROS", Categories: []stat.CareerStatCategory{
+		{Name: "Combat", Values: stat.KeyValues{{Key: "Éliminations", Value: "30"}, {Key: "Morts", Value: "10"}}},
+		{Name: "Partie", Values: stat.KeyValues{{Key: "Temps de jeu", Value: "01:00:00"}, {Key: "Parties gagnées", Value: "3"}, {Key: "Parties perdues", Value: "2"}}},
+	}})
+	if values[metrics.Eliminations] != 30 || values[metrics.Deaths] != 10 || values[metrics.TimePlayed] != 3600 || values[metrics.GamesPlayed] != 5 {
+		t.Errorf("unexpected values in french: %+v", values)
+	}
+
+	// private profiles
+	private := metrics.CalculateStat(stat.Stat{Private: true})
+	if len(private.QuickPlay.Overall) != 0 || len(private.CompetitivePlay.Heroes) != 0 {
+		t.Errorf("private profiles should have no metrics: %+v", private)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	metric := metrics.Metric{
+		Name:        "objective_kills_per_10m",
+		Description: "objective kills per 10 minutes",
+		Formula:     metrics.Per10Minutes("Objective Kills"),
+	}
+	if err := metrics.Register(metric); err != nil {
+		t.Fatalf("failed to register a metric: %s", err)
+	}
+	defer metrics.Unregister(metric.Name)
+
+	if err := metrics.Register(metric); err == nil {
+		t.Errorf("registering a duplicated metric should fail")
+	}
+	if err := metrics.Register(metrics.Metric{Name: "no_formula"}); err == nil {
+		t.Errorf("registering a metric without formula should fail")
+	}
+	if all := metrics.Metrics(); all[len(all)-1].Name != metric.Name {
+		t.Errorf("custom metrics should be calculated after the registered ones")
+	}
+
+	values := metrics.Values{metrics.TimePlayed: 1200, "Objective Kills": 10, metrics.Deaths: 0, metrics.HealingDone: 100}
+	results := metrics.Calculate(values)
+	if results[metric.Name] != 5 {
+		t.Errorf("expected 5 objective kills per 10 minutes, got %.2f", results[metric.Name])
+	}
+	if _, exists := results[metrics.HealingPerLife]; exists {
+		t.Errorf("metrics divided by zero should be omitted")
+	}
+
+	if !metrics.Unregister(metric.Name) || metrics.Unregister(metric.Name) {
+		t.Errorf("failed to unregister the metric")
+	}
+}
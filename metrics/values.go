@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"github.com/meinside/overwatch-go/stat"
+)
+
+// canonical keys of values, parsed from career stats (or top heroes) of any language in stat.SupportedLanguages
+const (
+	Eliminations     = "eliminations"
+	FinalBlows       = "final_blows"
+	Deaths           = "deaths"
+	DamageDone       = "damage_done"
+	HealingDone      = "healing_done"
+	OffensiveAssists = "offensive_assists"
+	DefensiveAssists = "defensive_assists"
+	GamesWon         = "games_won"
+	GamesLost        = "games_lost"
+	GamesTied        = "games_tied"
+	GamesPlayed      = "games_played"
+	TimePlayed       = "time_played"     // in seconds
+	WeaponAccuracy   = "weapon_accuracy" // in percent (0 ~ 100)
+	WinPercentage    = "win_percentage"  // in percent (0 ~ 100), from top heroes
+)
+
+// canonical keys of english labels in career stats and top heroes (labels in other languages are translated with stat.CanonicalLabel)
+var keysOfLabels = map[string]string{
+	"Eliminations":      Eliminations,
+	"Final Blows":       FinalBlows,
+	"Deaths":            Deaths,
+	"All Damage Done":   DamageDone,
+	"Damage Done":       DamageDone,
+	"Healing Done":      HealingDone,
+	"Offensive Assists": OffensiveAssists,
+	"Defensive Assists": DefensiveAssists,
+	"Games Won":         GamesWon,
+	"Games Lost":        GamesLost,
+	"Games Tied":        GamesTied,
+	"Games Played":      GamesPlayed,
+	"Time Played":       TimePlayed,
+	"Weapon Accuracy":   WeaponAccuracy,
+	"Win Percentage":    WinPercentage,
+}
+
+// numeric values of a hero (or all heroes) for formulas
+//
+// (keyed by canonical keys, eg. Eliminations, and also by labels as they are on the career page, eg. "Objective Kills")
+type Values map[string]float64
+
+// get the value of given key
+func (v Values) Get(key string) (float64, bool) {
+	value, exists := v[key]
+	return value, exists
+}
+
+// get the value of given key, or 0 when it doesn't exist
+func (v Values) Or0(key string) float64 {
+	return v[key]
+}
+
+// parse numeric values of given career stat
+//
+// (values which are not numbers or durations are skipped; durations are in seconds)
+func ValuesOf(careerStat stat.CareerStat) Values {
+	values := Values{}
+	for _, category := range careerStat.Categories {
+		for _, kv := range category.Values {
+			number, err := stat.ParseNumber(kv.Value)
+			if err != nil {
+				if duration, err := stat.ParseDuration(kv.Value); err == nil {
+					number = duration.Seconds()
+				} else {
+					continue
+				}
+			}
+
+			if _, exists := values[kv.Key]; !exists {
+				values[kv.Key] = number
+			}
+			if key, exists := canonicalKey(kv.Key); exists {
+				if _, exists := values[key]; !exists {
+					values[key] = number
+				}
+			}
+		}
+	}
+
+	// games played can be calculated with other ones
+	if _, exists := values[GamesPlayed]; !exists {
+		if lost, exists := values[GamesLost]; exists {
+			values[GamesPlayed] = values[GamesWon] + lost + values[GamesTied]
+		}
+	}
+
+	return values
+}
+
+// canonical key of given label, in any supported language
+func canonicalKey(label string) (string, bool) {
+	if english, exists := stat.CanonicalLabel(label); exists {
+		key, exists := keysOfLabels[english]
+		return key, exists
+	}
+	return "", false
+}
+
+// value of given hero from the top heroes' comparison of given canonical key
+func topHeroValue(playStat stat.PlayStat, key, heroName string) (float64, bool) {
+	for _, comparison := range playStat.TopHeroes {
+		if k, exists := canonicalKey(comparison.Name); !exists || k != key {
+			continue
+		}
+
+		for _, hero := range comparison.Heroes {
+			if hero.Name == heroName {
+				if number, err := stat.ParseNumber(hero.Value); err == nil {
+					return number, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,316 @@
+package stat
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// labels of career stats for achievement progress (in all supported languages)
+var (
+	labelsMedals       = labelsOf("Medals")
+	labelsHeroSpecific = labelsOf("Hero Specific")
+)
+
+// achievements which count up to a number in their descriptions (eg. "Win 21 games."), with keywords (stems) of the descriptions
+var achievementCounters = []struct {
+	keywords []string
+	value    func(stat Stat) float64
+}{
+	{[]string{"level", "레벨", "stufe", "niveau", "nivel", "livello", "poziom", "レベル", "уров", "等級"}, func(stat Stat) float64 { return float64(stat.Level) }},
+	{[]string{"medal", "메달", "medaille", "médaille", "medalla", "medalh", "medagli", "メダル", "медал", "獎牌"}, func(stat Stat) float64 { return allHeroesTotal(stat, labelsMedals) }},
+	{[]string{"win", "승리", "gewinn", "remport", "gana", "venç", "vinc", "wygr", "勝利", "побед", "獲勝"}, func(stat Stat) float64 { return allHeroesTotal(stat, labelsGamesWon) }},
+}
+
+// a number in descriptions of achievements (eg. "21" of "Win 21 games.")
+var achievementNumber = regexp.MustCompile(`\d[\d,]*`)
+
+// completion of achievements in a category (or all categories)
+type AchievementCompletion struct {
+	Category string  `json:"category"` // (empty for all categories)
+	Achieved int     `json:"achieved"`
+	Total    int     `json:"total"`
+	Percent  float64 `json:"percent"` // 0 ~ 100
+}
+
+// an achievement with the time it was unlocked
+type AchievementUnlock struct {
+	Category    string    `json:"category"`
+	Title       string    `json:"title"`
+	Description string    `json:"achievement"`
+	ImageUrl    string    `json:"image_url"`
+	UnlockedAt  time.Time `json:"unlocked_at"` // time of the first snapshot where it was achieved
+	Initial     bool      `json:"initial"`     // achieved already in the first snapshot (so it was unlocked at or before UnlockedAt)
+}
+
+// progress of a non-achieved achievement
+type AchievementProgress struct {
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Description string `json:"achievement"`
+	ImageUrl    string `json:"image_url"`
+
+	// for achievements which count up to a number (eg. "Win 21 games.")
+	Measured bool    `json:"measured"`
+	Current  float64 `json:"current,omitempty"`
+	Target   float64 `json:"target,omitempty"`
+	Progress float64 `json:"progress,omitempty"` // 0.0 ~ 1.0
+
+	// for hero-specific achievements (eg. "Sleep 4 enemies with Biotic Grenade as Ana.")
+	Hero       string    `json:"hero,omitempty"`        // name of the hero, in the language of the career page
+	TimePlayed string    `json:"time_played,omitempty"` // of the hero, in quick and competitive play
+	Stats      KeyValues `json:"stats,omitempty"`       // relevant career stats of the hero (hero specific ones and bests)
+
+	timePlayed time.Duration
+}
+
+// summary of achievements
+type AchievementReport struct {
+	Overall    AchievementCompletion   `json:"overall"`
+	Categories []AchievementCompletion `json:"categories"`
+	Timeline   []AchievementUnlock     `json:"timeline"` // only with snapshots, in the order of unlocks
+	Closest    []AchievementProgress   `json:"closest"`  // non-achieved ones, closest to unlock first
+}
+
+// summarize achievements of given stat, with an unlock timeline of given snapshots (can be nil)
+func NewAchievementReport(stat Stat, snapshots []Snapshot) AchievementReport {
+	report := AchievementReport{
+		Overall:    AchievementCompletion{},
+		Categories: []AchievementCompletion{},
+		Timeline:   AchievementTimeline(snapshots),
+		Closest:    ClosestAchievements(stat),
+	}
+
+	for _, category := range stat.Achievements {
+		completion := newAchievementCompletion(category.Name, len(category.Achieved), len(category.Achieved)+len(category.NonAchieved))
+		report.Categories = append(report.Categories, completion)
+
+		report.Overall.Achieved += completion.Achieved
+		report.Overall.Total += completion.Total
+	}
+	report.Overall = newAchievementCompletion("", report.Overall.Achieved, report.Overall.Total)
+
+	return report
+}
+
+func newAchievementCompletion(category string, achieved, total int) AchievementCompletion {
+	completion := AchievementCompletion{Category: category, Achieved: achieved, Total: total}
+	if total > 0 {
+		completion.Percent = float64(achieved) / float64(total) * 100
+	}
+	return completion
+}
+
+// unlock timeline of achievements: the first snapshot where each achievement appears achieved
+//
+// (achievements are identified by their images, so snapshots in different languages can be combined)
+func AchievementTimeline(snapshots []Snapshot) []AchievementUnlock {
+	timeline := []AchievementUnlock{}
+	unlocked := map[string]bool{}
+	for i, snapshot := range sortedSnapshots(snapshots) {
+		for _, category := range snapshot.Stat.Achievements {
+			for _, achievement := range category.Achieved {
+				id := achievementId(achievement)
+				if unlocked[id] {
+					continue
+				}
+				unlocked[id] = true
+
+				timeline = append(timeline, AchievementUnlock{
+					Category:    category.Name,
+					Title:       achievement.Title,
+					Description: achievement.Description,
+					ImageUrl:    achievement.ImageUrl,
+					UnlockedAt:  snapshot.Time,
+					Initial:     i == 0,
+				})
+			}
+		}
+	}
+	return timeline
+}
+
+// non-achieved achievements of given stat, closest to unlock first
+//
+// - achievements which count up to a number (eg. levels, won games, and medals) are ordered by their progress
+// - hero-specific ones follow them, ordered by time played of their heroes (with relevant career stats)
+// - others are at the end, in the order of the career page
+func ClosestAchievements(stat Stat) []AchievementProgress {
+	progresses := []AchievementProgress{}
+	for _, category := range stat.Achievements {
+		for _, achievement := range category.NonAchieved {
+			progress := AchievementProgress{
+				Category:    category.Name,
+				Title:       achievement.Title,
+				Description: achievement.Description,
+				ImageUrl:    achievement.ImageUrl,
+			}
+
+			if hero, exists := achievementHero(achievement, stat.Language); exists {
+				progress.Hero = hero.LocalizedName(stat.Language)
+				progress.timePlayed, progress.Stats = heroAchievementStats(stat, progress.Hero)
+				if progress.timePlayed > 0 {
+					progress.TimePlayed = formatDuration(progress.timePlayed)
+				}
+			} else if target, exists := achievementTarget(achievement); exists {
+				for _, counter := range achievementCounters {
+					if containsKeyword(achievement.Description, counter.keywords) {
+						// (not measurable when it is still locked with the target reached, eg. counted differently)
+						if current := counter.value(stat); current < target {
+							progress.Measured = true
+							progress.Current = current
+							progress.Target = target
+							progress.Progress = current / target
+						}
+						break
+					}
+				}
+			}
+
+			progresses = append(progresses, progress)
+		}
+	}
+
+	// measured ones first, then hero-specific ones
+	rank := func(p AchievementProgress) int {
+		switch {
+		case p.Measured:
+			return 0
+		case p.Hero != "":
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(progresses, func(i, j int) bool {
+		a, b := progresses[i], progresses[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.Measured {
+			return a.Progress > b.Progress
+		}
+		return a.timePlayed > b.timePlayed
+	})
+
+	return progresses
+}
+
+// id of an achievement (from its image url, or its title)
+func achievementId(achievement Achievement) string {
+	if achievement.ImageUrl != "" {
+		return strings.TrimSuffix(path.Base(achievement.ImageUrl), path.Ext(achievement.ImageUrl))
+	}
+	return achievement.Title
+}
+
+// the hero mentioned in the description of given achievement (eg. "... as Ana.")
+func achievementHero(achievement Achievement, language string) (hero HeroInfo, exists bool) {
+	longest := 0
+	for _, h := range Heroes {
+		for _, name := range []string{h.LocalizedName(language), h.Name} {
+			if len(name) > longest && containsName(achievement.Description, name) {
+				hero, exists, longest = h, true, len(name)
+			}
+		}
+	}
+	return hero, exists
+}
+
+// the number to count up to, in the description of given achievement
+func achievementTarget(achievement Achievement) (float64, bool) {
+	if found := achievementNumber.FindString(achievement.Description); found != "" {
+		if number, err := ParseNumber(found); err == nil && number > 0 {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// time played and relevant career stats (hero specific ones and bests) of given hero, in quick and competitive play
+func heroAchievementStats(stat Stat, heroName string) (timePlayed time.Duration, stats KeyValues) {
+	stats = KeyValues{}
+	for _, playStat := range []PlayStat{stat.QuickPlay, stat.CompetitivePlay} {
+		for _, careerStat := range playStat.CareerStats {
+			if careerStat.HeroName != heroName {
+				continue
+			}
+
+			for _, category := range careerStat.Categories {
+				if containsLabel(labelsHeroSpecific, category.Name) || containsLabel(labelsBest, category.Name) {
+					for _, value := range category.Values {
+						if _, exists := stats.Lookup(value.Key); !exists {
+							stats = append(stats, value)
+						}
+					}
+				}
+				for _, value := range category.Values {
+					if containsLabel(labelsTimePlayed, value.Key) {
+						if duration, err := ParseDuration(value.Value); err == nil {
+							timePlayed += duration
+						}
+					}
+				}
+			}
+		}
+	}
+	return timePlayed, stats
+}
+
+// total of values with given labels in career stats of all heroes, in quick and competitive play
+func allHeroesTotal(stat Stat, labels []string) (total float64) {
+	for _, playStat := range []PlayStat{stat.QuickPlay, stat.CompetitivePlay} {
+		for _, careerStat := range playStat.CareerStats {
+			if !IsAllHeroes(careerStat.HeroName) {
+				continue
+			}
+
+			for _, category := range careerStat.Categories {
+				for _, value := range category.Values {
+					if containsLabel(labels, value.Key) {
+						if number, err := ParseNumber(value.Value); err == nil {
+							total += number
+						}
+					}
+				}
+			}
+		}
+	}
+	return total
+}
+
+// check if given text contains one of given keywords (case-insensitive)
+func containsKeyword(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// check if given text contains given name, not as a part of another word (eg. "Ana" in "Banana" or "Anarchy")
+//
+// (in languages without spaces between words, particles can follow names, eg. "아나로")
+func containsName(text, name string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], name)
+		if i < 0 {
+			return false
+		}
+		i += offset
+		end := i + len(name)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (i == 0 || !unicode.IsLetter(before)) && (end == len(text) || !unicode.IsLetter(after) || unicode.In(after, unicode.Hangul, unicode.Han, unicode.Hiragana)) {
+			return true
+		}
+		offset = end
+	}
+}
@@ -0,0 +1,26 @@
+package stat
+
+import (
+	"testing"
+)
+
+func TestContainsName(t *testing.T) {
+	for _, test := range []struct {
+		text, name string
+		expected   bool
+	}{
+		{"Sleep 4 enemies with Biotic Grenade as Ana.", "Ana", true},
+		{"Ana", "Ana", true},
+		{"Banana", "Ana", false},
+		{"Anarchy reigns", "Ana", false},
+		{"Meister werden", "Mei", false},
+		{"Als Mei einfrieren", "Mei", true},
+		{"Anarchy as Ana", "Ana", true},
+		{"아나로 적 4명을 재우십시오.", "아나", true},
+		{"위도우메이커로 처치", "메이", false},
+	} {
+		if containsName(test.text, test.name) != test.expected {
+			t.Errorf("expected containsName('%s', '%s') to be %v", test.text, test.name, test.expected)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package stat_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestNewAchievementReport(t *testing.T) {
+	s := stattest.FixturePcCompetitive.Stat(t)
+	report := stat.NewAchievementReport(s, nil)
+
+	// completion
+	if report.Overall.Achieved != 7 || report.Overall.Total != 19 || math.Abs(report.Overall.Percent-36.84) > 0.01 {
+		t.Errorf("unexpected overall completion: %+v", report.Overall)
+	}
+	if len(report.Categories) != len(s.Achievements) || report.Categories[0].Category != "General" || report.Categories[0].Percent != 20 || report.Categories[1].Percent != 100 {
+		t.Errorf("unexpected completions of categories: %+v", report.Categories)
+	}
+	if len(report.Timeline) != 0 {
+		t.Errorf("there should be no timeline without snapshots")
+	}
+
+	// closest: hero-specific ones by time played of their heroes, then others
+	// (counted ones are not measurable, as their targets are already reached while they are locked)
+	if len(report.Closest) != report.Overall.Total-report.Overall.Achieved {
+		t.Fatalf("expected %d non-achieved achievements, got %d", report.Overall.Total-report.Overall.Achieved, len(report.Closest))
+	}
+	var naptime, coldSnap stat.AchievementProgress
+	for i, p := range report.Closest {
+		switch p.Title {
+		case "Level 10", "Decorated":
+			if p.Measured || p.Progress != 0 {
+				t.Errorf("locked achievements with reached targets should not be measured: %+v", p)
+			}
+		case "Naptime":
+			naptime = p
+			if i != 0 {
+				t.Errorf("the most played hero's achievement should be the first, but it was at %d", i)
+			}
+		case "Cold Snap":
+			coldSnap = p
+		case "Cake Walk":
+			if p.Measured || p.Hero != "" || i < len(report.Closest)-2 {
+				t.Errorf("achievements without progress should be at the end: %+v", p)
+			}
+		}
+	}
+	if naptime.Hero != "Ana" || naptime.TimePlayed != "109h 34m" || naptime.Stats.Get("Enemies Slept") != "51" {
+		t.Errorf("unexpected progress of Naptime: %+v", naptime)
+	}
+	if coldSnap.Hero != "Mei" || coldSnap.TimePlayed != "" || len(coldSnap.Stats) != 0 {
+		t.Errorf("unexpected progress of Cold Snap: %+v", coldSnap)
+	}
+
+	// localized
+	korean := stat.NewAchievementReport(stattest.FixturePcCompetitiveKorean.Stat(t), nil)
+	heroes := 0
+	for _, p := range korean.Closest {
+		if p.Hero != "" {
+			heroes++
+		}
+		if p.Title == "대규모 부활" && p.Hero != "메르시" {
+			t.Errorf("unexpected hero of Huge Rez in korean: %s", p.Hero)
+		}
+	}
+	if heroes != 6 {
+		t.Errorf("expected 6 hero-specific achievements in korean, got %d", heroes)
+	}
+}
+
+func TestClosestAchievements(t *testing.T) {
+	achievements := func(descriptions ...string) []stat.AchievementCategory {
+		category := stat.AchievementCategory{Name: "General", Achieved: []stat.Achievement{}}
+		for _, description := range descriptions {
+			category.NonAchieved = append(category.NonAchieved, stat.Achievement{Title: description, Description: description})
+		}
+		return []stat.AchievementCategory{category}
+	}
+	allHeroes := func(name, label, value string) []stat.CareerStat {
+		return []stat.CareerStat{{HeroName: name, Categories: []stat.CareerStatCategory{{Name: "", Values: stat.KeyValues{{Key: label, Value: value}}}}}}
+	}
+
+	// counted ones by their progress, not confused by names of heroes in other words
+	s := stat.Stat{
+		Language:     "en-us",
+		Level:        5,
+		QuickPlay:    stat.PlayStat{CareerStats: allHeroes("ALL HEROES", "Medals", "20")},
+		Achievements: achievements("Anarchy reigns.", "Reach level 10.", "Earn 25 medals."),
+	}
+	closest := stat.ClosestAchievements(s)
+	if p := closest[0]; !p.Measured || p.Title != "Earn 25 medals." || p.Current != 20 || p.Progress != 0.8 {
+		t.Errorf("unexpected progress of medals: %+v", p)
+	}
+	if p := closest[1]; !p.Measured || p.Current != 5 || p.Target != 10 || p.Progress != 0.5 {
+		t.Errorf("unexpected progress of level: %+v", p)
+	}
+	if p := closest[2]; p.Measured || p.Hero != "" {
+		t.Errorf("'Anarchy' should not be an achievement of Ana: %+v", p)
+	}
+
+	// in french
+	s = stat.Stat{
+		Language:     "fr-fr",
+		Level:        30,
+		QuickPlay:    stat.PlayStat{CareerStats: allHeroes("TOUS LES HÉROS", "Médailles", "10")},
+		Achievements: achievements("Obtenez 25 médailles.", "Atteignez le niveau 25.", "Endormez 4 ennemis en jouant Ana."),
+	}
+	closest = stat.ClosestAchievements(s)
+	if p := closest[0]; !p.Measured || p.Current != 10 || p.Target != 25 {
+		t.Errorf("unexpected progress of medals in french: %+v", p)
+	}
+	if p := closest[1]; p.Hero != "Ana" {
+		t.Errorf("unexpected progress of a hero-specific achievement in french: %+v", p)
+	}
+	if p := closest[2]; p.Measured {
+		t.Errorf("locked achievements with reached targets should not be measured: %+v", p)
+	}
+}
+
+func TestAchievementTimeline(t *testing.T) {
+	base := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	achievement := func(title string) stat.Achievement {
+		return stat.Achievement{Title: title, ImageUrl: "https://example.com/achievements/" + title + ".png"}
+	}
+	snapshot := func(days int, achieved ...string) stat.Snapshot {
+		category := stat.AchievementCategory{Name: "General", Achieved: []stat.Achievement{}}
+		for _, title := range achieved {
+			category.Achieved = append(category.Achieved, achievement(title))
+		}
+		return stat.Snapshot{Time: base.AddDate(0, 0, days), Stat: stat.Stat{Achievements: []stat.AchievementCategory{category}}}
+	}
+
+	// (out of order)
+	timeline := stat.AchievementTimeline([]stat.Snapshot{
+		snapshot(10, "a", "b", "c"),
+		snapshot(0, "a"),
+		snapshot(5, "a", "b"),
+		snapshot(20, "a", "b", "c"),
+	})
+	if len(timeline) != 3 {
+		t.Fatalf("expected 3 unlocks, got %d", len(timeline))
+	}
+	for i, expected := range []struct {
+		title   string
+		days    int
+		initial bool
+	}{
+		{"a", 0, true},
+		{"b", 5, false},
+		{"c", 10, false},
+	} {
+		if unlock := timeline[i]; unlock.Title != expected.title || !unlock.UnlockedAt.Equal(base.AddDate(0, 0, expected.days)) || unlock.Initial != expected.initial {
+			t.Errorf("unexpected unlock at %d: %+v", i, unlock)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package stat
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// remote assets (images, fonts) referenced in html: src="..." attributes, and url(...)s in css
+var htmlAssetRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`\bsrc\s*=\s*"(https?://[^"]+)"`),
+	regexp.MustCompile(`\burl\(\s*['"]?(https?://[^'")\s]+)['"]?\s*\)`),
+}
+
+// fetched asset
+type asset struct {
+	bytes       []byte
+	contentType string
+}
+
+// limits of cached assets
+const (
+	AssetCacheMaxBytes    = 32 * 1024 * 1024  // in memory
+	AssetCacheDirMaxBytes = 256 * 1024 * 1024 // in AssetCacheDir
+	AssetCacheTtl         = 24 * time.Hour    // both in memory and in AssetCacheDir
+)
+
+// cache of fetched assets (keyed by url)
+var assetCache = newAssetLru(AssetCacheMaxBytes, AssetCacheTtl)
+
+// directory for caching fetched assets as files, so they can be reused across runs
+// (when empty, assets are cached in memory only)
+var AssetCacheDir string = ""
+
+// names of cache files in AssetCacheDir (sha1 of urls)
+var assetCacheFilenameRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// clear cached assets (images and fonts fetched for banners and html reports) in memory
+//
+// (files in AssetCacheDir are not removed)
+func ClearAssetCache() {
+	assetCache.clear()
+}
+
+// inline all remote images and fonts referenced in given html as data uris, so it can be viewed offline
+//
+// (assets which cannot be fetched are left as they are)
+func InlineHtmlAssets(htmlStr string) string {
+	failed := map[string]bool{} // (not to retry failed ones)
+
+	for _, re := range htmlAssetRegexps {
+		htmlStr = re.ReplaceAllStringFunc(htmlStr, func(match string) string {
+			escaped := re.FindStringSubmatch(match)[1]
+			if failed[escaped] {
+				return match
+			}
+
+			if dataUri, err := getDataUri(html.UnescapeString(escaped)); err == nil {
+				return strings.Replace(match, escaped, dataUri, 1)
+			} else {
+				if Verbose {
+					log.Printf("> failed to inline asset: %s\n", err)
+				}
+				failed[escaped] = true
+				return match
+			}
+		})
+	}
+	return htmlStr
+}
+
+// read file from given url, and return it as a data uri
+func getDataUri(url string) (string, error) {
+	if asset, err := fetchAsset(url); err == nil {
+		return fmt.Sprintf("data:%s;base64,%s", asset.contentType, base64.StdEncoding.EncodeToString(asset.bytes)), nil
+	} else {
+		return "", err
+	}
+}
+
+// read file from given url (or from the cache, if it was fetched before)
+func fetchAsset(url string) (asset, error) {
+	if cached, exists := assetCache.get(url); exists {
+		return cached, nil
+	}
+	if cached, err := loadCachedAsset(url); err == nil {
+		assetCache.put(url, cached)
+
+		return cached, nil
+	}
+
+	if Verbose {
+		log.Printf("> fetching asset from url: %s\n", url)
+	}
+
+	if res, err := http.Get(url); err == nil {
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return asset{}, fmt.Errorf("failed to get %s: %s", url, res.Status)
+		}
+
+		if bytes, err := ioutil.ReadAll(res.Body); err == nil {
+			contentType := res.Header.Get("Content-Type")
+			if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") {
+				if contentType = mime.TypeByExtension(path.Ext(url)); contentType == "" {
+					contentType = http.DetectContentType(bytes)
+				}
+			}
+
+			fetched := asset{bytes: bytes, contentType: contentType}
+
+			assetCache.put(url, fetched)
+
+			if err := storeCachedAsset(url, fetched); err != nil && Verbose {
+				log.Printf("> failed to cache asset: %s\n", err)
+			}
+
+			return fetched, nil
+		} else {
+			return asset{}, err
+		}
+	} else {
+		return asset{}, err
+	}
+}
+
+// path of the cache file of given url
+func assetCachePath(url string) string {
+	return filepath.Join(AssetCacheDir, fmt.Sprintf("%x", sha1.Sum([]byte(url))))
+}
+
+// load an asset from AssetCacheDir
+//
+// (cache file has the content type in its first line, and the bytes after it; expired ones are removed)
+func loadCachedAsset(url string) (asset, error) {
+	if AssetCacheDir == "" {
+		return asset{}, fmt.Errorf("no cache directory")
+	}
+
+	cachePath := assetCachePath(url)
+	if info, err := os.Stat(cachePath); err == nil {
+		if time.Since(info.ModTime()) > AssetCacheTtl {
+			os.Remove(cachePath)
+			return asset{}, fmt.Errorf("expired cache file of %s", url)
+		}
+	} else {
+		return asset{}, err
+	}
+
+	if b, err := ioutil.ReadFile(cachePath); err == nil {
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			return asset{bytes: b[i+1:], contentType: string(b[:i])}, nil
+		}
+		return asset{}, fmt.Errorf("malformed cache file of %s", url)
+	} else {
+		return asset{}, err
+	}
+}
+
+// store an asset to AssetCacheDir (if it is set)
+func storeCachedAsset(url string, a asset) error {
+	if AssetCacheDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(AssetCacheDir, 0750); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(assetCachePath(url), append([]byte(a.contentType+"\n"), a.bytes...), 0640); err != nil {
+		return err
+	}
+	return pruneAssetCacheDir(AssetCacheDirMaxBytes)
+}
+
+// remove the oldest cache files in AssetCacheDir until their total size is within given bytes
+//
+// (other files in the directory are left as they are)
+func pruneAssetCacheDir(maxBytes int64) error {
+	infos, err := ioutil.ReadDir(AssetCacheDir)
+	if err != nil {
+		return err
+	}
+
+	files := []os.FileInfo{}
+	var total int64
+	for _, info := range infos {
+		if info.Mode().IsRegular() && assetCacheFilenameRegexp.MatchString(info.Name()) {
+			files = append(files, info)
+			total += info.Size()
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	for _, info := range files {
+		if total <= maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(AssetCacheDir, info.Name())); err != nil {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
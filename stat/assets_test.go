@@ -0,0 +1,96 @@
+package stat_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestInlineHtmlAssets(t *testing.T) {
+	images := newImageServer(t)
+
+	// count requests, to check if assets are cached
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/font.ttf":
+			w.Header().Set("Content-Type", "font/ttf")
+			w.Write(goregular.TTF)
+		case "/missing.png":
+			http.NotFound(w, r)
+		default:
+			http.Redirect(w, r, images.URL+r.URL.Path, http.StatusFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	stat.ClearAssetCache()
+
+	s := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)
+	for _, comparison := range s.CompetitivePlay.TopHeroes {
+		for i := range comparison.Heroes {
+			comparison.Heroes[i].ImageUrl = server.URL + "/hero.png"
+		}
+	}
+	for i := range s.Achievements {
+		for j := range s.Achievements[i].Achieved {
+			s.Achievements[i].Achieved[j].ImageUrl = server.URL + "/achievement.png"
+		}
+		for j := range s.Achievements[i].NonAchieved {
+			s.Achievements[i].NonAchieved[j].ImageUrl = server.URL + "/missing.png"
+		}
+	}
+
+	html, err := stat.RenderStatToHtml(s, stat.SampleHtmlTemplate)
+	if err != nil {
+		t.Fatalf("failed to render html: %s", err)
+	}
+	html = strings.Replace(html, stat.KoverwatchFontUrl, server.URL+"/font.ttf", -1)
+
+	inlined := stat.InlineHtmlAssets(html)
+	for _, expected := range []string{
+		`<img src="data:image/png;base64,`,
+		`url(data:image/png;base64,`,
+		`url(data:font/ttf;base64,`,
+		`src="` + server.URL + `/missing.png"`, // (not fetched, so left as it is)
+	} {
+		if !strings.Contains(inlined, expected) {
+			t.Errorf("expected '%s' in inlined html", expected)
+		}
+	}
+	if strings.Count(inlined, server.URL) != strings.Count(html, server.URL+"/missing.png") {
+		t.Errorf("expected all assets except missing ones to be inlined")
+	}
+
+	// fetched only once for each url (missing ones are not cached)
+	fetched := atomic.LoadInt32(&requests)
+	stat.InlineHtmlAssets(html)
+	if again := atomic.LoadInt32(&requests) - fetched; again != 1 {
+		t.Errorf("expected only the missing asset to be fetched again, got %d requests", again)
+	}
+
+	// cached as files, so they are not fetched again even after clearing the cache in memory
+	stat.AssetCacheDir = t.TempDir()
+	t.Cleanup(func() { stat.AssetCacheDir = "" })
+
+	stat.ClearAssetCache()
+	stat.InlineHtmlAssets(html)
+	fetched = atomic.LoadInt32(&requests)
+
+	stat.ClearAssetCache()
+	if cached := stat.InlineHtmlAssets(html); cached != inlined {
+		t.Errorf("expected same html with cached assets")
+	}
+	if again := atomic.LoadInt32(&requests) - fetched; again != 1 {
+		t.Errorf("expected only the missing asset to be fetched again from the cache directory, got %d requests", again)
+	}
+}
@@ -0,0 +1,215 @@
+package stat
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// names of built-in banner themes
+const (
+	BannerThemeDefault   = "default"   // 320x50, the original layout
+	BannerThemeSignature = "signature" // 468x60, wide forum signature with top hero
+	BannerThemeSquare    = "square"    // 200x200, square avatar
+	BannerThemeTwitter   = "twitter"   // 1200x630, twitter card
+)
+
+// position and size of an element on banners
+type BannerElement struct {
+	Show bool
+
+	Rect     image.Rectangle // area of the element's image (eg. logo, portrait, level, rank icon, ...)
+	TextAt   image.Point     // baseline position of the element's text (eg. battletag, level, rank, ...)
+	FontSize float64
+}
+
+// layout and colors of a banner
+type BannerSpec struct {
+	Name   string
+	Width  int
+	Height int
+
+	BackgroundColor color.RGBA
+	TextColor       color.RGBA
+
+	LogoImageUrl string // when empty, OverwatchLogoImageUrl will be used
+
+	Logo        BannerElement // image only
+	Portrait    BannerElement // image only
+	BattleTag   BannerElement // text only (battletag, platform, and region)
+	Detail      BannerElement // text only
+	Level       BannerElement // level frame (stars will be drawn on its lower half) and level
+	Rank        BannerElement // rank icon and competitive rank
+	TopHero     BannerElement // portrait of the most played hero and its time played
+	Endorsement BannerElement // ring of endorsement breakdown and endorsement level
+}
+
+// the original banner layout
+var DefaultBannerSpec = BannerSpec{
+	Name:   BannerThemeDefault,
+	Width:  BannerWidth,
+	Height: BannerHeight,
+
+	BackgroundColor: color.RGBA{64, 82, 117, 255}, // #405275
+	TextColor:       color.RGBA{255, 255, 255, 255},
+
+	Logo: BannerElement{
+		Show: true,
+		Rect: image.Rect(0, 0, BannerHeight, BannerHeight),
+	},
+	Portrait: BannerElement{
+		Show: true,
+		Rect: image.Rect(BannerWidth-BannerHeight, 0, BannerWidth, BannerHeight),
+	},
+	BattleTag: BannerElement{
+		Show:     true,
+		TextAt:   image.Pt(BannerHeight+Margin, 17),
+		FontSize: FontSizeBattleTag,
+	},
+	Detail: BannerElement{
+		Show:     true,
+		TextAt:   image.Pt(BannerHeight+Margin, 44),
+		FontSize: FontSizeDetail,
+	},
+	Level: BannerElement{
+		Show:     true,
+		Rect:     image.Rect(BannerWidth-BannerHeight*2, 0, BannerWidth-BannerHeight, BannerLevelBgSize),
+		TextAt:   image.Pt(238, 29),
+		FontSize: FontSizeLevel,
+	},
+	Rank: BannerElement{
+		Show:     true,
+		Rect:     image.Rect(BannerWidth-BannerHeight*2-BannerRankIconSize, 0, BannerWidth-BannerHeight*2, BannerRankIconSize),
+		TextAt:   image.Pt(194, 43),
+		FontSize: FontSizeRank,
+	},
+	TopHero: BannerElement{
+		Show: false,
+	},
+	Endorsement: BannerElement{
+		Show:     false,
+		Rect:     image.Rect(BannerWidth-BannerEndorsementSize-1, BannerHeight-BannerEndorsementSize-1, BannerWidth-1, BannerHeight-1),
+		TextAt:   image.Pt(307, 43),
+		FontSize: FontSizeEndorsement,
+	},
+}
+
+// built-in banner themes
+var BannerThemes = map[string]BannerSpec{
+	BannerThemeDefault: DefaultBannerSpec,
+	BannerThemeSignature: {
+		Name:   BannerThemeSignature,
+		Width:  468,
+		Height: 60,
+
+		BackgroundColor: color.RGBA{64, 82, 117, 255},
+		TextColor:       color.RGBA{255, 255, 255, 255},
+
+		Logo:        BannerElement{Show: true, Rect: image.Rect(0, 0, 60, 60)},
+		Portrait:    BannerElement{Show: true, Rect: image.Rect(408, 0, 468, 60)},
+		BattleTag:   BannerElement{Show: true, TextAt: image.Pt(66, 22), FontSize: 17},
+		Detail:      BannerElement{Show: true, TextAt: image.Pt(66, 52), FontSize: 14},
+		Level:       BannerElement{Show: true, Rect: image.Rect(348, 0, 408, 60), TextAt: image.Pt(369, 35), FontSize: 12},
+		Rank:        BannerElement{Show: true, Rect: image.Rect(300, 2, 340, 42), TextAt: image.Pt(306, 55), FontSize: 12},
+		TopHero:     BannerElement{Show: true, Rect: image.Rect(252, 4, 288, 40), TextAt: image.Pt(248, 55), FontSize: 10},
+		Endorsement: BannerElement{Show: true, Rect: image.Rect(447, 39, 467, 59), TextAt: image.Pt(454, 53), FontSize: 11},
+	},
+	BannerThemeSquare: {
+		Name:   BannerThemeSquare,
+		Width:  200,
+		Height: 200,
+
+		BackgroundColor: color.RGBA{64, 82, 117, 255},
+		TextColor:       color.RGBA{255, 255, 255, 255},
+
+		Logo:        BannerElement{Show: true, Rect: image.Rect(6, 6, 42, 42)},
+		Portrait:    BannerElement{Show: true, Rect: image.Rect(50, 8, 150, 108)},
+		BattleTag:   BannerElement{Show: true, TextAt: image.Pt(10, 130), FontSize: 16},
+		Detail:      BannerElement{Show: false},
+		Level:       BannerElement{Show: true, Rect: image.Rect(10, 140, 60, 190), TextAt: image.Pt(28, 169), FontSize: 11},
+		Rank:        BannerElement{Show: true, Rect: image.Rect(75, 142, 115, 182), TextAt: image.Pt(81, 195), FontSize: 11},
+		TopHero:     BannerElement{Show: true, Rect: image.Rect(135, 142, 175, 182), TextAt: image.Pt(130, 195), FontSize: 10},
+		Endorsement: BannerElement{Show: true, Rect: image.Rect(128, 86, 150, 108), TextAt: image.Pt(135, 101), FontSize: 11},
+	},
+	BannerThemeTwitter: {
+		Name:   BannerThemeTwitter,
+		Width:  1200,
+		Height: 630,
+
+		BackgroundColor: color.RGBA{64, 82, 117, 255},
+		TextColor:       color.RGBA{255, 255, 255, 255},
+
+		Logo:        BannerElement{Show: true, Rect: image.Rect(40, 40, 200, 200)},
+		Portrait:    BannerElement{Show: true, Rect: image.Rect(900, 40, 1160, 300)},
+		BattleTag:   BannerElement{Show: true, TextAt: image.Pt(230, 110), FontSize: 64},
+		Detail:      BannerElement{Show: true, TextAt: image.Pt(230, 180), FontSize: 40},
+		Level:       BannerElement{Show: true, Rect: image.Rect(60, 300, 300, 540), TextAt: image.Pt(146, 439), FontSize: 48},
+		Rank:        BannerElement{Show: true, Rect: image.Rect(360, 320, 540, 500), TextAt: image.Pt(392, 570), FontSize: 48},
+		TopHero:     BannerElement{Show: true, Rect: image.Rect(620, 320, 800, 500), TextAt: image.Pt(600, 570), FontSize: 36},
+		Endorsement: BannerElement{Show: true, Rect: image.Rect(1060, 200, 1160, 300), TextAt: image.Pt(1098, 264), FontSize: 40},
+	},
+}
+
+// get a built-in banner theme with given name
+func BannerTheme(name string) (BannerSpec, error) {
+	if spec, exists := BannerThemes[name]; exists {
+		return spec, nil
+	}
+	return BannerSpec{}, fmt.Errorf("no such banner theme: %s (available: %v)", name, BannerThemeNames())
+}
+
+// names of built-in banner themes, sorted
+func BannerThemeNames() []string {
+	names := []string{}
+	for name := range BannerThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// url of logo image of this spec
+func (s BannerSpec) logoImageUrl() string {
+	if s.LogoImageUrl != "" {
+		return s.LogoImageUrl
+	}
+	return OverwatchLogoImageUrl
+}
+
+// get the most played hero of quick play
+func mostPlayedHero(stat Stat) (hero Hero, exists bool) {
+	if heroes := timePlayedHeroes(stat); len(heroes) > 0 {
+		return heroes[0], true
+	}
+	return Hero{}, false
+}
+
+// get top heroes of quick play, ordered by their time played
+func timePlayedHeroes(stat Stat) []Hero {
+	return timePlayedHeroesOf(stat.QuickPlay)
+}
+
+// get top heroes of given play stat, ordered by their time played
+//
+// (heroes of the first top heroes' comparison which has durations as values, eg. "Time Played")
+func timePlayedHeroesOf(playStat PlayStat) []Hero {
+	for _, comparison := range playStat.TopHeroes {
+		heroes := comparison.Heroes
+		if len(heroes) <= 0 {
+			continue
+		}
+
+		durations := true
+		for _, hero := range heroes {
+			if _, err := ParseDuration(hero.Value); err != nil {
+				durations = false
+				break
+			}
+		}
+		if durations {
+			return heroes
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package stat_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// start a server which responds with a placeholder .png image for any path
+func newImageServer(t *testing.T) *httptest.Server {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 128, 255})
+		}
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatalf("failed to encode placeholder image: %s", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buffer.Bytes())
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// parse a fixture, and point all its image urls to given server
+func statWithLocalImages(t *testing.T, fixture stattest.Fixture, serverUrl string) stat.Stat {
+	s := fixture.Stat(t)
+
+	s.ProfileImageUrl = serverUrl + "/portrait.png"
+	s.LevelImageUrl = serverUrl + "/level.png"
+	if s.LevelStarImageUrl != "" {
+		s.LevelStarImageUrl = serverUrl + "/stars.png"
+	}
+	if s.CompetitiveRankImageUrl != "" {
+		s.CompetitiveRankImageUrl = serverUrl + "/rank.png"
+	}
+	for _, comparison := range s.QuickPlay.TopHeroes {
+		for i := range comparison.Heroes {
+			comparison.Heroes[i].ImageUrl = serverUrl + "/hero.png"
+		}
+	}
+	return s
+}
+
+func testFont(t *testing.T) *truetype.Font {
+	font, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %s", err)
+	}
+	return font
+}
+
+func TestRenderBannerThemes(t *testing.T) {
+	server := newImageServer(t)
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	font := testFont(t)
+
+	for _, fixture := range []stattest.Fixture{stattest.FixturePcCompetitive, stattest.FixturePcNoCompetitive, stattest.FixturePcPrivate} {
+		s := statWithLocalImages(t, fixture, server.URL)
+
+		for _, name := range stat.BannerThemeNames() {
+			spec, err := stat.BannerTheme(name)
+			if err != nil {
+				t.Fatalf("failed to get banner theme %s: %s", name, err)
+			}
+
+			pngBytes, err := stat.RenderStatToPngBytesWithSpec(s, spec, logo, font)
+			if err != nil {
+				t.Errorf("failed to render %s with theme %s: %s", fixture.Name, name, err)
+				continue
+			}
+
+			img, err := png.Decode(bytes.NewReader(pngBytes))
+			if err != nil {
+				t.Errorf("failed to decode banner of %s with theme %s: %s", fixture.Name, name, err)
+				continue
+			}
+			if img.Bounds().Dx() != spec.Width || img.Bounds().Dy() != spec.Height {
+				t.Errorf("expected %dx%d banner for theme %s, got %v", spec.Width, spec.Height, name, img.Bounds())
+			}
+		}
+	}
+
+	if _, err := stat.BannerTheme("no-such-theme"); err == nil {
+		t.Errorf("expected an error for unknown banner theme")
+	}
+}
+
+func TestRenderSvgBanner(t *testing.T) {
+	server := newImageServer(t)
+	s := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)
+
+	for _, embedImages := range []bool{true, false} {
+		for _, name := range stat.BannerThemeNames() {
+			spec, _ := stat.BannerTheme(name)
+			spec.LogoImageUrl = server.URL + "/logo.png"
+			spec.Endorsement.Show = true
+
+			svg, err := stat.RenderStatToSvg(s, spec, embedImages)
+			if err != nil {
+				t.Fatalf("failed to render svg with theme %s: %s", name, err)
+			}
+
+			// should be a well-formed xml
+			decoder := xml.NewDecoder(strings.NewReader(svg))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("malformed svg with theme %s: %s\n%s", name, err, svg)
+				}
+			}
+
+			if embedImages {
+				if strings.Contains(svg, server.URL) || !strings.Contains(svg, `href="data:image/png;base64,`) {
+					t.Errorf("images should be embedded as data uris with theme %s", name)
+				}
+			} else if !strings.Contains(svg, server.URL+"/portrait.png") {
+				t.Errorf("images should be linked with theme %s", name)
+			}
+			if !strings.Contains(svg, s.BattleTag) {
+				t.Errorf("battletag should be in svg with theme %s", name)
+			}
+		}
+	}
+}
+
+func TestRenderJpegAndGifBanners(t *testing.T) {
+	server := newImageServer(t)
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	font := testFont(t)
+	s := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)
+
+	for _, name := range stat.BannerThemeNames() {
+		spec, _ := stat.BannerTheme(name)
+
+		jpegBytes, err := stat.RenderStatToJpegBytes(s, spec, logo, font, stat.DefaultJpegQuality)
+		if err != nil {
+			t.Fatalf("failed to render jpeg with theme %s: %s", name, err)
+		}
+		if img, err := jpeg.Decode(bytes.NewReader(jpegBytes)); err != nil {
+			t.Errorf("failed to decode jpeg banner with theme %s: %s", name, err)
+		} else if img.Bounds().Dx() != spec.Width || img.Bounds().Dy() != spec.Height {
+			t.Errorf("expected %dx%d jpeg banner for theme %s, got %v", spec.Width, spec.Height, name, img.Bounds())
+		}
+
+		gifBytes, err := stat.RenderStatToGifBytes(s, spec, logo, font)
+		if err != nil {
+			t.Fatalf("failed to render gif with theme %s: %s", name, err)
+		}
+		if img, err := gif.Decode(bytes.NewReader(gifBytes)); err != nil {
+			t.Errorf("failed to decode gif banner with theme %s: %s", name, err)
+		} else if img.Bounds().Dx() != spec.Width || img.Bounds().Dy() != spec.Height {
+			t.Errorf("expected %dx%d gif banner for theme %s, got %v", spec.Width, spec.Height, name, img.Bounds())
+		}
+	}
+
+	// lower quality should result in smaller files
+	high, _ := stat.RenderStatToJpegBytes(s, stat.DefaultBannerSpec, logo, font, 100)
+	low, _ := stat.RenderStatToJpegBytes(s, stat.DefaultBannerSpec, logo, font, 10)
+	if len(low) >= len(high) {
+		t.Errorf("expected a smaller jpeg with lower quality, got %d >= %d bytes", len(low), len(high))
+	}
+}
+
+func TestRenderAnimatedGifBanner(t *testing.T) {
+	server := newImageServer(t)
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	font := testFont(t)
+
+	for _, test := range []struct {
+		fixture stattest.Fixture
+		themes  []string
+		panels  int
+	}{
+		{stattest.FixturePcCompetitive, stat.BannerThemeNames(), 4},             // profile, rank, top heroes, and featured stats
+		{stattest.FixturePcNoCompetitive, []string{stat.BannerThemeDefault}, 3}, // no rank
+		{stattest.FixturePcPrivate, []string{stat.BannerThemeDefault}, 1},       // profile only
+	} {
+		s := statWithLocalImages(t, test.fixture, server.URL)
+
+		for _, name := range test.themes {
+			spec, _ := stat.BannerTheme(name)
+
+			gifBytes, err := stat.RenderStatToAnimatedGifBytes(s, spec, logo, font, 2*time.Second)
+			if err != nil {
+				t.Fatalf("failed to render animated gif of %s with theme %s: %s", test.fixture.Name, name, err)
+			}
+
+			anim, err := gif.DecodeAll(bytes.NewReader(gifBytes))
+			if err != nil {
+				t.Fatalf("failed to decode animated gif of %s with theme %s: %s", test.fixture.Name, name, err)
+			}
+			if len(anim.Image) != test.panels {
+				t.Errorf("expected %d panels of %s with theme %s, got %d", test.panels, test.fixture.Name, name, len(anim.Image))
+			}
+			for i, frame := range anim.Image {
+				if frame.Bounds().Dx() != spec.Width || frame.Bounds().Dy() != spec.Height {
+					t.Errorf("expected %dx%d panel #%d for theme %s, got %v", spec.Width, spec.Height, i, name, frame.Bounds())
+				}
+				if anim.Delay[i] != 200 {
+					t.Errorf("expected delay of 200 (100ths of a second), got %d", anim.Delay[i])
+				}
+			}
+		}
+	}
+}
+
+func TestRenderHeroBanner(t *testing.T) {
+	server := newImageServer(t)
+	font := testFont(t)
+
+	for _, test := range []struct {
+		fixture     stattest.Fixture
+		heroName    string
+		competitive bool
+	}{
+		{stattest.FixturePcCompetitive, "Ana", false},
+		{stattest.FixturePcCompetitive, "ana", true},
+		{stattest.FixturePcCompetitiveKorean, "아나", false},
+		{stattest.FixturePsnConsoleGerman, "Ana", false},
+	} {
+		s := statWithLocalImages(t, test.fixture, server.URL)
+		for _, comparison := range s.CompetitivePlay.TopHeroes {
+			for i := range comparison.Heroes {
+				comparison.Heroes[i].ImageUrl = server.URL + "/hero.png"
+			}
+		}
+
+		pngBytes, err := stat.RenderHeroBanner(s, test.heroName, stat.HeroBannerOptions{Competitive: test.competitive, Font: font})
+		if err != nil {
+			t.Errorf("failed to render hero banner of %s for %s: %s", test.heroName, test.fixture.Name, err)
+			continue
+		}
+
+		img, err := png.Decode(bytes.NewReader(pngBytes))
+		if err != nil {
+			t.Errorf("failed to decode hero banner of %s for %s: %s", test.heroName, test.fixture.Name, err)
+			continue
+		}
+		if img.Bounds().Dx() != stat.HeroBannerWidth || img.Bounds().Dy() != stat.HeroBannerHeight {
+			t.Errorf("expected %dx%d hero banner, got %v", stat.HeroBannerWidth, stat.HeroBannerHeight, img.Bounds())
+		}
+	}
+
+	// custom size
+	s := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)
+	if pngBytes, err := stat.RenderHeroBanner(s, "Reinhardt", stat.HeroBannerOptions{Width: 600, Height: 200, Font: font}); err != nil {
+		t.Errorf("failed to render hero banner with custom size: %s", err)
+	} else if img, err := png.Decode(bytes.NewReader(pngBytes)); err != nil || img.Bounds().Dx() != 600 || img.Bounds().Dy() != 200 {
+		t.Errorf("expected 600x200 hero banner, got %v (%v)", img, err)
+	}
+
+	// heroes not in top heroes
+	if _, err := stat.RenderHeroBanner(s, "No Such Hero", stat.HeroBannerOptions{Font: font}); err == nil {
+		t.Errorf("expected an error for a hero not in top heroes")
+	}
+	private := statWithLocalImages(t, stattest.FixturePcPrivate, server.URL)
+	if _, err := stat.RenderHeroBanner(private, "Ana", stat.HeroBannerOptions{Font: font}); err == nil {
+		t.Errorf("expected an error for a private profile")
+	}
+}
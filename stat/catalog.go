@@ -0,0 +1,705 @@
+package stat
+
+import (
+	"strings"
+)
+
+// languages of career pages on the site
+var SupportedLanguages = []string{
+	"de-de",
+	"en-gb",
+	"en-us",
+	"es-es",
+	"es-mx",
+	"fr-fr",
+	"it-it",
+	"ja-jp",
+	"ko-kr",
+	"pl-pl",
+	"pt-br",
+	"ru-ru",
+	"zh-tw",
+}
+
+// message catalog: translations of labels in html reports, banners, charts, and overlays
+//
+// (keyed by language, then by english label; english ones are used as they are)
+var labelCatalog = map[string]map[string]string{
+	"de-de": {
+		"Achievements":          "Erfolge",
+		"Average Rank":          "Durchschnittliche Wertung",
+		"Career Stats":          "Karrierestatistiken",
+		"Charts":                "Diagramme",
+		"Competitive":           "Gewertet",
+		"Competitive Play":      "Gewertete Spiele",
+		"Competitive Rank":      "Wertung",
+		"Eliminations per Life": "Eliminierungen pro Leben",
+		"Endorsement Level":     "Empfehlungsstufe",
+		"Featured Stats":        "Wichtige Statistiken",
+		"Fetch error":           "Abruffehler",
+		"Fetching...":           "Wird abgerufen...",
+		"Good Teammate":         "Guter Teamkamerad",
+		"Hero Pool":             "Heldenpool",
+		"Inactive":              "Inaktiv",
+		"Inactive since":        "Inaktiv seit",
+		"Level":                 "Stufe",
+		"Loading...":            "Wird geladen...",
+		"Median Rank":           "Median der Wertung",
+		"Others":                "Andere",
+		"Private Profile":       "Privates Profil",
+		"Profile":               "Profil",
+		"Quick Play":            "Schnelles Spiel",
+		"Rank Spread":           "Wertungsspanne",
+		"Ranked Members":        "Gewertete Mitglieder",
+		"Session":               "Sitzung",
+		"Shotcaller":            "Shotcaller",
+		"Sportsmanship":         "Sportlichkeit",
+		"Time Played":           "Spielzeit",
+		"Top Heroes":            "Top-Helden",
+		"Uncovered":             "Nicht abgedeckt",
+		"Updated":               "Aktualisiert",
+		"Waiting for stats...":  "Warte auf Statistiken...",
+		"Win Percentage":        "Siegquote",
+		"Stats and achievements of this player are not public.": "Statistiken und Erfolge dieses Spielers sind nicht öffentlich.",
+
+		// labels on career pages
+		"ALL HEROES":              "ALLE HELDEN",
+		"Games Won":               "Gewonnene Spiele",
+		"Games Lost":              "Verlorene Spiele",
+		"Games Tied":              "Unentschiedene Spiele",
+		"Games Played":            "Gespielte Spiele",
+		"Best":                    "Bestwerte",
+		"Deaths":                  "Tode",
+		"Deaths - Average":        "Tode – Durchschnitt",
+		"Deaths - Avg per 10 Min": "Tode – ⌀ pro 10 Min.",
+		"Environmental Deaths":    "Umgebungstode",
+		"Medals":                  "Medaillen",
+		"Hero Specific":           "Heldenspezifisch",
+		"Eliminations":            "Eliminierungen",
+		"Final Blows":             "Todesstöße",
+		"All Damage Done":         "Gesamter verursachter Schaden",
+		"Damage Done":             "Verursachter Schaden",
+		"Healing Done":            "Heilung",
+		"Offensive Assists":       "Offensivunterstützungen",
+		"Defensive Assists":       "Defensivunterstützungen",
+		"Weapon Accuracy":         "Waffengenauigkeit",
+	},
+	"es-es": labelsSpanish,
+	"es-mx": labelsSpanish,
+	"fr-fr": {
+		"Achievements":          "Hauts faits",
+		"Average Rank":          "Classement moyen",
+		"Career Stats":          "Statistiques de carrière",
+		"Charts":                "Graphiques",
+		"Competitive":           "Compétitif",
+		"Competitive Play":      "Partie compétitive",
+		"Competitive Rank":      "Classement compétitif",
+		"Eliminations per Life": "Éliminations par vie",
+		"Endorsement Level":     "Niveau de recommandation",
+		"Featured Stats":        "Statistiques principales",
+		"Fetch error":           "Erreur de récupération",
+		"Fetching...":           "Récupération...",
+		"Good Teammate":         "Bon coéquipier",
+		"Hero Pool":             "Réserve de héros",
+		"Inactive":              "Inactif",
+		"Inactive since":        "Inactif depuis",
+		"Level":                 "Niveau",
+		"Loading...":            "Chargement...",
+		"Median Rank":           "Classement médian",
+		"Others":                "Autres",
+		"Private Profile":       "Profil privé",
+		"Profile":               "Profil",
+		"Quick Play":            "Partie rapide",
+		"Rank Spread":           "Écart de classement",
+		"Ranked Members":        "Membres classés",
+		"Session":               "Session de jeu",
+		"Shotcaller":            "Meneur",
+		"Sportsmanship":         "Fair-play",
+		"Time Played":           "Temps de jeu",
+		"Top Heroes":            "Meilleurs héros",
+		"Uncovered":             "Non couverts",
+		"Updated":               "Mis à jour",
+		"Waiting for stats...":  "En attente des statistiques...",
+		"Win Percentage":        "Pourcentage de victoires",
+		"Stats and achievements of this player are not public.": "Les statistiques et les hauts faits de ce joueur ne sont pas publics.",
+
+		// labels on career pages
+		"ALL HEROES":              "TOUS LES HÉROS",
+		"Games Won":               "Parties gagnées",
+		"Games Lost":              "Parties perdues",
+		"Games Tied":              "Parties nulles",
+		"Games Played":            "Parties jouées",
+		"Best":                    "Meilleur",
+		"Deaths":                  "Morts",
+		"Deaths - Average":        "Morts - Moyenne",
+		"Deaths - Avg per 10 Min": "Morts - Moy. par 10 min",
+		"Environmental Deaths":    "Morts environnementales",
+		"Medals":                  "Médailles",
+		"Hero Specific":           "Spécifique au héros",
+		"Eliminations":            "Éliminations",
+		"Final Blows":             "Coups de grâce",
+		"All Damage Done":         "Total des dégâts infligés",
+		"Damage Done":             "Dégâts infligés",
+		"Healing Done":            "Soins prodigués",
+		"Offensive Assists":       "Assistances offensives",
+		"Defensive Assists":       "Assistances défensives",
+		"Weapon Accuracy":         "Précision de l'arme",
+	},
+	"it-it": {
+		"Achievements":          "Imprese",
+		"Average Rank":          "Grado medio",
+		"Career Stats":          "Statistiche carriera",
+		"Charts":                "Grafici",
+		"Competitive":           "Competitiva",
+		"Competitive Play":      "Partita competitiva",
+		"Competitive Rank":      "Grado competitivo",
+		"Eliminations per Life": "Eliminazioni per vita",
+		"Endorsement Level":     "Livello di apprezzamento",
+		"Featured Stats":        "Statistiche in evidenza",
+		"Fetch error":           "Errore di recupero",
+		"Fetching...":           "Recupero...",
+		"Good Teammate":         "Buon compagno di squadra",
+		"Hero Pool":             "Rosa di eroi",
+		"Inactive":              "Inattivo",
+		"Inactive since":        "Inattivo dal",
+		"Level":                 "Livello",
+		"Loading...":            "Caricamento...",
+		"Median Rank":           "Grado mediano",
+		"Others":                "Altri",
+		"Private Profile":       "Profilo privato",
+		"Profile":               "Profilo",
+		"Quick Play":            "Partita rapida",
+		"Rank Spread":           "Divario di grado",
+		"Ranked Members":        "Membri classificati",
+		"Session":               "Sessione",
+		"Shotcaller":            "Stratega",
+		"Sportsmanship":         "Sportività",
+		"Time Played":           "Tempo di gioco",
+		"Top Heroes":            "Eroi migliori",
+		"Uncovered":             "Non coperti",
+		"Updated":               "Aggiornato",
+		"Waiting for stats...":  "In attesa delle statistiche...",
+		"Win Percentage":        "Percentuale di vittorie",
+		"Stats and achievements of this player are not public.": "Le statistiche e le imprese di questo giocatore non sono pubbliche.",
+
+		// labels on career pages
+		"ALL HEROES":              "TUTTI GLI EROI",
+		"Games Won":               "Partite vinte",
+		"Games Lost":              "Partite perse",
+		"Games Tied":              "Partite pareggiate",
+		"Games Played":            "Partite giocate",
+		"Best":                    "Migliori",
+		"Deaths":                  "Morti",
+		"Deaths - Average":        "Morti - Media",
+		"Deaths - Avg per 10 Min": "Morti - Media per 10 min",
+		"Environmental Deaths":    "Morti ambientali",
+		"Medals":                  "Medaglie",
+		"Hero Specific":           "Specifiche dell'eroe",
+		"Eliminations":            "Eliminazioni",
+		"Final Blows":             "Colpi di grazia",
+		"All Damage Done":         "Danni totali inflitti",
+		"Damage Done":             "Danni inflitti",
+		"Healing Done":            "Cure fornite",
+		"Offensive Assists":       "Assist offensivi",
+		"Defensive Assists":       "Assist difensivi",
+		"Weapon Accuracy":         "Precisione arma",
+	},
+	"ja-jp": {
+		"Achievements":          "実績",
+		"Average Rank":          "平均スキル・レート",
+		"Career Stats":          "キャリア統計",
+		"Charts":                "グラフ",
+		"Competitive":           "ライバル",
+		"Competitive Play":      "ライバル・プレイ",
+		"Competitive Rank":      "スキル・レート",
+		"Eliminations per Life": "ライフごとのキル",
+		"Endorsement Level":     "推薦レベル",
+		"Featured Stats":        "注目の統計",
+		"Fetch error":           "取得エラー",
+		"Fetching...":           "取得中...",
+		"Good Teammate":         "グッド・チームメイト",
+		"Hero Pool":             "ヒーロー・プール",
+		"Inactive":              "非アクティブ",
+		"Inactive since":        "非アクティブ開始",
+		"Level":                 "レベル",
+		"Loading...":            "読み込み中...",
+		"Median Rank":           "スキル・レートの中央値",
+		"Others":                "その他",
+		"Private Profile":       "非公開プロフィール",
+		"Profile":               "プロフィール",
+		"Quick Play":            "クイック・プレイ",
+		"Rank Spread":           "スキル・レートの幅",
+		"Ranked Members":        "ランク付きメンバー",
+		"Session":               "セッション",
+		"Shotcaller":            "指揮官",
+		"Sportsmanship":         "スポーツマンシップ",
+		"Time Played":           "プレイ時間",
+		"Top Heroes":            "トップ・ヒーロー",
+		"Uncovered":             "未カバー",
+		"Updated":               "更新",
+		"Waiting for stats...":  "統計を待っています...",
+		"Win Percentage":        "勝率",
+		"Stats and achievements of this player are not public.": "このプレイヤーの統計と実績は公開されていません。",
+
+		// labels on career pages
+		"ALL HEROES":              "全ヒーロー",
+		"Games Won":               "勝利数",
+		"Games Lost":              "敗北数",
+		"Games Tied":              "引き分け数",
+		"Games Played":            "プレイ回数",
+		"Best":                    "ベスト",
+		"Deaths":                  "デス",
+		"Deaths - Average":        "デス - 平均",
+		"Deaths - Avg per 10 Min": "デス - 10分平均",
+		"Environmental Deaths":    "環境によるデス",
+		"Medals":                  "メダル",
+		"Hero Specific":           "ヒーロー特有",
+		"Eliminations":            "キル",
+		"Final Blows":             "とどめ",
+		"All Damage Done":         "与えたダメージ(合計)",
+		"Damage Done":             "与えたダメージ",
+		"Healing Done":            "回復",
+		"Offensive Assists":       "攻撃アシスト",
+		"Defensive Assists":       "防御アシスト",
+		"Weapon Accuracy":         "武器命中率",
+	},
+	"ko-kr": {
+		"Achievements":          "업적",
+		"Average Rank":          "평균 점수",
+		"Career Stats":          "경력 통계",
+		"Charts":                "차트",
+		"Competitive":           "경쟁전",
+		"Competitive Play":      "경쟁전",
+		"Competitive Rank":      "경쟁전 점수",
+		"Eliminations per Life": "목숨당 처치",
+		"Endorsement Level":     "칭찬 레벨",
+		"Featured Stats":        "주요 통계",
+		"Fetch error":           "가져오기 오류",
+		"Fetching...":           "가져오는 중...",
+		"Good Teammate":         "좋은 팀원",
+		"Hero Pool":             "영웅 폭",
+		"Inactive":              "비활동",
+		"Inactive since":        "비활동 시작",
+		"Level":                 "레벨",
+		"Loading...":            "불러오는 중...",
+		"Median Rank":           "중간 점수",
+		"Others":                "기타",
+		"Private Profile":       "비공개 프로필",
+		"Profile":               "프로필",
+		"Quick Play":            "빠른 대전",
+		"Rank Spread":           "점수 차이",
+		"Ranked Members":        "점수 있는 멤버",
+		"Session":               "세션",
+		"Shotcaller":            "지휘관",
+		"Sportsmanship":         "스포츠맨십",
+		"Time Played":           "플레이 시간",
+		"Top Heroes":            "영웅 순위",
+		"Uncovered":             "미보유",
+		"Updated":               "업데이트",
+		"Waiting for stats...":  "통계를 기다리는 중...",
+		"Win Percentage":        "승률",
+		"Stats and achievements of this player are not public.": "이 플레이어의 통계와 업적은 공개되어 있지 않습니다.",
+
+		// labels on career pages
+		"ALL HEROES":              "모든 영웅",
+		"Games Won":               "승리한 게임",
+		"Games Lost":              "패배한 게임",
+		"Games Tied":              "무승부 게임",
+		"Games Played":            "치른 게임",
+		"Best":                    "최고 기록",
+		"Deaths":                  "죽음",
+		"Deaths - Average":        "죽음 - 평균",
+		"Deaths - Avg per 10 Min": "죽음 - 10분당 평균",
+		"Environmental Deaths":    "환경 요소로 죽음",
+		"Medals":                  "메달",
+		"Hero Specific":           "영웅별",
+		"Eliminations":            "처치",
+		"Final Blows":             "결정타",
+		"All Damage Done":         "준 모든 피해",
+		"Damage Done":             "준 피해",
+		"Healing Done":            "치유",
+		"Offensive Assists":       "공격 도움",
+		"Defensive Assists":       "방어 도움",
+		"Weapon Accuracy":         "무기 명중률",
+	},
+	"pl-pl": {
+		"Achievements":          "Osiągnięcia",
+		"Average Rank":          "Średni ranking",
+		"Career Stats":          "Statystyki kariery",
+		"Charts":                "Wykresy",
+		"Competitive":           "Rankingowa",
+		"Competitive Play":      "Gra rankingowa",
+		"Competitive Rank":      "Ranking",
+		"Eliminations per Life": "Eliminacje na życie",
+		"Endorsement Level":     "Poziom rekomendacji",
+		"Featured Stats":        "Najważniejsze statystyki",
+		"Fetch error":           "Błąd pobierania",
+		"Fetching...":           "Pobieranie...",
+		"Good Teammate":         "Dobry kolega z drużyny",
+		"Hero Pool":             "Pula bohaterów",
+		"Inactive":              "Nieaktywny",
+		"Inactive since":        "Nieaktywny od",
+		"Level":                 "Poziom",
+		"Loading...":            "Wczytywanie...",
+		"Median Rank":           "Mediana rankingu",
+		"Others":                "Inne",
+		"Private Profile":       "Profil prywatny",
+		"Profile":               "Profil",
+		"Quick Play":            "Szybka gra",
+		"Rank Spread":           "Rozrzut rankingu",
+		"Ranked Members":        "Sklasyfikowani członkowie",
+		"Session":               "Sesja",
+		"Shotcaller":            "Dowódca",
+		"Sportsmanship":         "Sportowe zachowanie",
+		"Time Played":           "Czas gry",
+		"Top Heroes":            "Najlepsi bohaterowie",
+		"Uncovered":             "Niepokryci",
+		"Updated":               "Zaktualizowano",
+		"Waiting for stats...":  "Oczekiwanie na statystyki...",
+		"Win Percentage":        "Procent zwycięstw",
+		"Stats and achievements of this player are not public.": "Statystyki i osiągnięcia tego gracza nie są publiczne.",
+
+		// labels on career pages
+		"ALL HEROES":              "WSZYSCY BOHATEROWIE",
+		"Games Won":               "Wygrane mecze",
+		"Games Lost":              "Przegrane mecze",
+		"Games Tied":              "Zremisowane mecze",
+		"Games Played":            "Rozegrane mecze",
+		"Best":                    "Najlepsze",
+		"Deaths":                  "Śmierci",
+		"Deaths - Average":        "Śmierci - Średnio",
+		"Deaths - Avg per 10 Min": "Śmierci - Śr. na 10 min",
+		"Environmental Deaths":    "Śmierci od otoczenia",
+		"Medals":                  "Medale",
+		"Hero Specific":           "Specyficzne dla bohatera",
+		"Eliminations":            "Eliminacje",
+		"Final Blows":             "Ostateczne ciosy",
+		"All Damage Done":         "Wszystkie zadane obrażenia",
+		"Damage Done":             "Zadane obrażenia",
+		"Healing Done":            "Przywrócone zdrowie",
+		"Offensive Assists":       "Asysty ofensywne",
+		"Defensive Assists":       "Asysty defensywne",
+		"Weapon Accuracy":         "Celność broni",
+	},
+	"pt-br": {
+		"Achievements":          "Conquistas",
+		"Average Rank":          "Classificação média",
+		"Career Stats":          "Estatísticas de Carreira",
+		"Charts":                "Gráficos",
+		"Competitive":           "Competitivo",
+		"Competitive Play":      "Partida Competitiva",
+		"Competitive Rank":      "Classificação Competitiva",
+		"Eliminations per Life": "Abates por Vida",
+		"Endorsement Level":     "Nível de Recomendação",
+		"Featured Stats":        "Estatísticas em Destaque",
+		"Fetch error":           "Erro ao Buscar",
+		"Fetching...":           "Buscando...",
+		"Good Teammate":         "Bom Colega de Equipe",
+		"Hero Pool":             "Repertório de heróis",
+		"Inactive":              "Inativo",
+		"Inactive since":        "Inativo desde",
+		"Level":                 "Nível",
+		"Loading...":            "Carregando...",
+		"Median Rank":           "Classificação mediana",
+		"Others":                "Outros",
+		"Private Profile":       "Perfil Privado",
+		"Profile":               "Perfil",
+		"Quick Play":            "Partida Rápida",
+		"Rank Spread":           "Diferença de classificação",
+		"Ranked Members":        "Membros classificados",
+		"Session":               "Sessão",
+		"Shotcaller":            "Líder",
+		"Sportsmanship":         "Espírito Esportivo",
+		"Time Played":           "Tempo de Jogo",
+		"Top Heroes":            "Heróis Principais",
+		"Uncovered":             "Não cobertos",
+		"Updated":               "Atualizado",
+		"Waiting for stats...":  "Aguardando estatísticas...",
+		"Win Percentage":        "Porcentagem de Vitórias",
+		"Stats and achievements of this player are not public.": "As estatísticas e conquistas deste jogador não são públicas.",
+
+		// labels on career pages
+		"ALL HEROES":              "TODOS OS HERÓIS",
+		"Games Won":               "Partidas Vencidas",
+		"Games Lost":              "Partidas Perdidas",
+		"Games Tied":              "Partidas Empatadas",
+		"Games Played":            "Partidas Jogadas",
+		"Best":                    "Melhor",
+		"Deaths":                  "Mortes",
+		"Deaths - Average":        "Mortes - Média",
+		"Deaths - Avg per 10 Min": "Mortes - Méd. por 10 min",
+		"Environmental Deaths":    "Mortes ambientais",
+		"Medals":                  "Medalhas",
+		"Hero Specific":           "Específico do Herói",
+		"Eliminations":            "Abates",
+		"Final Blows":             "Golpes Finais",
+		"All Damage Done":         "Dano Total Causado",
+		"Damage Done":             "Dano Causado",
+		"Healing Done":            "Cura Realizada",
+		"Offensive Assists":       "Assistências Ofensivas",
+		"Defensive Assists":       "Assistências Defensivas",
+		"Weapon Accuracy":         "Precisão da Arma",
+	},
+	"ru-ru": {
+		"Achievements":          "Достижения",
+		"Average Rank":          "Средний рейтинг",
+		"Career Stats":          "Статистика карьеры",
+		"Charts":                "Графики",
+		"Competitive":           "Рейтинг",
+		"Competitive Play":      "Соревновательная игра",
+		"Competitive Rank":      "Рейтинг",
+		"Eliminations per Life": "Убийств за жизнь",
+		"Endorsement Level":     "Уровень одобрения",
+		"Featured Stats":        "Основная статистика",
+		"Fetch error":           "Ошибка получения",
+		"Fetching...":           "Получение...",
+		"Good Teammate":         "Хороший союзник",
+		"Hero Pool":             "Набор героев",
+		"Inactive":              "Неактивен",
+		"Inactive since":        "Неактивен с",
+		"Level":                 "Уровень",
+		"Loading...":            "Загрузка...",
+		"Median Rank":           "Медианный рейтинг",
+		"Others":                "Другие",
+		"Private Profile":       "Закрытый профиль",
+		"Profile":               "Профиль",
+		"Quick Play":            "Быстрая игра",
+		"Rank Spread":           "Разброс рейтинга",
+		"Ranked Members":        "Участники с рейтингом",
+		"Session":               "Сессия",
+		"Shotcaller":            "Лидер",
+		"Sportsmanship":         "Спортивное поведение",
+		"Time Played":           "Время игры",
+		"Top Heroes":            "Лучшие герои",
+		"Uncovered":             "Не охвачены",
+		"Updated":               "Обновлено",
+		"Waiting for stats...":  "Ожидание статистики...",
+		"Win Percentage":        "Процент побед",
+		"Stats and achievements of this player are not public.": "Статистика и достижения этого игрока скрыты.",
+
+		// labels on career pages
+		"ALL HEROES":              "ВСЕ ГЕРОИ",
+		"Games Won":               "Матчей выиграно",
+		"Games Lost":              "Матчей проиграно",
+		"Games Tied":              "Ничьих",
+		"Games Played":            "Сыграно матчей",
+		"Best":                    "Лучшие результаты",
+		"Deaths":                  "Смерти",
+		"Deaths - Average":        "Смерти - в среднем",
+		"Deaths - Avg per 10 Min": "Смерти - в среднем за 10 мин",
+		"Environmental Deaths":    "Смерти от окружения",
+		"Medals":                  "Медали",
+		"Hero Specific":           "Особые характеристики героя",
+		"Eliminations":            "Убийства",
+		"Final Blows":             "Решающие удары",
+		"All Damage Done":         "Весь нанесенный урон",
+		"Damage Done":             "Нанесенный урон",
+		"Healing Done":            "Исцеление",
+		"Offensive Assists":       "Помощь в атаке",
+		"Defensive Assists":       "Помощь в защите",
+		"Weapon Accuracy":         "Меткость",
+	},
+	"zh-tw": {
+		"Achievements":          "成就",
+		"Average Rank":          "平均積分",
+		"Career Stats":          "生涯數據",
+		"Charts":                "圖表",
+		"Competitive":           "競技",
+		"Competitive Play":      "競技對戰",
+		"Competitive Rank":      "競技積分",
+		"Eliminations per Life": "每條命擊殺數",
+		"Endorsement Level":     "表揚等級",
+		"Featured Stats":        "精選數據",
+		"Fetch error":           "擷取錯誤",
+		"Fetching...":           "擷取中...",
+		"Good Teammate":         "好隊友",
+		"Hero Pool":             "英雄池",
+		"Inactive":              "未活動",
+		"Inactive since":        "未活動自",
+		"Level":                 "等級",
+		"Loading...":            "載入中...",
+		"Median Rank":           "積分中位數",
+		"Others":                "其他",
+		"Private Profile":       "非公開個人檔案",
+		"Profile":               "個人檔案",
+		"Quick Play":            "快速對戰",
+		"Rank Spread":           "積分差距",
+		"Ranked Members":        "有積分的成員",
+		"Session":               "本次遊戲",
+		"Shotcaller":            "指揮官",
+		"Sportsmanship":         "運動家精神",
+		"Time Played":           "遊戲時間",
+		"Top Heroes":            "最常使用英雄",
+		"Uncovered":             "未涵蓋",
+		"Updated":               "已更新",
+		"Waiting for stats...":  "等待數據中...",
+		"Win Percentage":        "勝率",
+		"Stats and achievements of this player are not public.": "此玩家的數據與成就並未公開。",
+
+		// labels on career pages
+		"ALL HEROES":              "所有英雄",
+		"Games Won":               "勝場數",
+		"Games Lost":              "敗場數",
+		"Games Tied":              "平手場數",
+		"Games Played":            "遊戲場數",
+		"Best":                    "最佳",
+		"Deaths":                  "死亡",
+		"Deaths - Average":        "死亡 - 平均",
+		"Deaths - Avg per 10 Min": "死亡 - 平均每10分鐘",
+		"Environmental Deaths":    "環境死亡",
+		"Medals":                  "獎牌",
+		"Hero Specific":           "英雄專屬",
+		"Eliminations":            "擊殺",
+		"Final Blows":             "最後一擊",
+		"All Damage Done":         "造成的總傷害",
+		"Damage Done":             "造成傷害",
+		"Healing Done":            "治療量",
+		"Offensive Assists":       "進攻助攻",
+		"Defensive Assists":       "防禦助攻",
+		"Weapon Accuracy":         "武器命中率",
+	},
+}
+
+// shared by es-es and es-mx
+var labelsSpanish = map[string]string{
+	"Achievements":          "Logros",
+	"Average Rank":          "Clasificación media",
+	"Career Stats":          "Estadísticas de carrera",
+	"Charts":                "Gráficos",
+	"Competitive":           "Competitiva",
+	"Competitive Play":      "Partida competitiva",
+	"Competitive Rank":      "Rango competitivo",
+	"Eliminations per Life": "Eliminaciones por vida",
+	"Endorsement Level":     "Nivel de reconocimiento",
+	"Featured Stats":        "Estadísticas destacadas",
+	"Fetch error":           "Error al obtener",
+	"Fetching...":           "Obteniendo...",
+	"Good Teammate":         "Buen compañero",
+	"Hero Pool":             "Repertorio de héroes",
+	"Inactive":              "Inactivo",
+	"Inactive since":        "Inactivo desde",
+	"Level":                 "Nivel",
+	"Loading...":            "Cargando...",
+	"Median Rank":           "Clasificación mediana",
+	"Others":                "Otros",
+	"Private Profile":       "Perfil privado",
+	"Profile":               "Perfil",
+	"Quick Play":            "Partida rápida",
+	"Rank Spread":           "Diferencia de clasificación",
+	"Ranked Members":        "Miembros clasificados",
+	"Session":               "Sesión",
+	"Shotcaller":            "Estratega",
+	"Sportsmanship":         "Deportividad",
+	"Time Played":           "Tiempo jugado",
+	"Top Heroes":            "Héroes destacados",
+	"Uncovered":             "Sin cubrir",
+	"Updated":               "Actualizado",
+	"Waiting for stats...":  "Esperando estadísticas...",
+	"Win Percentage":        "Porcentaje de victorias",
+	"Stats and achievements of this player are not public.": "Las estadísticas y los logros de este jugador no son públicos.",
+
+	// labels on career pages
+	"ALL HEROES":              "TODOS LOS HÉROES",
+	"Games Won":               "Partidas ganadas",
+	"Games Lost":              "Partidas perdidas",
+	"Games Tied":              "Partidas empatadas",
+	"Games Played":            "Partidas jugadas",
+	"Best":                    "Mejor",
+	"Deaths":                  "Muertes",
+	"Deaths - Average":        "Muertes - Promedio",
+	"Deaths - Avg per 10 Min": "Muertes - Prom. cada 10 min",
+	"Environmental Deaths":    "Muertes por el entorno",
+	"Medals":                  "Medallas",
+	"Hero Specific":           "Específico del héroe",
+	"Eliminations":            "Eliminaciones",
+	"Final Blows":             "Golpes de gracia",
+	"All Damage Done":         "Daño total infligido",
+	"Damage Done":             "Daño infligido",
+	"Healing Done":            "Sanación realizada",
+	"Offensive Assists":       "Asistencias ofensivas",
+	"Defensive Assists":       "Asistencias defensivas",
+	"Weapon Accuracy":         "Precisión del arma",
+}
+
+// localize given (english) label to given language
+//
+// (when there is no translation, the label itself will be returned)
+func Localize(language, label string) string {
+	if labels, exists := labelCatalog[strings.ToLower(language)]; exists {
+		if translated, exists := labels[label]; exists {
+			return translated
+		}
+	}
+	return label
+}
+
+// (english) labels on career pages, which can be looked up in any supported language with CanonicalLabel
+var pageLabels = []string{
+	"ALL HEROES",
+	"All Damage Done",
+	"Best",
+	"Damage Done",
+	"Deaths",
+	"Deaths - Average",
+	"Deaths - Avg per 10 Min",
+	"Defensive Assists",
+	"Eliminations",
+	"Eliminations per Life",
+	"Environmental Deaths",
+	"Final Blows",
+	"Games Lost",
+	"Games Played",
+	"Games Tied",
+	"Games Won",
+	"Healing Done",
+	"Hero Specific",
+	"Medals",
+	"Offensive Assists",
+	"Time Played",
+	"Weapon Accuracy",
+	"Win Percentage",
+}
+
+// label of career stats of all heroes
+const LabelAllHeroes = "ALL HEROES"
+
+// english label of given label on a career page, in any supported language (case-insensitive)
+//
+// (eg. "Spielzeit" => "Time Played")
+func CanonicalLabel(label string) (string, bool) {
+	english, exists := canonicalLabels[strings.ToLower(label)]
+	return english, exists
+}
+
+// english labels on career pages, keyed by lowercased labels of all supported languages
+var canonicalLabels = func() map[string]string {
+	labels := map[string]string{}
+	for _, english := range pageLabels {
+		for _, label := range labelsOf(english) {
+			labels[strings.ToLower(label)] = english
+		}
+	}
+	return labels
+}()
+
+// check if given label on a career page is given english label, in any supported language
+func IsLabel(label, english string) bool {
+	canonical, exists := CanonicalLabel(label)
+	return exists && canonical == english
+}
+
+// check if given hero name on a career page is the one of all heroes
+func IsAllHeroes(heroName string) bool {
+	return IsLabel(heroName, LabelAllHeroes)
+}
+
+// given english label, and its translations in all supported languages
+func labelsOf(english string) []string {
+	labels := []string{english}
+	for _, language := range SupportedLanguages {
+		if translated := Localize(language, english); translated != english {
+			labels = append(labels, translated)
+		}
+	}
+	return labels
+}
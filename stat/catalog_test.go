@@ -0,0 +1,148 @@
+package stat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// labels which should be translated in all non-english languages
+var catalogLabels = []string{
+	"Achievements",
+	"Average Rank",
+	"Career Stats",
+	"Charts",
+	"Competitive",
+	"Competitive Play",
+	"Competitive Rank",
+	"Eliminations per Life",
+	"Endorsement Level",
+	"Featured Stats",
+	"Fetch error",
+	"Fetching...",
+	"Good Teammate",
+	"Hero Pool",
+	"Inactive",
+	"Inactive since",
+	"Level",
+	"Loading...",
+	"Median Rank",
+	"Others",
+	"Private Profile",
+	"Profile",
+	"Quick Play",
+	"Rank Spread",
+	"Ranked Members",
+	"Session",
+	"Sportsmanship",
+	"Stats and achievements of this player are not public.",
+	"Time Played",
+	"Top Heroes",
+	"Uncovered",
+	"Updated",
+	"Waiting for stats...",
+	"Win Percentage",
+}
+
+func TestCatalog(t *testing.T) {
+	for _, language := range stat.SupportedLanguages {
+		if strings.HasPrefix(language, "en-") {
+			continue
+		}
+
+		for _, label := range catalogLabels {
+			if stat.Localize(language, label) == label {
+				t.Errorf("no translation of '%s' in %s", label, language)
+			}
+		}
+	}
+
+	for _, label := range catalogLabels {
+		if localized := stat.Localize("en-gb", label); localized != label {
+			t.Errorf("expected '%s' in english, got '%s'", label, localized)
+		}
+	}
+}
+
+// labels on career pages which should be recognized in all languages
+var catalogPageLabels = []string{
+	"ALL HEROES",
+	"All Damage Done",
+	"Best",
+	"Damage Done",
+	"Deaths",
+	"Deaths - Average",
+	"Deaths - Avg per 10 Min",
+	"Defensive Assists",
+	"Eliminations",
+	"Eliminations per Life",
+	"Environmental Deaths",
+	"Final Blows",
+	"Games Lost",
+	"Games Played",
+	"Games Tied",
+	"Games Won",
+	"Healing Done",
+	"Hero Specific",
+	"Medals",
+	"Offensive Assists",
+	"Time Played",
+	"Weapon Accuracy",
+	"Win Percentage",
+}
+
+func TestCanonicalLabel(t *testing.T) {
+	for _, language := range stat.SupportedLanguages {
+		for _, label := range catalogPageLabels {
+			localized := stat.Localize(language, label)
+			if localized == label && !strings.HasPrefix(language, "en-") {
+				t.Errorf("no translation of '%s' in %s", label, language)
+			}
+
+			// translations should not be shared with other labels, in any language
+			if canonical, exists := stat.CanonicalLabel(localized); !exists || canonical != label {
+				t.Errorf("expected '%s' for '%s' in %s, got '%s'", label, localized, language, canonical)
+			}
+			if !stat.IsLabel(strings.ToUpper(localized), label) {
+				t.Errorf("'%s' should be recognized case-insensitively in %s", localized, language)
+			}
+		}
+	}
+
+	if !stat.IsAllHeroes("TOUS LES HÉROS") || stat.IsAllHeroes("Ana") {
+		t.Errorf("unexpected results of all heroes' labels")
+	}
+	if _, exists := stat.CanonicalLabel("Objective Kills"); exists {
+		t.Errorf("labels which are not in the catalog should not be recognized")
+	}
+}
+
+func TestLocalizedHtml(t *testing.T) {
+	for _, test := range []struct {
+		fixture  stattest.Fixture
+		language string
+		expected []string
+	}{
+		{stattest.FixturePcCompetitive, "en-us", []string{`<html lang="en-us">`, "<h1>Quick Play</h1>", "<h2>Top Heroes</h2>"}},
+		{stattest.FixturePcCompetitiveKorean, "ko-kr", []string{`<html lang="ko-kr">`, "<h1>빠른 대전</h1>", "<h2>영웅 순위</h2>", "<h1>경쟁전</h1>"}},
+		{stattest.FixturePsnConsoleGerman, "de-de", []string{`<html lang="de-de">`, "<h1>Schnelles Spiel</h1>", "<h2>Karrierestatistiken</h2>"}},
+		{stattest.FixturePcPrivate, "en-us", []string{"<h1>Private Profile</h1>"}},
+	} {
+		s := test.fixture.Stat(t)
+		if s.Language != test.language {
+			t.Errorf("expected language %s, got '%s'", test.language, s.Language)
+		}
+
+		html, err := stat.RenderStatToHtml(s, stat.SampleHtmlTemplate)
+		if err != nil {
+			t.Fatalf("failed to render html: %s", err)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(html, expected) {
+				t.Errorf("expected '%s' in html of %s", expected, test.language)
+			}
+		}
+	}
+}
@@ -0,0 +1,503 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+// types of charts
+const (
+	ChartTypeLine  = "line"
+	ChartTypeBar   = "bar"
+	ChartTypePie   = "pie"
+	ChartTypeDonut = "donut"
+)
+
+// sizes of charts
+const (
+	ChartWidth  = 480
+	ChartHeight = 240
+
+	FontSizeChartTitle float64 = 16.0
+	FontSizeChartLabel float64 = 10.0
+
+	maxChartXLabels     = 6
+	maxChartPieSegments = 6 // (rest of them will be merged into one)
+)
+
+// labels of charts
+const (
+	ChartTitleCompetitiveRank = "Competitive Rank"
+	ChartTitleLevel           = "Level"
+	ChartTitleTimePlayed      = "Time Played"
+	ChartLabelOthers          = "Others"
+	ChartTimeFormat           = "01-02"
+)
+
+// colors of charts' lines, bars, and segments
+var ChartColors = []color.RGBA{
+	colorShotcaller,
+	colorTeammate,
+	colorSportsmanship,
+	{67, 160, 236, 255},
+	{232, 75, 60, 255},
+	{41, 196, 190, 255},
+	{250, 220, 60, 255},
+	{160, 160, 160, 255},
+}
+
+// a stat fetched at a time
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	Stat Stat      `json:"stat"`
+}
+
+// a chart of labeled values
+type Chart struct {
+	Type   string       `json:"type"` // ChartTypeLine, ChartTypeBar, ChartTypePie, or ChartTypeDonut
+	Title  string       `json:"title"`
+	Width  int          `json:"width"`  // when 0, ChartWidth will be used
+	Height int          `json:"height"` // when 0, ChartHeight will be used
+	Points []ChartPoint `json:"points"`
+}
+
+type ChartPoint struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+// a text on charts
+type chartText struct {
+	At    image.Point // baseline
+	Text  string
+	Color color.RGBA
+}
+
+// positions and sizes of a chart's elements, shared by .png and .svg renderers
+type chartLayout struct {
+	Width, Height int
+	Title         chartText
+
+	// line and bar charts
+	Plot    image.Rectangle
+	GridYs  []int
+	Labels  []chartText // values on the y axis, and labels on the x axis
+	Points  []image.Point
+	Bars    []image.Rectangle
+	HasAxes bool
+
+	// pie and donut charts
+	Center      image.Point
+	Radius      int
+	InnerRadius int
+	Ratios      []float64
+	Colors      []color.RGBA      // colors of the segments
+	Legends     []image.Rectangle // color boxes of the legends (texts are in Labels)
+}
+
+// chart of competitive rank over time (line chart)
+//
+// (snapshots without competitive rank are skipped)
+func CompetitiveRankChart(snapshots []Snapshot) Chart {
+	chart := Chart{Type: ChartTypeLine, Title: ChartTitleCompetitiveRank, Points: []ChartPoint{}}
+	for _, snapshot := range sortedSnapshots(snapshots) {
+		if snapshot.Stat.CompetitiveRank == NoCompetitiveRank {
+			continue
+		}
+		chart.Points = append(chart.Points, ChartPoint{
+			Label: snapshot.Time.Format(ChartTimeFormat),
+			Value: float64(snapshot.Stat.CompetitiveRank),
+		})
+	}
+	return chart
+}
+
+// chart of level progression over time (bar chart)
+func LevelChart(snapshots []Snapshot) Chart {
+	chart := Chart{Type: ChartTypeBar, Title: ChartTitleLevel, Points: []ChartPoint{}}
+	for _, snapshot := range sortedSnapshots(snapshots) {
+		chart.Points = append(chart.Points, ChartPoint{
+			Label: snapshot.Time.Format(ChartTimeFormat),
+			Value: float64(snapshot.Stat.Level),
+		})
+	}
+	return chart
+}
+
+// chart of heroes' shares of time played in quick play, from the latest snapshot (donut chart)
+func TimePlayedChart(snapshots []Snapshot) Chart {
+	chart := Chart{Type: ChartTypeDonut, Title: ChartTitleTimePlayed, Points: []ChartPoint{}}
+
+	sorted := sortedSnapshots(snapshots)
+	if len(sorted) <= 0 {
+		return chart
+	}
+
+	for i, hero := range timePlayedHeroes(sorted[len(sorted)-1].Stat) {
+		duration, _ := ParseDuration(hero.Value)
+		if i < maxChartPieSegments-1 {
+			chart.Points = append(chart.Points, ChartPoint{Label: hero.Name, Value: duration.Hours()})
+		} else if i == maxChartPieSegments-1 {
+			chart.Points = append(chart.Points, ChartPoint{Label: ChartLabelOthers, Value: duration.Hours()})
+		} else {
+			chart.Points[len(chart.Points)-1].Value += duration.Hours()
+		}
+	}
+	return chart
+}
+
+// return bytes of given chart rendered in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderChartToPngBytes(chart Chart, font *truetype.Font) ([]byte, error) {
+	if image, err := genChart(chart, font); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := png.Encode(imgBytes, image); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render given chart to a file in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderChartToPngFile(chart Chart, font *truetype.Font, outFilepath string) error {
+	if bytes, err := RenderChartToPngBytes(chart, font); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// render given chart in .svg format
+func RenderChartToSvg(chart Chart) (result string, err error) {
+	layout := newChartLayout(chart)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="chart chart-%s" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", chart.Type, layout.Width, layout.Height, layout.Width, layout.Height)
+	fmt.Fprintf(&b, "\t<style>@font-face { font-family: Koverwatch; src: url(%s); } text { font-family: Koverwatch, sans-serif; }</style>\n", KoverwatchFontUrl)
+	fmt.Fprintf(&b, "\t<rect x=\"0\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", layout.Width, layout.Height, cssColor(DefaultBannerSpec.BackgroundColor))
+	writeSvgChartText(&b, layout.Title, FontSizeChartTitle)
+
+	// grid and axes
+	for _, y := range layout.GridYs {
+		fmt.Fprintf(&b, "\t<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\"/>\n", layout.Plot.Min.X, y, layout.Plot.Max.X, y, cssColor(colorChartGrid))
+	}
+	if layout.HasAxes {
+		fmt.Fprintf(&b, "\t<polyline points=\"%d,%d %d,%d %d,%d\" fill=\"none\" stroke=\"%s\"/>\n",
+			layout.Plot.Min.X, layout.Plot.Min.Y,
+			layout.Plot.Min.X, layout.Plot.Max.Y,
+			layout.Plot.Max.X, layout.Plot.Max.Y,
+			cssColor(DefaultBannerSpec.TextColor),
+		)
+	}
+
+	// bars
+	for _, bar := range layout.Bars {
+		fmt.Fprintf(&b, "\t<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", bar.Min.X, bar.Min.Y, bar.Dx(), bar.Dy(), cssColor(ChartColors[0]))
+	}
+
+	// line and its points
+	if len(layout.Points) > 0 {
+		points := []string{}
+		for _, p := range layout.Points {
+			points = append(points, fmt.Sprintf("%d,%d", p.X, p.Y))
+		}
+		fmt.Fprintf(&b, "\t<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"2\"/>\n", strings.Join(points, " "), cssColor(ChartColors[0]))
+		for _, p := range layout.Points {
+			fmt.Fprintf(&b, "\t<circle cx=\"%d\" cy=\"%d\" r=\"3\" fill=\"%s\"/>\n", p.X, p.Y, cssColor(ChartColors[0]))
+		}
+	}
+
+	// pie or donut (with dashed circles, clockwise from the top)
+	if layout.Radius > 0 {
+		r := float64(layout.Radius+layout.InnerRadius) / 2
+		width := float64(layout.Radius - layout.InnerRadius)
+		circumference := 2 * math.Pi * r
+
+		fmt.Fprintf(&b, "\t<g transform=\"rotate(-90 %d %d)\">\n", layout.Center.X, layout.Center.Y)
+		offset := 0.0
+		for i, ratio := range layout.Ratios {
+			length := circumference * ratio
+			fmt.Fprintf(&b, "\t\t<circle cx=\"%d\" cy=\"%d\" r=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\" stroke-dasharray=\"%.3f %.3f\" stroke-dashoffset=\"%.3f\"/>\n",
+				layout.Center.X, layout.Center.Y, r,
+				cssColor(layout.Colors[i]),
+				width,
+				length, circumference-length,
+				-offset,
+			)
+			offset += length
+		}
+		b.WriteString("\t</g>\n")
+	}
+	for i, legend := range layout.Legends {
+		fmt.Fprintf(&b, "\t<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", legend.Min.X, legend.Min.Y, legend.Dx(), legend.Dy(), cssColor(layout.Colors[i]))
+	}
+
+	// labels
+	for _, label := range layout.Labels {
+		writeSvgChartText(&b, label, FontSizeChartLabel)
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.String(), nil
+}
+
+// render given chart to a file in .svg format
+func RenderChartToSvgFile(chart Chart, outFilepath string) error {
+	if svg, err := RenderChartToSvg(chart); err == nil {
+		return ioutil.WriteFile(outFilepath, []byte(svg), 0640)
+	} else {
+		return err
+	}
+}
+
+// colors of grid lines, and pies without any value
+var (
+	colorChartGrid = color.RGBA{255, 255, 255, 48}
+	colorChartNone = color.RGBA{128, 128, 128, 255}
+)
+
+// generate a chart image
+func genChart(chart Chart, font *truetype.Font) (result *image.RGBA, err error) {
+	layout := newChartLayout(chart)
+
+	// load .ttf font
+	if font == nil {
+		if font, err = getFont(KoverwatchFontUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, layout.Width, layout.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{DefaultBannerSpec.BackgroundColor}, image.ZP, draw.Src)
+
+	// grid and axes
+	for _, y := range layout.GridYs {
+		draw.Draw(img, image.Rect(layout.Plot.Min.X, y, layout.Plot.Max.X, y+1), &image.Uniform{colorChartGrid}, image.ZP, draw.Over)
+	}
+	if layout.HasAxes {
+		drawLine(img, image.Pt(layout.Plot.Min.X, layout.Plot.Min.Y), image.Pt(layout.Plot.Min.X, layout.Plot.Max.Y), 1, DefaultBannerSpec.TextColor)
+		drawLine(img, image.Pt(layout.Plot.Min.X, layout.Plot.Max.Y), image.Pt(layout.Plot.Max.X, layout.Plot.Max.Y), 1, DefaultBannerSpec.TextColor)
+	}
+
+	// bars
+	for _, bar := range layout.Bars {
+		draw.Draw(img, bar, &image.Uniform{ChartColors[0]}, image.ZP, draw.Src)
+	}
+
+	// line and its points
+	for i, p := range layout.Points {
+		if i > 0 {
+			drawLine(img, layout.Points[i-1], p, 2, ChartColors[0])
+		}
+	}
+	for _, p := range layout.Points {
+		drawPie(img, p, 3, 0, []float64{1}, []color.Color{ChartColors[0]})
+	}
+
+	// pie or donut
+	if layout.Radius > 0 {
+		colors := []color.Color{}
+		for _, c := range layout.Colors {
+			colors = append(colors, c)
+		}
+		drawPie(img, layout.Center, layout.Radius, layout.InnerRadius, layout.Ratios, colors)
+	}
+	for i, legend := range layout.Legends {
+		draw.Draw(img, legend, &image.Uniform{layout.Colors[i]}, image.ZP, draw.Src)
+	}
+
+	// texts
+	context := freetype.NewContext()
+	context.SetFont(font)
+	context.SetDPI(72)
+	context.SetClip(img.Bounds())
+	context.SetDst(img)
+
+	context.SetSrc(&image.Uniform{layout.Title.Color})
+	if err = drawText(context, BannerElement{TextAt: layout.Title.At, FontSize: FontSizeChartTitle}, layout.Title.Text, 0); err != nil {
+		return nil, err
+	}
+	for _, label := range layout.Labels {
+		context.SetSrc(&image.Uniform{label.Color})
+		if err = drawText(context, BannerElement{TextAt: label.At, FontSize: FontSizeChartLabel}, label.Text, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return img, nil
+}
+
+// calculate positions and sizes of given chart's elements
+func newChartLayout(chart Chart) chartLayout {
+	layout := chartLayout{
+		Width:  chart.Width,
+		Height: chart.Height,
+	}
+	if layout.Width <= 0 {
+		layout.Width = ChartWidth
+	}
+	if layout.Height <= 0 {
+		layout.Height = ChartHeight
+	}
+	textColor := DefaultBannerSpec.TextColor
+	layout.Title = chartText{At: image.Pt(Margin*2, Margin+int(FontSizeChartTitle)), Text: chart.Title, Color: textColor}
+
+	top := Margin*3 + int(FontSizeChartTitle)
+
+	switch chart.Type {
+	case ChartTypePie, ChartTypeDonut:
+		total := 0.0
+		for _, p := range chart.Points {
+			total += math.Max(p.Value, 0)
+		}
+
+		layout.Radius = (layout.Height - top - Margin*2) / 2
+		layout.Center = image.Pt(Margin*4+layout.Radius, top+layout.Radius)
+		if chart.Type == ChartTypeDonut {
+			layout.InnerRadius = layout.Radius / 2
+		}
+
+		// segments and their legends
+		x := layout.Center.X + layout.Radius + Margin*6
+		size := int(FontSizeChartLabel)
+		for i, p := range chart.Points {
+			ratio := 0.0
+			if total > 0 {
+				ratio = math.Max(p.Value, 0) / total
+			}
+			layout.Ratios = append(layout.Ratios, ratio)
+			layout.Colors = append(layout.Colors, ChartColors[i%len(ChartColors)])
+
+			y := top + i*(size+Margin*2)
+			layout.Legends = append(layout.Legends, image.Rect(x, y, x+size, y+size))
+			layout.Labels = append(layout.Labels, chartText{
+				At:    image.Pt(x+size+Margin*2, y+size-1),
+				Text:  fmt.Sprintf("%s  %s (%.1f%%)", p.Label, formatChartValue(p.Value), ratio*100),
+				Color: textColor,
+			})
+		}
+		if total <= 0 { // nothing to show
+			layout.Ratios = []float64{1}
+			layout.Colors = []color.RGBA{colorChartNone}
+			layout.Legends = nil
+		}
+	default: // line or bar
+		layout.HasAxes = true
+		layout.Plot = image.Rect(Margin*14, top+Margin, layout.Width-Margin*4, layout.Height-Margin*2-int(FontSizeChartLabel))
+
+		// range of values
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, p := range chart.Points {
+			min, max = math.Min(min, p.Value), math.Max(max, p.Value)
+		}
+		if len(chart.Points) <= 0 {
+			min, max = 0, 1
+		}
+		if chart.Type == ChartTypeBar {
+			min, max = math.Min(min, 0), math.Max(max, 0)
+		}
+		if min == max {
+			min, max = min-1, max+1
+		}
+		y := func(value float64) int {
+			return layout.Plot.Max.Y - int(math.Round((value-min)/(max-min)*float64(layout.Plot.Dy())))
+		}
+
+		// values on the y axis
+		for _, value := range []float64{min, (min + max) / 2, max} {
+			layout.GridYs = append(layout.GridYs, y(value))
+			layout.Labels = append(layout.Labels, chartText{
+				At:    image.Pt(Margin*2, y(value)+int(FontSizeChartLabel)/3),
+				Text:  formatChartValue(value),
+				Color: textColor,
+			})
+		}
+
+		// points or bars, and labels on the x axis
+		slot := 0
+		if len(chart.Points) > 0 {
+			slot = layout.Plot.Dx() / len(chart.Points)
+		}
+		step := int(math.Ceil(float64(len(chart.Points)) / maxChartXLabels))
+		for i, p := range chart.Points {
+			x := layout.Plot.Min.X + slot*i + slot/2
+
+			if chart.Type == ChartTypeBar {
+				width := slot * 3 / 5
+				layout.Bars = append(layout.Bars, image.Rect(x-width/2, y(p.Value), x-width/2+width, y(math.Max(min, 0))).Canon())
+			} else {
+				layout.Points = append(layout.Points, image.Pt(x, y(p.Value)))
+			}
+
+			if i%step == 0 || i == len(chart.Points)-1 {
+				layout.Labels = append(layout.Labels, chartText{
+					At:    image.Pt(x-len(p.Label)*int(FontSizeChartLabel)/4, layout.Plot.Max.Y+Margin+int(FontSizeChartLabel)),
+					Text:  p.Label,
+					Color: textColor,
+				})
+			}
+		}
+	}
+
+	return layout
+}
+
+// draw a line with given width
+func drawLine(dst draw.Image, from, to image.Point, width int, c color.Color) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	steps := int(math.Max(math.Max(math.Abs(float64(dx)), math.Abs(float64(dy))), 1))
+
+	for i := 0; i <= steps; i++ {
+		x := from.X + dx*i/steps - width/2
+		y := from.Y + dy*i/steps - width/2
+		draw.Draw(dst, image.Rect(x, y, x+width, y+width), &image.Uniform{c}, image.ZP, draw.Src)
+	}
+}
+
+func writeSvgChartText(b *strings.Builder, text chartText, fontSize float64) {
+	fmt.Fprintf(b, "\t<text x=\"%d\" y=\"%d\" font-size=\"%g\" fill=\"%s\" xml:space=\"preserve\">%s</text>\n",
+		text.At.X, text.At.Y,
+		fontSize,
+		cssColor(text.Color),
+		html.EscapeString(text.Text),
+	)
+}
+
+// format given value for charts (integers without decimals)
+func formatChartValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
+
+// snapshots sorted by their times
+func sortedSnapshots(snapshots []Snapshot) []Snapshot {
+	sorted := append([]Snapshot{}, snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	return sorted
+}
@@ -0,0 +1,101 @@
+package stat_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// snapshots of a player, with increasing competitive rank and level
+func testSnapshots(t *testing.T) []stat.Snapshot {
+	s := stattest.FixturePcCompetitive.Stat(t)
+
+	snapshots := []stat.Snapshot{}
+	start := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 4; i >= 0; i-- { // (in reverse order, to be sorted)
+		snapshot := stat.Snapshot{Time: start.AddDate(0, 0, i*7), Stat: s}
+		snapshot.Stat.CompetitiveRank = s.CompetitiveRank - int32(100*(4-i))
+		snapshot.Stat.Level = s.Level - int32(4-i)
+		if i == 2 {
+			snapshot.Stat.CompetitiveRank = stat.NoCompetitiveRank
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+func TestCharts(t *testing.T) {
+	snapshots := testSnapshots(t)
+
+	rank := stat.CompetitiveRankChart(snapshots)
+	if len(rank.Points) != 4 || rank.Points[0].Label != "06-01" || rank.Points[3].Value != 3537 {
+		t.Errorf("unexpected competitive rank chart: %+v", rank)
+	}
+	level := stat.LevelChart(snapshots)
+	if len(level.Points) != 5 || level.Points[0].Value != 85 || level.Points[4].Value != 89 {
+		t.Errorf("unexpected level chart: %+v", level)
+	}
+	timePlayed := stat.TimePlayedChart(snapshots)
+	if len(timePlayed.Points) <= 0 || timePlayed.Points[0].Label != "Ana" || timePlayed.Points[0].Value != 40 {
+		t.Errorf("unexpected time played chart: %+v", timePlayed)
+	}
+
+	font := testFont(t)
+	empty := stat.Chart{Title: "Empty"}
+	for _, chartType := range []string{stat.ChartTypeLine, stat.ChartTypeBar, stat.ChartTypePie, stat.ChartTypeDonut} {
+		for _, chart := range []stat.Chart{rank, level, timePlayed, empty} {
+			chart.Type = chartType
+
+			pngBytes, err := stat.RenderChartToPngBytes(chart, font)
+			if err != nil {
+				t.Fatalf("failed to render %s chart '%s' to png: %s", chartType, chart.Title, err)
+			}
+			if img, err := png.Decode(bytes.NewReader(pngBytes)); err != nil {
+				t.Errorf("failed to decode %s chart '%s': %s", chartType, chart.Title, err)
+			} else if img.Bounds().Dx() != stat.ChartWidth || img.Bounds().Dy() != stat.ChartHeight {
+				t.Errorf("expected %dx%d chart, got %v", stat.ChartWidth, stat.ChartHeight, img.Bounds())
+			}
+
+			svg, err := stat.RenderChartToSvg(chart)
+			if err != nil {
+				t.Fatalf("failed to render %s chart '%s' to svg: %s", chartType, chart.Title, err)
+			}
+			decoder := xml.NewDecoder(strings.NewReader(svg))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("malformed svg of %s chart '%s': %s\n%s", chartType, chart.Title, err, svg)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderStatToHtmlWithCharts(t *testing.T) {
+	snapshots := testSnapshots(t)
+	latest := snapshots[0].Stat
+
+	html, err := stat.RenderStatToHtmlWithCharts(latest, snapshots, stat.SampleHtmlTemplate)
+	if err != nil {
+		t.Fatalf("failed to render html with charts: %s", err)
+	}
+	if count := strings.Count(html, "<svg "); count != 3 {
+		t.Errorf("expected 3 charts in html, got %d", count)
+	}
+
+	// without snapshots
+	if html, err = stat.RenderStatToHtml(latest, stat.SampleHtmlTemplate); err != nil {
+		t.Fatalf("failed to render html: %s", err)
+	}
+	if strings.Contains(html, `id="charts"`) {
+		t.Errorf("html without snapshots should not have charts")
+	}
+}
@@ -0,0 +1,469 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+const (
+	SampleComparisonHtmlTemplate = `<html>
+	<head>
+		<title>Overwatch: {{range $i, $p := .Players}}{{if $i}} vs {{end}}{{$p.BattleTag}}{{end}}</title>
+		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+		<meta name="viewport" content="user-scalable=yes, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0, width=device-width">
+		<style>
+			body {
+				display: block;
+				padding: 3px;
+				margin: 3px;
+				background-color: #405275;
+				color: #f0edf2;
+				font-family: Futura,century gothic,arial,sans-serif;
+			}
+			table {
+				border-collapse: collapse;
+			}
+			th, td {
+				padding: 4px 10px;
+				text-align: right;
+			}
+			th.player {
+				font-family: Koverwatch, sans-serif;
+				text-align: center;
+			}
+			th.section {
+				font-family: Koverwatch, sans-serif;
+				font-size: 1.2rem;
+				text-align: left;
+				padding-top: 16px;
+			}
+			td.label {
+				text-align: left;
+				color: #c0c8d8;
+			}
+			td.leader {
+				color: #405275;
+				background-color: #f99e1a;
+				font-weight: bold;
+			}
+			img.profile {
+				width: 60px;
+				display: block;
+				margin: 0 auto 4px auto;
+			}
+		</style>
+	</head>
+	<body>
+		<table>
+			<tr>
+				<th></th>
+				{{range .Players}}
+					<th class="player"><img src="{{.ProfileImageUrl}}" class="profile">{{.BattleTag}}</th>
+				{{end}}
+			</tr>
+			{{range .Sections}}
+				<tr>
+					<th class="section" colspan="{{len $.Players | inc}}">{{.Name}}</th>
+				</tr>
+				{{range .Rows}}
+					<tr>
+						<td class="label">{{.Label}}</td>
+						{{range .Cells}}
+							<td{{if .Leader}} class="leader"{{end}}>{{.Value}}</td>
+						{{end}}
+					</tr>
+				{{end}}
+			{{end}}
+		</table>
+	</body>
+</html>`
+)
+
+// names of comparison sections (besides shared heroes), localized in the language of the first stat
+const (
+	ComparisonSectionProfile  = "Profile"
+	ComparisonSectionFeatured = "Featured Stats"
+)
+
+// sizes of comparison images
+const (
+	ComparisonLabelWidth   = 260
+	ComparisonColumnWidth  = 160
+	ComparisonRowHeight    = 22
+	ComparisonHeaderHeight = 80
+
+	FontSizeComparison float64 = 13.0
+)
+
+// color of leaders' cells on comparison images
+var ColorLeader = color.RGBA{249, 158, 26, 255}
+
+// english labels of stats which are better when lower (labels in other languages are matched with CanonicalLabel)
+var labelsLowerIsBetter = []string{"Deaths", "Deaths - Average", "Deaths - Avg per 10 Min", "Environmental Deaths", "Games Lost"}
+
+// comparison of several players' stats, in aligned columns
+type Comparison struct {
+	Players  []ComparisonPlayer  `json:"players"`
+	Sections []ComparisonSection `json:"sections"`
+}
+
+type ComparisonPlayer struct {
+	BattleTag       string `json:"battletag"`
+	Platform        string `json:"platform"`
+	Region          string `json:"region"`
+	ProfileImageUrl string `json:"profile_image_url"`
+}
+
+type ComparisonSection struct {
+	Name string          `json:"name"` // (localized) ComparisonSectionProfile, ComparisonSectionFeatured, or name of a shared hero
+	Rows []ComparisonRow `json:"rows"`
+}
+
+type ComparisonRow struct {
+	Label string           `json:"label"`
+	Cells []ComparisonCell `json:"cells"` // in the order of players
+}
+
+type ComparisonCell struct {
+	Value  string `json:"value"`  // NoValue when the player doesn't have it
+	Leader bool   `json:"leader"` // true when it is the best value of the row (ties are all leaders)
+}
+
+// compare given stats: level, competitive rank, featured stats, and career stats of heroes shared by all players
+//
+// - when competitive is true: stats of competitive play will be compared, instead of quick play
+// - leaders of each row are the highest values (or the lowest ones for deaths and lost games), among the values which exist
+// - names of sections and rows of the profile are in the language of the first stat
+func CompareStats(stats []Stat, competitive bool) Comparison {
+	comparison := Comparison{
+		Players:  []ComparisonPlayer{},
+		Sections: []ComparisonSection{},
+	}
+
+	playStats := []PlayStat{}
+	for _, stat := range stats {
+		comparison.Players = append(comparison.Players, ComparisonPlayer{
+			BattleTag:       stat.BattleTag,
+			Platform:        stat.Platform,
+			Region:          stat.Region,
+			ProfileImageUrl: stat.ProfileImageUrl,
+		})
+
+		if competitive {
+			playStats = append(playStats, stat.CompetitivePlay)
+		} else {
+			playStats = append(playStats, stat.QuickPlay)
+		}
+	}
+
+	language := ""
+	if len(stats) > 0 {
+		language = stats[0].Language
+	}
+
+	// profile
+	profile := ComparisonSection{Name: Localize(language, ComparisonSectionProfile)}
+	levels, ranks, endorsements := []string{}, []string{}, []string{}
+	for _, stat := range stats {
+		levels = append(levels, fmt.Sprintf("%d", stat.Level))
+		if stat.CompetitiveRank != NoCompetitiveRank {
+			ranks = append(ranks, fmt.Sprintf("%d", stat.CompetitiveRank))
+		} else {
+			ranks = append(ranks, NoValue)
+		}
+		if stat.EndorsementLevel != NoEndorsementLevel {
+			endorsements = append(endorsements, fmt.Sprintf("%d", stat.EndorsementLevel))
+		} else {
+			endorsements = append(endorsements, NoValue)
+		}
+	}
+	profile.Rows = append(profile.Rows,
+		newComparisonRow(Localize(language, "Level"), levels),
+		newComparisonRow(Localize(language, "Competitive Rank"), ranks),
+		newComparisonRow(Localize(language, "Endorsement Level"), endorsements),
+	)
+	comparison.Sections = append(comparison.Sections, profile)
+
+	// featured stats
+	featuredValues := []KeyValues{}
+	for _, playStat := range playStats {
+		featuredValues = append(featuredValues, playStat.FeaturedStats)
+	}
+	if rows := comparisonRows(orderedLabels(featuredValues), featuredValues); len(rows) > 0 {
+		comparison.Sections = append(comparison.Sections, ComparisonSection{
+			Name: Localize(language, ComparisonSectionFeatured),
+			Rows: rows,
+		})
+	}
+
+	// career stats of shared heroes (in the order of the first player's)
+	if len(playStats) > 0 {
+		for _, careerStat := range playStats[0].CareerStats {
+			shared := true
+			heroValues := []KeyValues{}
+			for _, playStat := range playStats {
+				if values, exists := careerStatValues(playStat, careerStat.HeroName); exists {
+					heroValues = append(heroValues, values)
+				} else {
+					shared = false
+					break
+				}
+			}
+			if !shared {
+				continue
+			}
+
+			// labels in the order of the first player's, then labels which only others have
+			comparison.Sections = append(comparison.Sections, ComparisonSection{
+				Name: careerStat.HeroName,
+				Rows: comparisonRows(orderedLabels(heroValues), heroValues),
+			})
+		}
+	}
+
+	return comparison
+}
+
+// render given comparison to .html format, using template
+func RenderComparisonToHtml(comparison Comparison, templateStr string) (result string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = template.New("comparison").Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(templateStr); err == nil {
+		var buffer bytes.Buffer
+		if err = tmpl.Execute(&buffer, comparison); err == nil {
+			return buffer.String(), nil
+		}
+	}
+	return "", err
+}
+
+// return bytes of given comparison rendered in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderComparisonToPngBytes(comparison Comparison, font *truetype.Font) ([]byte, error) {
+	if image, err := genComparisonImage(comparison, font); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := png.Encode(imgBytes, image); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render given comparison to a file in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderComparisonToPngFile(comparison Comparison, font *truetype.Font, outFilepath string) error {
+	if bytes, err := RenderComparisonToPngBytes(comparison, font); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// generate an image of given comparison, with colors of DefaultBannerSpec
+func genComparisonImage(comparison Comparison, font *truetype.Font) (result *image.RGBA, err error) {
+	// load .ttf font
+	if font == nil {
+		if font, err = getFont(KoverwatchFontUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := 0
+	for _, section := range comparison.Sections {
+		rows += 1 + len(section.Rows)
+	}
+	width := ComparisonLabelWidth + ComparisonColumnWidth*len(comparison.Players) + Margin
+	height := ComparisonHeaderHeight + ComparisonRowHeight*rows + Margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{DefaultBannerSpec.BackgroundColor}, image.ZP, draw.Src)
+
+	context := freetype.NewContext()
+	context.SetFont(font)
+	context.SetDPI(72)
+	context.SetDst(img)
+
+	textColor := &image.Uniform{DefaultBannerSpec.TextColor}
+	leaderTextColor := &image.Uniform{DefaultBannerSpec.BackgroundColor}
+
+	// players: portraits and battletags
+	for i, player := range comparison.Players {
+		column := image.Rect(
+			ComparisonLabelWidth+ComparisonColumnWidth*i, Margin,
+			ComparisonLabelWidth+ComparisonColumnWidth*(i+1), ComparisonHeaderHeight-Margin,
+		)
+		size := column.Dy() - ComparisonRowHeight
+		x := column.Min.X + (column.Dx()-size)/2
+
+		var profile image.Image
+		if profile, err = getImage(player.ProfileImageUrl); err == nil {
+			drawResized(img, image.Rect(x, column.Min.Y, x+size, column.Min.Y+size), profile)
+		} else {
+			return nil, err
+		}
+
+		context.SetSrc(textColor)
+		context.SetClip(column)
+		if err = drawFittedString(context, column, player.BattleTag, FontSizeComparison, column.Dy()-Margin); err != nil {
+			return nil, err
+		}
+	}
+
+	// sections and rows
+	y := ComparisonHeaderHeight
+	for _, section := range comparison.Sections {
+		rect := image.Rect(Margin, y, width-Margin, y+ComparisonRowHeight)
+		context.SetSrc(textColor)
+		context.SetClip(rect)
+		if err = drawFittedString(context, rect, section.Name, FontSizeComparison+2, ComparisonRowHeight-Margin-1); err != nil {
+			return nil, err
+		}
+		y += ComparisonRowHeight
+
+		for _, row := range section.Rows {
+			rect := image.Rect(Margin*3, y, ComparisonLabelWidth-Margin, y+ComparisonRowHeight)
+			context.SetSrc(textColor)
+			context.SetClip(rect)
+			if err = drawFittedString(context, rect, row.Label, FontSizeComparison, ComparisonRowHeight-Margin-2); err != nil {
+				return nil, err
+			}
+
+			for i, cell := range row.Cells {
+				rect := image.Rect(
+					ComparisonLabelWidth+ComparisonColumnWidth*i, y+1,
+					ComparisonLabelWidth+ComparisonColumnWidth*(i+1)-Margin, y+ComparisonRowHeight-1,
+				)
+				if cell.Leader {
+					draw.Draw(img, rect, &image.Uniform{ColorLeader}, image.ZP, draw.Src)
+					context.SetSrc(leaderTextColor)
+				} else {
+					context.SetSrc(textColor)
+				}
+
+				text := rect.Inset(Margin)
+				context.SetClip(rect)
+				if err = drawFittedString(context, text, cell.Value, FontSizeComparison, ComparisonRowHeight-Margin*2-3); err != nil {
+					return nil, err
+				}
+			}
+
+			y += ComparisonRowHeight
+		}
+	}
+
+	return img, nil
+}
+
+// values of given hero's career stats, from all categories
+func careerStatValues(playStat PlayStat, heroName string) (values KeyValues, exists bool) {
+	for _, careerStat := range playStat.CareerStats {
+		if careerStat.HeroName == heroName {
+			values = KeyValues{}
+			for _, category := range careerStat.Categories {
+				values = append(values, category.Values...)
+			}
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+// labels of all given values, in the order of their first appearances
+func orderedLabels(values []KeyValues) []string {
+	set := map[string]bool{}
+	labels := []string{}
+	for _, v := range values {
+		for _, value := range v {
+			if !set[value.Key] {
+				set[value.Key] = true
+				labels = append(labels, value.Key)
+			}
+		}
+	}
+	return labels
+}
+
+// rows of given labels, with values of each player
+func comparisonRows(labels []string, values []KeyValues) (rows []ComparisonRow) {
+	for _, label := range labels {
+		cells := []string{}
+		for _, v := range values {
+			if value, exists := v.Lookup(label); exists {
+				cells = append(cells, value)
+			} else {
+				cells = append(cells, NoValue)
+			}
+		}
+		rows = append(rows, newComparisonRow(label, cells))
+	}
+	return rows
+}
+
+// new row of given values, with its leaders marked
+func newComparisonRow(label string, values []string) ComparisonRow {
+	row := ComparisonRow{Label: label, Cells: []ComparisonCell{}}
+
+	lowerIsBetter := false
+	if english, exists := CanonicalLabel(label); exists {
+		lowerIsBetter = containsLabel(labelsLowerIsBetter, english)
+	}
+
+	// find the best value
+	numbers := []float64{}
+	comparables, best := 0, 0.0
+	for _, value := range values {
+		row.Cells = append(row.Cells, ComparisonCell{Value: value})
+
+		number, ok := comparableValue(value)
+		numbers = append(numbers, number)
+		if !ok {
+			continue
+		}
+
+		if comparables == 0 || (lowerIsBetter && number < best) || (!lowerIsBetter && number > best) {
+			best = number
+		}
+		comparables++
+	}
+
+	// mark leaders (only when there are players to compare)
+	if len(values) >= 2 && comparables > 0 {
+		for i, value := range values {
+			if _, ok := comparableValue(value); ok && numbers[i] == best {
+				row.Cells[i].Leader = true
+			}
+		}
+	}
+
+	return row
+}
+
+// parse given value into a comparable number (durations are in seconds)
+func comparableValue(value string) (float64, bool) {
+	if strings.TrimSpace(value) == NoValue {
+		return 0, false
+	}
+	if number, err := ParseNumber(value); err == nil {
+		return number, true
+	}
+	if duration, err := ParseDuration(value); err == nil {
+		return duration.Seconds(), true
+	}
+	return 0, false
+}
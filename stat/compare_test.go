@@ -0,0 +1,133 @@
+package stat_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// find a row of comparison with given section and label
+func comparisonRow(t *testing.T, comparison stat.Comparison, section, label string) stat.ComparisonRow {
+	for _, s := range comparison.Sections {
+		if s.Name != section {
+			continue
+		}
+		for _, row := range s.Rows {
+			if row.Label == label {
+				return row
+			}
+		}
+	}
+	t.Fatalf("no row '%s' in section '%s'", label, section)
+	return stat.ComparisonRow{}
+}
+
+func leaders(row stat.ComparisonRow) (result []bool) {
+	for _, cell := range row.Cells {
+		result = append(result, cell.Leader)
+	}
+	return result
+}
+
+func TestCompareStats(t *testing.T) {
+	server := newImageServer(t)
+	a := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)   // level 89, sr 3537, endorsement 4
+	b := statWithLocalImages(t, stattest.FixturePcNoCompetitive, server.URL) // level 37, no sr, endorsement 5
+
+	// (games lost and environmental deaths, which are not in quick play of the fixtures)
+	for i, lost := range []string{"10", "50"} {
+		s := []*stat.Stat{&a, &b}[i]
+		for j, careerStat := range s.QuickPlay.CareerStats {
+			if careerStat.HeroName == "ALL HEROES" {
+				s.QuickPlay.CareerStats[j].Categories = append(careerStat.Categories, stat.CareerStatCategory{Name: "Game", Values: stat.KeyValues{{Key: "Games Lost", Value: lost}, {Key: "Environmental Deaths", Value: lost}}})
+			}
+		}
+	}
+
+	comparison := stat.CompareStats([]stat.Stat{a, b}, false)
+
+	if len(comparison.Players) != 2 || comparison.Players[0].BattleTag != a.BattleTag || comparison.Players[1].BattleTag != b.BattleTag {
+		t.Fatalf("unexpected players: %+v", comparison.Players)
+	}
+
+	// sections: profile, featured stats, then shared heroes in the order of the first player's
+	names := []string{}
+	for _, section := range comparison.Sections {
+		names = append(names, section.Name)
+	}
+	expected := []string{stat.ComparisonSectionProfile, stat.ComparisonSectionFeatured, "ALL HEROES", "Ana", "Reinhardt", "Soldier: 76", "Mercy", "D.Va"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected sections %v, got %v", expected, names)
+	}
+
+	for _, test := range []struct {
+		section string
+		label   string
+		values  []string
+		leaders []bool
+	}{
+		{stat.ComparisonSectionProfile, "Level", []string{"89", "37"}, []bool{true, false}},
+		{stat.ComparisonSectionProfile, "Competitive Rank", []string{"3537", stat.NoValue}, []bool{true, false}},
+		{stat.ComparisonSectionProfile, "Endorsement Level", []string{"4", "5"}, []bool{false, true}},
+		{stat.ComparisonSectionFeatured, "Deaths - Average", []string{"19.05", "7.55"}, []bool{false, true}}, // lower is better
+		{"ALL HEROES", "Games Lost", []string{"10", "50"}, []bool{true, false}},
+		{"ALL HEROES", "Environmental Deaths", []string{"10", "50"}, []bool{true, false}},
+		{"ALL HEROES", "Games Won", []string{"19", "33"}, []bool{false, true}}, // higher is better
+	} {
+		row := comparisonRow(t, comparison, test.section, test.label)
+		for i, cell := range row.Cells {
+			if cell.Value != test.values[i] {
+				t.Errorf("expected value '%s' of %s/%s for player #%d, got '%s'", test.values[i], test.section, test.label, i, cell.Value)
+			}
+		}
+		if got := leaders(row); len(got) != len(test.leaders) || got[0] != test.leaders[0] || got[1] != test.leaders[1] {
+			t.Errorf("expected leaders %v of %s/%s, got %v", test.leaders, test.section, test.label, got)
+		}
+	}
+
+	// ties are all leaders, and a single player has no leaders
+	tie := stat.CompareStats([]stat.Stat{a, a}, false)
+	if got := leaders(comparisonRow(t, tie, stat.ComparisonSectionProfile, "Level")); !got[0] || !got[1] {
+		t.Errorf("expected both to be leaders on a tie, got %v", got)
+	}
+	single := stat.CompareStats([]stat.Stat{a}, false)
+	if got := leaders(comparisonRow(t, single, stat.ComparisonSectionProfile, "Level")); got[0] {
+		t.Errorf("expected no leaders for a single player, got %v", got)
+	}
+
+	// localized in the language of the first stat
+	korean := stattest.FixturePcCompetitiveKorean.Stat(t)
+	localized := stat.CompareStats([]stat.Stat{korean, a}, true)
+	if names := []string{localized.Sections[0].Name, localized.Sections[1].Name}; names[0] != "프로필" || names[1] != "주요 통계" {
+		t.Errorf("unexpected names of sections in korean: %v", names)
+	}
+	if got := leaders(comparisonRow(t, localized, "프로필", "레벨")); len(got) != 2 {
+		t.Errorf("expected a row of levels in korean, got %v", got)
+	}
+
+	// html
+	html, err := stat.RenderComparisonToHtml(comparison, stat.SampleComparisonHtmlTemplate)
+	if err != nil {
+		t.Fatalf("failed to render comparison to html: %s", err)
+	}
+	if !strings.Contains(html, `class="leader"`) || !strings.Contains(html, a.BattleTag) || !strings.Contains(html, b.BattleTag) {
+		t.Errorf("html should contain battletags and leaders")
+	}
+
+	// png
+	pngBytes, err := stat.RenderComparisonToPngBytes(comparison, testFont(t))
+	if err != nil {
+		t.Fatalf("failed to render comparison to png: %s", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngBytes))
+	if err != nil {
+		t.Fatalf("failed to decode comparison png: %s", err)
+	}
+	if width := stat.ComparisonLabelWidth + stat.ComparisonColumnWidth*2 + stat.Margin; img.Bounds().Dx() != width {
+		t.Errorf("expected width %d, got %d", width, img.Bounds().Dx())
+	}
+}
@@ -0,0 +1,94 @@
+package stat
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// a changed value between two stats
+type StatChange struct {
+	Path string `json:"path"` // eg. "quick_play.career_stats[Ana][Combat][Eliminations]"
+	Old  string `json:"old"`  // empty when newly added
+	New  string `json:"new"`  // empty when removed
+}
+
+func (c StatChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, orDash(c.Old), orDash(c.New))
+}
+
+// get changed values from old stat to new one, in the order of the career page
+func DiffStats(old, new Stat) (changes []StatChange) {
+	changes = []StatChange{}
+
+	olds, news := flattenStat(old), flattenStat(new)
+	oldValues, newValues := olds.Map(), news.Map()
+	for _, v := range news {
+		if value, exists := oldValues[v.Key]; !exists || value != v.Value {
+			changes = append(changes, StatChange{Path: v.Key, Old: value, New: v.Value})
+		}
+	}
+	for _, v := range olds {
+		if _, exists := newValues[v.Key]; !exists {
+			changes = append(changes, StatChange{Path: v.Key, Old: v.Value})
+		}
+	}
+
+	return changes
+}
+
+// get the value of given path in given stat (paths are the same as ones of StatChange, eg. "level")
+func StatValue(s Stat, path string) (string, bool) {
+	return flattenStat(s).Lookup(path)
+}
+
+// flatten values of given stat into paths and values
+func flattenStat(s Stat) KeyValues {
+	values := KeyValues{
+		{Key: "level", Value: strconv.Itoa(int(s.Level))},
+		{Key: "competitive_rank", Value: strconv.Itoa(int(s.CompetitiveRank))},
+		{Key: "endorsement_level", Value: strconv.Itoa(int(s.EndorsementLevel))},
+		{Key: "private", Value: strconv.FormatBool(s.Private)},
+	}
+
+	for _, play := range []struct {
+		path string
+		stat PlayStat
+	}{
+		{"quick_play", s.QuickPlay},
+		{"competitive_play", s.CompetitivePlay},
+	} {
+		for _, v := range play.stat.FeaturedStats {
+			values = append(values, KeyValue{Key: fmt.Sprintf("%s.featured_stats[%s]", play.path, v.Key), Value: v.Value})
+		}
+		for _, comparison := range play.stat.TopHeroes {
+			for _, hero := range comparison.Heroes {
+				values = append(values, KeyValue{Key: fmt.Sprintf("%s.top_heroes[%s][%s]", play.path, comparison.Name, hero.Name), Value: hero.Value})
+			}
+		}
+		for _, careerStat := range play.stat.CareerStats {
+			for _, category := range careerStat.Categories {
+				for _, v := range category.Values {
+					values = append(values, KeyValue{Key: fmt.Sprintf("%s.career_stats[%s][%s][%s]", play.path, careerStat.HeroName, category.Name, v.Key), Value: v.Value})
+				}
+			}
+		}
+	}
+
+	for _, category := range s.Achievements {
+		for _, achievement := range category.Achieved {
+			values = append(values, KeyValue{Key: fmt.Sprintf("achievements[%s][%s]", category.Name, achievement.Title), Value: "true"})
+		}
+		for _, achievement := range category.NonAchieved {
+			values = append(values, KeyValue{Key: fmt.Sprintf("achievements[%s][%s]", category.Name, achievement.Title), Value: "false"})
+		}
+	}
+
+	return values
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
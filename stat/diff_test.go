@@ -0,0 +1,40 @@
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestDiffStats(t *testing.T) {
+	// same stats, no changes
+	for _, fixture := range []stattest.Fixture{stattest.FixturePcCompetitive, stattest.FixturePcPrivate} {
+		s := fixture.Stat(t)
+		if changes := stat.DiffStats(s, s); len(changes) != 0 {
+			t.Errorf("expected no changes in %s, got: %v", fixture.Name, changes)
+		}
+	}
+
+	old := overlayStat(2500, stat.KeyValues{{Key: "Games Won", Value: "10"}, {Key: "Games Lost", Value: "7"}})
+	new := overlayStat(2525, stat.KeyValues{{Key: "Games Won", Value: "11"}, {Key: "Games Tied", Value: "1"}})
+	expected := []stat.StatChange{
+		{Path: "competitive_rank", Old: "2500", New: "2525"},
+		{Path: "competitive_play.career_stats[ALL HEROES][Game][Games Won]", Old: "10", New: "11"},
+		{Path: "competitive_play.career_stats[ALL HEROES][Game][Games Tied]", Old: "", New: "1"},
+		{Path: "competitive_play.career_stats[ALL HEROES][Game][Games Lost]", Old: "7", New: ""},
+	}
+
+	changes := stat.DiffStats(old, new)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got: %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change != expected[i] {
+			t.Errorf("expected change %v, got %v", expected[i], change)
+		}
+	}
+	if str := changes[2].String(); str != "competitive_play.career_stats[ALL HEROES][Game][Games Tied]: - -> 1" {
+		t.Errorf("unexpected string of change: %s", str)
+	}
+}
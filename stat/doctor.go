@@ -0,0 +1,334 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// result of checking a css selector against a career page
+type SelectorCheck struct {
+	Section  string `json:"section"`
+	Name     string `json:"name"`
+	Selector string `json:"selector"`
+	Matched  int    `json:"matched"`
+	Expected int    `json:"expected"`
+	Exact    bool   `json:"exact"` // when true, Matched should be equal to Expected (otherwise, at least Expected)
+	Ok       bool   `json:"ok"`
+}
+
+// report of checking the parser's selectors against a career page
+type LayoutReport struct {
+	Url         string          `json:"url,omitempty"`
+	Checks      []SelectorCheck `json:"checks"`
+	EmptyFields []string        `json:"empty_fields"`
+	ParseError  string          `json:"parse_error,omitempty"`
+	Private     bool            `json:"private"` // when true, only the masthead was checked
+}
+
+// check if the career page's layout drifted from what the parser expects
+func (r LayoutReport) Drifted() bool {
+	if r.ParseError != "" || len(r.EmptyFields) > 0 {
+		return true
+	}
+	for _, check := range r.Checks {
+		if !check.Ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch given user's career page and check it with the parser's selectors
+func DiagnoseLayout(battleTagString string, battleTagNumber int, platform, region, language string) (report LayoutReport, err error) {
+	url := GenUrl(battleTagString, battleTagNumber, platform, region, language)
+
+	if Verbose {
+		log.Printf("> fetching from url: %s\n", url)
+	}
+
+	var doc *goquery.Document
+	doc, err = fetchDocument(url)
+	if err != nil {
+		return LayoutReport{}, err
+	}
+
+	report = diagnoseLayout(doc, battleTagString, battleTagNumber, platform, region)
+	report.Url = url
+
+	return report, nil
+}
+
+// check a saved career page with the parser's selectors
+func DiagnoseLayoutFromReader(r io.Reader, battleTagString string, battleTagNumber int, platform, region string) (report LayoutReport, err error) {
+	var doc *goquery.Document
+	doc, err = goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return LayoutReport{}, err
+	}
+
+	return diagnoseLayout(doc, battleTagString, battleTagNumber, platform, region), nil
+}
+
+// run every selector of the parser on given document, then parse it and look for empty fields
+func diagnoseLayout(doc *goquery.Document, battleTagString string, battleTagNumber int, platform, region string) LayoutReport {
+	checker := layoutChecker{doc: doc}
+	private := isPrivate(doc)
+
+	////////////////
+	// [info]
+	//
+	checker.atLeast("masthead", "name", selectorName, "", 1)
+	checker.atLeast("masthead", "profile image", selectorProfileImage, "src", 1)
+	checker.atLeast("masthead", "level", selectorLevel, "", 1)
+	checker.atLeast("masthead", "level image", selectorLevelImage, "style", 1)
+	checker.atLeast("masthead", "level star image", selectorLevelStarImage, "style", 0)
+	competitiveRanks := checker.atLeast("masthead", "competitive rank", selectorCompetitiveRank, "", 0)
+	checker.atLeast("masthead", "competitive rank image", selectorCompetitiveRankImage, "src", 0)
+	checker.atLeast("masthead", "endorsement level", selectorEndorsementLevel, "", 0)
+	for _, endorsement := range []string{"shotcaller", "teammate", "sportsmanship"} {
+		checker.atLeast("masthead", "endorsement "+endorsement, fmt.Sprintf(selectorEndorsementBreakdown, endorsement), "data-value", 0)
+	}
+	if private {
+		checker.atLeast("masthead", "detail", selectorDetail, "", 0)
+
+		return diagnosedReport(doc, checker, battleTagString, battleTagNumber, platform, region)
+	}
+	checker.atLeast("masthead", "detail", selectorDetail, "", 1)
+	//
+	////////////////
+	// [stats] quick play is always expected, competitive play only when the player has a rank
+	//
+	checker.checkPlayStat(TagIdQuickPlay, 1)
+	if competitiveRanks > 0 {
+		checker.checkPlayStat(TagIdCompetitivePlay, 1)
+	} else {
+		checker.checkPlayStat(TagIdCompetitivePlay, 0)
+	}
+	//
+	////////////////
+	// [achievements]
+	//
+	categories := checker.atLeast("achievements", "categories", selectorAchievementCategories, "", 1)
+	var cards, images, titles, descriptions int
+	for i := 0; i < categories; i++ {
+		cards += checker.count(fmt.Sprintf(selectorAchievementCards, i+2 /* skip first one */), "class")
+		images += checker.count(fmt.Sprintf(selectorAchievementImages, i+2 /* skip first one */), "src")
+		titles += checker.count(fmt.Sprintf(selectorAchievementTitles, i+2 /* skip first one */), "")
+		descriptions += checker.count(fmt.Sprintf(selectorAchievementDescriptions, i+2 /* skip first one */), "")
+	}
+	// (selectors are reported with the first category)
+	checker.add("achievements", "cards", fmt.Sprintf(selectorAchievementCards, 2), cards, 1, false)
+	checker.add("achievements", "images", fmt.Sprintf(selectorAchievementImages, 2), images, cards, true)
+	checker.add("achievements", "titles", fmt.Sprintf(selectorAchievementTitles, 2), titles, cards, true)
+	checker.add("achievements", "descriptions", fmt.Sprintf(selectorAchievementDescriptions, 2), descriptions, cards, true)
+
+	return diagnosedReport(doc, checker, battleTagString, battleTagNumber, platform, region)
+}
+
+// parse given document and look for empty fields, then return the report with the results of checks
+func diagnosedReport(doc *goquery.Document, checker layoutChecker, battleTagString string, battleTagNumber int, platform, region string) LayoutReport {
+	report := LayoutReport{
+		Checks:      checker.checks,
+		EmptyFields: []string{},
+	}
+
+	if stat, err := parseStat(doc, battleTagString, battleTagNumber, platform, region); err == nil {
+		report.EmptyFields = emptyFields(stat)
+	} else if err == ErrPrivateProfile {
+		report.EmptyFields = emptyFields(stat)
+		report.Private = true
+	} else {
+		report.ParseError = err.Error()
+	}
+
+	return report
+}
+
+// collects results of selector checks
+type layoutChecker struct {
+	doc    *goquery.Document
+	checks []SelectorCheck
+}
+
+// count elements matching given selector (and having given attribute, if not empty)
+func (c *layoutChecker) count(selector, attrName string) int {
+	if attrName == "" {
+		return c.doc.Find(selector).Length()
+	}
+
+	count := 0
+	c.doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+		if _, exists := s.Attr(attrName); exists {
+			count++
+		}
+	})
+	return count
+}
+
+// count matches of given selector and check if there are at least `expected` ones
+func (c *layoutChecker) atLeast(section, name, selector, attrName string, expected int) int {
+	matched := c.count(selector, attrName)
+	c.add(section, name, selector, matched, expected, false)
+	return matched
+}
+
+func (c *layoutChecker) add(section, name, selector string, matched, expected int, exact bool) {
+	var ok bool
+	if exact {
+		ok = matched == expected
+	} else {
+		ok = matched >= expected
+	}
+
+	c.checks = append(c.checks, SelectorCheck{
+		Section:  section,
+		Name:     name,
+		Selector: selector,
+		Matched:  matched,
+		Expected: expected,
+		Exact:    exact,
+		Ok:       ok,
+	})
+}
+
+// check selectors of featured stats, top heroes, and career stats
+func (c *layoutChecker) checkPlayStat(id TagId, expected int) {
+	section := string(id)
+
+	////////////////
+	// featured stats
+	featuredStatTitles := c.atLeast(section, "featured stat titles", fmt.Sprintf(selectorFeaturedStatTitles, id), "", expected)
+	c.add(section, "featured stat values", fmt.Sprintf(selectorFeaturedStatValues, id), c.count(fmt.Sprintf(selectorFeaturedStatValues, id), ""), featuredStatTitles, true)
+	//
+	////////////////
+	// top heroes
+	comparisons := c.atLeast(section, "top hero comparisons", fmt.Sprintf(selectorTopHeroComparisons, id), "", expected)
+	var names, images, values int
+	for i := 0; i < comparisons; i++ {
+		names += c.count(fmt.Sprintf(selectorTopHeroNames, id, i+2 /* skip first one */), "")
+		images += c.count(fmt.Sprintf(selectorTopHeroImages, id, i+2 /* skip first one */), "src")
+		values += c.count(fmt.Sprintf(selectorTopHeroValues, id, i+2 /* skip first one */), "")
+	}
+	// (selectors are reported with the first comparison)
+	c.add(section, "top hero names", fmt.Sprintf(selectorTopHeroNames, id, 2), names, expected, false)
+	c.add(section, "top hero images", fmt.Sprintf(selectorTopHeroImages, id, 2), images, names, true)
+	c.add(section, "top hero values", fmt.Sprintf(selectorTopHeroValues, id, 2), values, names, true)
+	//
+	////////////////
+	// career stats
+	statIds := []string{}
+	c.doc.Find(fmt.Sprintf(selectorCareerStatIds, id)).Each(func(i int, s *goquery.Selection) {
+		if statId, exists := s.Attr("data-category-id"); exists {
+			statIds = append(statIds, statId)
+		}
+	})
+	c.add(section, "career stat heroes", fmt.Sprintf(selectorCareerStatIds, id), len(statIds), expected, false)
+	var heroNames, categoryNames, attrs, attrValues int
+	for _, statId := range statIds {
+		if c.count(fmt.Sprintf(selectorCareerStatHeroName, id, statId), "") > 0 {
+			heroNames++
+		}
+		categories := c.count(fmt.Sprintf(selectorCareerStatCategoryNames, id, statId), "")
+		for i := 0; i < categories; i++ {
+			attrs += c.count(fmt.Sprintf(selectorCareerStatCategoryAttrs, id, statId, i+1), "")
+			attrValues += c.count(fmt.Sprintf(selectorCareerStatCategoryValues, id, statId, i+1), "")
+		}
+		categoryNames += categories
+	}
+	// (selectors are reported with the first hero and category)
+	firstStatId := ""
+	if len(statIds) > 0 {
+		firstStatId = statIds[0]
+	}
+	c.add(section, "career stat hero names", fmt.Sprintf(selectorCareerStatHeroName, id, firstStatId), heroNames, len(statIds), true)
+	c.add(section, "career stat categories", fmt.Sprintf(selectorCareerStatCategoryNames, id, firstStatId), categoryNames, expected, false)
+	c.add(section, "career stat names", fmt.Sprintf(selectorCareerStatCategoryAttrs, id, firstStatId, 1), attrs, expected, false)
+	c.add(section, "career stat values", fmt.Sprintf(selectorCareerStatCategoryValues, id, firstStatId, 1), attrValues, attrs, true)
+}
+
+// list names of fields which came back empty
+func emptyFields(stat Stat) []string {
+	fields := []string{}
+
+	check := func(name, value string) {
+		if strings.TrimSpace(value) == "" {
+			fields = append(fields, name)
+		}
+	}
+
+	check("name", stat.Name)
+	check("profile_image_url", stat.ProfileImageUrl)
+	if stat.Level <= 0 {
+		fields = append(fields, "level")
+	}
+	check("level_image_url", stat.LevelImageUrl)
+	if stat.CompetitiveRank != NoCompetitiveRank {
+		check("competitive_rank_image_url", stat.CompetitiveRankImageUrl)
+	}
+	if stat.Private { // no more fields for private profiles
+		sort.Strings(fields)
+		return fields
+	}
+	check("detail", stat.Detail)
+
+	checkPlayStat := func(prefix string, playStat PlayStat) {
+		if len(playStat.FeaturedStats) <= 0 {
+			fields = append(fields, prefix+".featured_stats")
+		}
+		for _, featured := range playStat.FeaturedStats {
+			check(fmt.Sprintf("%s.featured_stats[%s]", prefix, featured.Key), featured.Value)
+		}
+		if len(playStat.TopHeroes) <= 0 {
+			fields = append(fields, prefix+".top_heroes")
+		}
+		for _, comparison := range playStat.TopHeroes {
+			for i, hero := range comparison.Heroes {
+				check(fmt.Sprintf("%s.top_heroes[%s][%d].name", prefix, comparison.Name, i), hero.Name)
+				check(fmt.Sprintf("%s.top_heroes[%s][%d].image_url", prefix, comparison.Name, i), hero.ImageUrl)
+				check(fmt.Sprintf("%s.top_heroes[%s][%d].value", prefix, comparison.Name, i), hero.Value)
+			}
+		}
+		if len(playStat.CareerStats) <= 0 {
+			fields = append(fields, prefix+".career_stats")
+		}
+		for i, careerStat := range playStat.CareerStats {
+			check(fmt.Sprintf("%s.career_stats[%d].hero_name", prefix, i), careerStat.HeroName)
+			for _, category := range careerStat.Categories {
+				if len(category.Values) <= 0 {
+					fields = append(fields, fmt.Sprintf("%s.career_stats[%s][%s]", prefix, careerStat.HeroName, category.Name))
+				}
+				for _, value := range category.Values {
+					check(fmt.Sprintf("%s.career_stats[%s][%s][%s]", prefix, careerStat.HeroName, category.Name, value.Key), value.Value)
+				}
+			}
+		}
+	}
+	checkPlayStat("quick_play", stat.QuickPlay)
+	if stat.CompetitiveRank != NoCompetitiveRank {
+		checkPlayStat("competitive_play", stat.CompetitivePlay)
+	}
+
+	if len(stat.Achievements) <= 0 {
+		fields = append(fields, "achievements")
+	}
+	for _, category := range stat.Achievements {
+		for i, achievement := range category.Achieved {
+			check(fmt.Sprintf("achievements[%s].achieved[%d].title", category.Name, i), achievement.Title)
+			check(fmt.Sprintf("achievements[%s].achieved[%d].image_url", category.Name, i), achievement.ImageUrl)
+		}
+		for i, achievement := range category.NonAchieved {
+			check(fmt.Sprintf("achievements[%s].non_achieved[%d].title", category.Name, i), achievement.Title)
+			check(fmt.Sprintf("achievements[%s].non_achieved[%d].image_url", category.Name, i), achievement.ImageUrl)
+		}
+	}
+
+	// (map iteration order is random)
+	sort.Strings(fields)
+
+	return fields
+}
@@ -1,8 +1,11 @@
 package stat
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -10,7 +13,8 @@ import (
 )
 
 const (
-	NoCompetitiveRank = -1
+	NoCompetitiveRank  = -1
+	NoEndorsementLevel = -1
 )
 
 const (
@@ -19,17 +23,121 @@ const (
 	PlatformPsn = "psn"
 )
 
+// css selectors for parsing career pages
+//
+// XXX - if it stops working, should check the html response and alter these selectors
+const (
+	// info
+	selectorLanguage             = "html"
+	selectorName                 = "div.masthead-player > h1.header-masthead"
+	selectorProfileImage         = "div.masthead-player > img.player-portrait"
+	selectorLevel                = "div.player-level > div:nth-child(1)"
+	selectorLevelImage           = "div.player-level"
+	selectorLevelStarImage       = "div.player-level > div.player-rank"
+	selectorCompetitiveRank      = "div.competitive-rank > div"
+	selectorCompetitiveRankImage = "div.competitive-rank > img"
+	selectorDetail               = "div.masthead > p.masthead-detail > span"
+	selectorPermissionLevel      = "div.masthead-player .masthead-permission-level-text"
+	selectorEndorsementLevel     = "div.endorsement-level div.u-center"
+	selectorEndorsementBreakdown = "div.endorsement-level svg.EndorsementIcon-border--%s" // %s: shotcaller, teammate, or sportsmanship
+
+	// stats of quick/competitive play (%s: tag id)
+	selectorPlayStat = "#%s"
+
+	// featured stats (%s: tag id)
+	selectorFeaturedStatTitles = "#%s > section.highlights-section div.card-content > p"
+	selectorFeaturedStatValues = "#%s > section.highlights-section div.card-content > h3"
+	selectorFeaturedStatValue  = "#%s > section.highlights-section li:nth-child(%d) div.card-content > h3"
+
+	// top heroes (%s: tag id, %d: index of comparison)
+	selectorTopHeroComparisons = "#%s > section.hero-comparison-section select[data-group-id=\"comparisons\"] > option"
+	selectorTopHeroNames       = "#%s > section.hero-comparison-section > div > div:nth-of-type(%d) div.bar-text > div.title"
+	selectorTopHeroImages      = "#%s > section.hero-comparison-section > div > div:nth-of-type(%d) img"
+	selectorTopHeroValues      = "#%s > section.hero-comparison-section > div > div:nth-of-type(%d) div.bar-text > div.description"
+
+	// career stats (%s: tag id, %s: stat id, %d: index of category)
+	selectorCareerStatIds            = "#%s div[data-group-id=\"stats\"]"
+	selectorCareerStatHeroName       = "#%s option[value=\"%s\"]"
+	selectorCareerStatCategoryNames  = "#%s div[data-category-id=\"%s\"] div.card-stat-block > table.data-table > thead > tr > th > .stat-title"
+	selectorCareerStatCategoryAttrs  = "#%s div[data-category-id=\"%s\"] > div:nth-child(%d) > div.card-stat-block > table.data-table > tbody > tr > td:nth-child(1)"
+	selectorCareerStatCategoryValues = "#%s div[data-category-id=\"%s\"] > div:nth-child(%d) > div.card-stat-block > table.data-table > tbody > tr > td:nth-child(2)"
+
+	// achievements (%d: index of category)
+	selectorAchievementCategories   = "#achievements-section select > option"
+	selectorAchievementImages       = "#achievements-section > div > div:nth-of-type(%d) > ul div.achievement-card > img"
+	selectorAchievementTitles       = "#achievements-section > div > div:nth-of-type(%d) div.tooltip-tip > h6"
+	selectorAchievementDescriptions = "#achievements-section > div > div:nth-of-type(%d) div.tooltip-tip > p"
+	selectorAchievementCards        = "#achievements-section > div > div:nth-of-type(%d) > ul div.achievement-card"
+)
+
+// returned with public information (eg. name, portrait, and level) when the career profile is private
+var ErrPrivateProfile = errors.New("career profile is private")
+
+// returned when there is no career page of given player
+var ErrPlayerNotFound = errors.New("player not found")
+
+// returned when the site responded with an unexpected http status
+type StatusError struct {
+	Url        string
+	StatusCode int
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("unexpected http status %d from: %s", e.StatusCode, e.Url)
+}
+
+// returned when the career page could not be parsed (eg. layout of the site has changed)
+type ParseError struct {
+	Err error
+}
+
+func (e ParseError) Error() string {
+	return e.Err.Error()
+}
+
 var Verbose bool = false
 
+// base url of career pages (can be altered for testing, eg. with stattest package)
+var BaseUrl string = "https://playoverwatch.com"
+
+// split given battle tag (eg. "meinside#3155") into its name and number
+//
+// (for consoles, battle tag is returned as the name, with number 0)
+func ParseBattleTag(battleTag, platform string) (name string, number int, err error) {
+	if !strings.EqualFold(platform, PlatformPc) { // Console (XBL, PSN)
+		return battleTag, 0, nil
+	}
+
+	battleTags := strings.Split(battleTag, "#")
+	if len(battleTags) != 2 {
+		return "", 0, fmt.Errorf("Malformed battle tag: %s", battleTag)
+	}
+	if number, err = strconv.Atoi(battleTags[1]); err != nil {
+		return "", 0, fmt.Errorf("Malformed battle tag: %s (%s)", battleTag, err)
+	}
+	return battleTags[0], number, nil
+}
+
 // generate url for given params
 //
 // ex:
-//		https://playoverwatch.com/en-us/career/pc/kr/meinside-3155
-//		https://playoverwatch.com/ko-kr/career/xbl/meinside
-//		https://playoverwatch.com/ru-ru/career/psn/meinside
+//
+//	https://playoverwatch.com/en-us/career/pc/kr/meinside-3155
+//	https://playoverwatch.com/ko-kr/career/xbl/meinside
+//	https://playoverwatch.com/ru-ru/career/psn/meinside
 func GenUrl(battleTagString string, battleTagNumber int, platform, region, language string) string {
+	return BaseUrl + GenPath(battleTagString, battleTagNumber, platform, region, language)
+}
+
+// generate url path for given params
+//
+// ex:
+//
+//	/en-us/career/pc/kr/meinside-3155
+//	/ko-kr/career/xbl/meinside
+func GenPath(battleTagString string, battleTagNumber int, platform, region, language string) string {
 	if strings.EqualFold(platform, PlatformPc) {
-		return fmt.Sprintf("https://playoverwatch.com/%s/career/%s/%s/%s-%d",
+		return fmt.Sprintf("/%s/career/%s/%s/%s-%d",
 			language,
 			platform,
 			region,
@@ -37,7 +145,7 @@ func GenUrl(battleTagString string, battleTagNumber int, platform, region, langu
 			battleTagNumber,
 		)
 	} else {
-		return fmt.Sprintf("https://playoverwatch.com/%s/career/%s/%s",
+		return fmt.Sprintf("/%s/career/%s/%s",
 			language,
 			platform,
 			battleTagString,
@@ -46,6 +154,8 @@ func GenUrl(battleTagString string, battleTagNumber int, platform, region, langu
 }
 
 // fetch given user's stat from official overwatch site.
+//
+// when the career profile is private, ErrPrivateProfile is returned with public information only
 func FetchStat(battleTagString string, battleTagNumber int, platform, region, language string) (result Stat, err error) {
 	url := GenUrl(battleTagString, battleTagNumber, platform, region, language)
 
@@ -55,7 +165,7 @@ func FetchStat(battleTagString string, battleTagNumber int, platform, region, la
 
 	// fetch html document,
 	var doc *goquery.Document
-	doc, err = goquery.NewDocument(url)
+	doc, err = fetchDocument(url)
 	if err != nil {
 		return Stat{}, err
 	}
@@ -65,30 +175,86 @@ func FetchStat(battleTagString string, battleTagNumber int, platform, region, la
 	}
 
 	// parse it and assign to struct
-	return parseStat(doc, battleTagString, battleTagNumber, platform, region)
+	return parseStatOrError(doc, battleTagString, battleTagNumber, platform, region)
+}
+
+// fetch html document from given url
+//
+// ErrPlayerNotFound is returned for 404, and StatusError for other non-200 responses
+func fetchDocument(url string) (doc *goquery.Document, err error) {
+	var res *http.Response
+	if res, err = http.Get(url); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return goquery.NewDocumentFromReader(res.Body)
+	case http.StatusNotFound:
+		return nil, ErrPlayerNotFound
+	default:
+		return nil, StatusError{Url: url, StatusCode: res.StatusCode}
+	}
+}
+
+// parse given user's stat from html document (eg. a saved career page)
+//
+// when the career profile is private, ErrPrivateProfile is returned with public information only
+func ParseStat(r io.Reader, battleTagString string, battleTagNumber int, platform, region string) (result Stat, err error) {
+	var doc *goquery.Document
+	doc, err = goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Stat{}, err
+	}
+
+	return parseStatOrError(doc, battleTagString, battleTagNumber, platform, region)
+}
+
+// parse stat from html document, and wrap errors (except ErrPrivateProfile) with ParseError
+func parseStatOrError(doc *goquery.Document, battleTagString string, battleTagNumber int, platform, region string) (result Stat, err error) {
+	if result, err = parseStat(doc, battleTagString, battleTagNumber, platform, region); err != nil && err != ErrPrivateProfile {
+		err = ParseError{Err: err}
+	}
+	return result, err
 }
 
 // parse stat from html bytes
 //
+// when the career profile is private, ErrPrivateProfile is returned with public information only
+//
 // XXX - if it stops working, should check the html response and alter css selectors
 func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber int, platform, region string) (result Stat, err error) {
+	var battleTag string
+	if strings.EqualFold(platform, PlatformPc) {
+		battleTag = fmt.Sprintf("%s#%d", battleTagString, battleTagNumber)
+	} else {
+		battleTag = battleTagString
+	}
+
+	private := isPrivate(doc)
+
+	// language of the career page (eg. "ko-kr")
+	language, _ := extractFirstAttrString(doc, selectorLanguage, "lang")
+	language = strings.ToLower(language)
+
 	////////////////
 	// [info]
 	//
 	var name string
-	if name, err = extractString(doc, "div.masthead-player > h1.header-masthead"); err != nil {
+	if name, err = extractString(doc, selectorName); err != nil {
 		return Stat{}, err
 	}
 	var profileImageUrl string
-	if profileImageUrl, err = extractFirstAttrString(doc, "div.masthead-player > img.player-portrait", "src"); err != nil {
+	if profileImageUrl, err = extractFirstAttrString(doc, selectorProfileImage, "src"); err != nil {
 		return Stat{}, err
 	}
 	var level int32
-	if level, err = extractInt32(doc, "div.player-level > div:nth-child(1)"); err != nil {
+	if level, err = extractInt32(doc, selectorLevel); err != nil {
 		return Stat{}, err
 	}
 	var levelImageUrl string
-	if levelImageUrl, err = extractFirstAttrString(doc, "div.player-level", "style"); err != nil {
+	if levelImageUrl, err = extractFirstAttrString(doc, selectorLevelImage, "style"); err != nil {
 		return Stat{}, err
 	} else {
 		// XXX - strip background-image:url(...)
@@ -100,7 +266,7 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 		}
 	}
 	var levelStarImageUrl string
-	if levelStarImageUrl, err = extractFirstAttrString(doc, "div.player-level > div.player-rank", "style"); err == nil {
+	if levelStarImageUrl, err = extractFirstAttrString(doc, selectorLevelStarImage, "style"); err == nil {
 		// XXX - strip background-image:url(...)
 		if strings.HasPrefix(levelStarImageUrl, "background-image:url(") {
 			levelStarImageUrl = strings.TrimLeft(levelStarImageUrl, "background-image:url(")
@@ -110,21 +276,53 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 		}
 	}
 	var competitiveRank int32
-	if competitiveRank, err = extractInt32(doc, "div.competitive-rank > div"); err != nil {
+	if competitiveRank, err = extractInt32(doc, selectorCompetitiveRank); err != nil {
 		competitiveRank = NoCompetitiveRank
 	}
 	var competitiveRankImageUrl string
-	competitiveRankImageUrl, _ = extractFirstAttrString(doc, "div.competitive-rank > img", "src")
+	competitiveRankImageUrl, _ = extractFirstAttrString(doc, selectorCompetitiveRankImage, "src")
+	var endorsementLevel int32
+	if endorsementLevel, err = extractInt32(doc, selectorEndorsementLevel); err != nil {
+		endorsementLevel = NoEndorsementLevel
+	}
+	var endorsementBreakdown Endorsement
+	endorsementBreakdown.Shotcaller, _ = extractFirstAttrFloat32(doc, fmt.Sprintf(selectorEndorsementBreakdown, "shotcaller"), "data-value")
+	endorsementBreakdown.Teammate, _ = extractFirstAttrFloat32(doc, fmt.Sprintf(selectorEndorsementBreakdown, "teammate"), "data-value")
+	endorsementBreakdown.Sportsmanship, _ = extractFirstAttrFloat32(doc, fmt.Sprintf(selectorEndorsementBreakdown, "sportsmanship"), "data-value")
 	var detail string
-	if detail, err = extractString(doc, "div.masthead > p.masthead-detail > span"); err != nil {
+	if detail, err = extractString(doc, selectorDetail); err != nil && !private {
 		return Stat{}, err
 	}
 	//
 	////////////////
+	// [private] no stats nor achievements are shown
+	//
+	if private {
+		return Stat{
+			BattleTag: battleTag,
+			Platform:  platform,
+			Region:    region,
+			Language:  language,
+
+			Name:                    name,
+			ProfileImageUrl:         profileImageUrl,
+			Level:                   level,
+			LevelImageUrl:           levelImageUrl,
+			LevelStarImageUrl:       levelStarImageUrl,
+			CompetitiveRank:         competitiveRank,
+			CompetitiveRankImageUrl: competitiveRankImageUrl,
+			EndorsementLevel:        endorsementLevel,
+			EndorsementBreakdown:    endorsementBreakdown,
+			Detail:                  detail,
+			Private:                 true,
+		}, ErrPrivateProfile
+	}
+	//
+	////////////////
 	// [stats] quick play
 	//
-	var featuredStats map[string]string
-	var topHeroes map[string][]Hero
+	var featuredStats KeyValues
+	var topHeroes HeroComparisons
 	var careerStats []CareerStat
 	if featuredStats, topHeroes, careerStats, err = extractPlayStat(doc, TagIdQuickPlay); err != nil {
 		return Stat{}, err
@@ -156,7 +354,7 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 	//
 	achievements := []AchievementCategory{}
 	var achievementCategoryNames []string
-	if achievementCategoryNames, err = extractStrings(doc, "#achievements-section select > option"); err != nil {
+	if achievementCategoryNames, err = extractStrings(doc, selectorAchievementCategories); err != nil {
 		return Stat{}, err
 	}
 	for i, categoryName := range achievementCategoryNames {
@@ -165,18 +363,21 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 		// achieved/non-achieved achievements
 		achieved := []Achievement{}
 		nonAchieved := []Achievement{}
-		if urls, err = extractAttrStrings(doc, fmt.Sprintf("#achievements-section > div > div:nth-of-type(%d) > ul div.achievement-card > img", i+2 /* skip first one */), "src"); err != nil {
+		if urls, err = extractAttrStrings(doc, fmt.Sprintf(selectorAchievementImages, i+2 /* skip first one */), "src"); err != nil {
 			return Stat{}, err
 		}
-		if titles, err = extractStrings(doc, fmt.Sprintf("#achievements-section > div > div:nth-of-type(%d) div.tooltip-tip > h6", i+2 /* skip first one */)); err != nil {
+		if titles, err = extractStrings(doc, fmt.Sprintf(selectorAchievementTitles, i+2 /* skip first one */)); err != nil {
 			return Stat{}, err
 		}
-		if descriptions, err = extractStrings(doc, fmt.Sprintf("#achievements-section > div > div:nth-of-type(%d) div.tooltip-tip > p", i+2 /* skip first one */)); err != nil {
+		if descriptions, err = extractStrings(doc, fmt.Sprintf(selectorAchievementDescriptions, i+2 /* skip first one */)); err != nil {
 			return Stat{}, err
 		}
-		if classes, err = extractAttrStrings(doc, fmt.Sprintf("#achievements-section > div > div:nth-of-type(%d) > ul div.achievement-card", i+2 /* skip first one */), "class"); err != nil {
+		if classes, err = extractAttrStrings(doc, fmt.Sprintf(selectorAchievementCards, i+2 /* skip first one */), "class"); err != nil {
 			return Stat{}, err
 		}
+		if len(titles) != len(classes) || len(descriptions) != len(classes) || len(urls) != len(classes) {
+			return Stat{}, fmt.Errorf("number of elements mismatch in achievement category '%s': %d cards, %d titles, %d descriptions, %d images", categoryName, len(classes), len(titles), len(descriptions), len(urls))
+		}
 		for i, class := range classes {
 			if strings.Contains(class, "m-disabled") { // m-disabled: non-achieved achievement
 				nonAchieved = append(nonAchieved, Achievement{
@@ -200,18 +401,12 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 		})
 	}
 
-	var battleTag string
-	if strings.EqualFold(platform, PlatformPc) {
-		battleTag = fmt.Sprintf("%s#%d", battleTagString, battleTagNumber)
-	} else {
-		battleTag = battleTagString
-	}
-
 	// return result
 	return Stat{
 		BattleTag: battleTag,
 		Platform:  platform,
 		Region:    region,
+		Language:  language,
 
 		Name:                    name,
 		ProfileImageUrl:         profileImageUrl,
@@ -220,6 +415,8 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 		LevelStarImageUrl:       levelStarImageUrl,
 		CompetitiveRank:         competitiveRank,
 		CompetitiveRankImageUrl: competitiveRankImageUrl,
+		EndorsementLevel:        endorsementLevel,
+		EndorsementBreakdown:    endorsementBreakdown,
 		Detail:                  detail,
 
 		QuickPlay:       quickPlayStat,
@@ -229,43 +426,52 @@ func parseStat(doc *goquery.Document, battleTagString string, battleTagNumber in
 	}, err
 }
 
-func extractPlayStat(doc *goquery.Document, id TagId) (featuredStats map[string]string, topHeroes map[string][]Hero, careerStats []CareerStat, err error) {
-	featuredStats = make(map[string]string)
-	topHeroes = make(map[string][]Hero)
+// check if given document is a private career profile (which has no stats)
+func isPrivate(doc *goquery.Document) bool {
+	return doc.Find(selectorPermissionLevel).Length() > 0 &&
+		doc.Find(fmt.Sprintf(selectorPlayStat, TagIdQuickPlay)).Length() <= 0
+}
+
+func extractPlayStat(doc *goquery.Document, id TagId) (featuredStats KeyValues, topHeroes HeroComparisons, careerStats []CareerStat, err error) {
+	featuredStats = KeyValues{}
+	topHeroes = HeroComparisons{}
 	careerStats = []CareerStat{}
 
 	////////////////
 	// featured stats
 	var featuredStatTitles []string
-	if featuredStatTitles, err = extractStrings(doc, fmt.Sprintf("#%s > section.highlights-section div.card-content > p", id)); err != nil {
+	if featuredStatTitles, err = extractStrings(doc, fmt.Sprintf(selectorFeaturedStatTitles, id)); err != nil {
 		return featuredStats, topHeroes, careerStats, err
 	}
 	for i, title := range featuredStatTitles {
 		var value string
-		if value, err = extractString(doc, fmt.Sprintf("#%s > section.highlights-section li:nth-child(%d) div.card-content > h3", id, i+1)); err != nil {
+		if value, err = extractString(doc, fmt.Sprintf(selectorFeaturedStatValue, id, i+1)); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
-		featuredStats[title] = value
+		featuredStats = append(featuredStats, KeyValue{Key: title, Value: value})
 	}
 	//
 	////////////////
 	// top heroes
 	var comparison, heroNames, heroImageUrls, heroValues []string
-	if comparison, err = extractStrings(doc, fmt.Sprintf("#%s > section.hero-comparison-section select[data-group-id=\"comparisons\"] > option", id)); err != nil {
+	if comparison, err = extractStrings(doc, fmt.Sprintf(selectorTopHeroComparisons, id)); err != nil {
 		return featuredStats, topHeroes, careerStats, err
 	}
 	for i, comparison := range comparison {
 		heroes := []Hero{}
-		if heroNames, err = extractStrings(doc, fmt.Sprintf("#%s > section.hero-comparison-section > div > div:nth-of-type(%d) div.bar-text > div.title", id, i+2 /* skip first one */)); err != nil {
+		if heroNames, err = extractStrings(doc, fmt.Sprintf(selectorTopHeroNames, id, i+2 /* skip first one */)); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
-		if heroImageUrls, err = extractAttrStrings(doc, fmt.Sprintf("#%s > section.hero-comparison-section > div > div:nth-of-type(%d) img", id, i+2 /* skip first one */), "src"); err != nil {
+		if heroImageUrls, err = extractAttrStrings(doc, fmt.Sprintf(selectorTopHeroImages, id, i+2 /* skip first one */), "src"); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
-		if heroValues, err = extractStrings(doc, fmt.Sprintf("#%s > section.hero-comparison-section > div > div:nth-of-type(%d) div.bar-text > div.description", id, i+2 /* skip first one */)); err != nil {
+		if heroValues, err = extractStrings(doc, fmt.Sprintf(selectorTopHeroValues, id, i+2 /* skip first one */)); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
-		for i, _ := range heroNames {
+		if len(heroImageUrls) != len(heroNames) || len(heroValues) != len(heroNames) {
+			return featuredStats, topHeroes, careerStats, fmt.Errorf("number of elements mismatch in top heroes '%s': %d names, %d images, %d values", comparison, len(heroNames), len(heroImageUrls), len(heroValues))
+		}
+		for i := range heroNames {
 			heroes = append(heroes, Hero{
 				Name:     heroNames[i],
 				ImageUrl: heroImageUrls[i],
@@ -273,40 +479,43 @@ func extractPlayStat(doc *goquery.Document, id TagId) (featuredStats map[string]
 			})
 		}
 
-		topHeroes[comparison] = heroes
+		topHeroes = append(topHeroes, HeroComparison{Name: comparison, Heroes: heroes})
 	}
 	//
 	////////////////
 	// career stats
 	//
 	var statIds []string
-	if statIds, err = extractAttrStrings(doc, fmt.Sprintf("#%s div[data-group-id=\"stats\"]", id), "data-category-id"); err != nil {
+	if statIds, err = extractAttrStrings(doc, fmt.Sprintf(selectorCareerStatIds, id), "data-category-id"); err != nil {
 		return featuredStats, topHeroes, careerStats, err
 	}
 	for _, statId := range statIds {
 		var heroName string
-		if heroName, err = extractString(doc, fmt.Sprintf("#%s option[value=\"%s\"]", id, statId)); err != nil {
+		if heroName, err = extractString(doc, fmt.Sprintf(selectorCareerStatHeroName, id, statId)); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
 		var categoryNames []string
-		if categoryNames, err = extractStrings(doc, fmt.Sprintf("#%s div[data-category-id=\"%s\"] div.card-stat-block > table.data-table > thead > tr > th > .stat-title", id, statId)); err != nil {
+		if categoryNames, err = extractStrings(doc, fmt.Sprintf(selectorCareerStatCategoryNames, id, statId)); err != nil {
 			return featuredStats, topHeroes, careerStats, err
 		}
 
 		var categoryAttrs, categoryValues []string
 		careerStatCategories := []CareerStatCategory{}
 		for i, categoryName := range categoryNames {
-			if categoryAttrs, err = extractStrings(doc, fmt.Sprintf("#%s div[data-category-id=\"%s\"] > div:nth-child(%d) > div.card-stat-block > table.data-table > tbody > tr > td:nth-child(1)", id, statId, i+1)); err != nil {
+			if categoryAttrs, err = extractStrings(doc, fmt.Sprintf(selectorCareerStatCategoryAttrs, id, statId, i+1)); err != nil {
 				return featuredStats, topHeroes, careerStats, err
 			}
-			if categoryValues, err = extractStrings(doc, fmt.Sprintf("#%s div[data-category-id=\"%s\"] > div:nth-child(%d) > div.card-stat-block > table.data-table > tbody > tr > td:nth-child(2)", id, statId, i+1)); err != nil {
+			if categoryValues, err = extractStrings(doc, fmt.Sprintf(selectorCareerStatCategoryValues, id, statId, i+1)); err != nil {
 				return featuredStats, topHeroes, careerStats, err
 			}
 
 			// values for this category
-			values := map[string]string{}
-			for i, _ := range categoryAttrs {
-				values[categoryAttrs[i]] = categoryValues[i]
+			if len(categoryValues) != len(categoryAttrs) {
+				return featuredStats, topHeroes, careerStats, fmt.Errorf("number of elements mismatch in career stats '%s' of '%s': %d names, %d values", categoryName, heroName, len(categoryAttrs), len(categoryValues))
+			}
+			values := KeyValues{}
+			for i := range categoryAttrs {
+				values = append(values, KeyValue{Key: categoryAttrs[i], Value: categoryValues[i]})
 			}
 
 			// categories
@@ -358,6 +567,19 @@ func extractFloat32(doc *goquery.Document, selector string) (float32, error) {
 	}
 }
 
+// get html attribute for the first element with given selector, as a float32 value
+func extractFirstAttrFloat32(doc *goquery.Document, selector, attrName string) (float32, error) {
+	if s, err := extractFirstAttrString(doc, selector, attrName); err == nil {
+		if f, err := strconv.ParseFloat(s, 32); err == nil {
+			return float32(f), nil
+		} else {
+			return 0, err
+		}
+	} else {
+		return 0, err
+	}
+}
+
 func extractString(doc *goquery.Document, selector string) (string, error) {
 	var result string
 	exists := false
@@ -0,0 +1,246 @@
+package stat_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// regenerate golden files with: go test ./stat -update
+var update = flag.Bool("update", false, "update golden files in testdata/")
+
+// json output for a fetched stat, or an error
+func goldenJson(result stat.Stat, err error) ([]byte, error) {
+	var v interface{} = result
+	if err != nil && err != stat.ErrPrivateProfile {
+		v = map[string]string{"error": err.Error()}
+	}
+
+	bytes, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("failed to create testdata directory: %s", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file %s: %s", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %s", path, err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("output differs from golden file %s (run with -update to regenerate):\n%s", path, got)
+	}
+}
+
+func TestFetchStatGolden(t *testing.T) {
+	stattest.UseServer(t)
+
+	for _, fixture := range stattest.Fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			result, err := stat.FetchStat(fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region, fixture.Language)
+
+			got, err := goldenJson(result, err)
+			if err != nil {
+				t.Fatalf("failed to encode result: %s", err)
+			}
+			checkGolden(t, fixture.Name, got)
+		})
+	}
+}
+
+func TestParseStatMatchesFetchStat(t *testing.T) {
+	stattest.UseServer(t)
+
+	for _, fixture := range stattest.Fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			fetched, fetchErr := stat.FetchStat(fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region, fixture.Language)
+			parsed, parseErr := stat.ParseStat(bytes.NewReader(fixture.Html()), fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region)
+
+			fetchedJson, _ := goldenJson(fetched, fetchErr)
+			parsedJson, _ := goldenJson(parsed, parseErr)
+			if !bytes.Equal(fetchedJson, parsedJson) {
+				t.Errorf("ParseStat and FetchStat returned different results:\n%s\n%s", parsedJson, fetchedJson)
+			}
+		})
+	}
+}
+
+func TestFetchStatPrivate(t *testing.T) {
+	stattest.UseServer(t)
+
+	fixture := stattest.FixturePcPrivate
+	result, err := stat.FetchStat(fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region, fixture.Language)
+	if err != stat.ErrPrivateProfile {
+		t.Fatalf("expected ErrPrivateProfile, got: %v", err)
+	}
+	if !result.Private || result.Name == "" || result.Level <= 0 {
+		t.Errorf("public information should be returned for private profile: %+v", result)
+	}
+
+	fixture = stattest.FixturePcCompetitive
+	if result, err = stat.FetchStat(fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region, fixture.Language); err != nil {
+		t.Fatalf("failed to fetch stat: %s", err)
+	} else if result.Private {
+		t.Errorf("public profile should not be flagged as private")
+	}
+}
+
+func TestFetchStatNotFound(t *testing.T) {
+	stattest.UseServer(t)
+
+	if _, err := stat.FetchStat("nobody", 1, stat.PlatformPc, "us", "en-us"); err != stat.ErrPlayerNotFound {
+		t.Errorf("expected ErrPlayerNotFound for a non-existent profile, got: %v", err)
+	}
+}
+
+func TestParseBattleTag(t *testing.T) {
+	for _, test := range []struct {
+		battleTag, platform string
+		name                string
+		number              int
+		malformed           bool
+	}{
+		{"meinside#3155", stat.PlatformPc, "meinside", 3155, false},
+		{"meinside#3155", "PC", "meinside", 3155, false},
+		{"meinside", stat.PlatformPsn, "meinside", 0, false},
+		{"mein#side", stat.PlatformXbl, "mein#side", 0, false},
+		{"malformed", stat.PlatformPc, "", 0, true},
+		{"a#b", stat.PlatformPc, "", 0, true},
+		{"a#1#2", stat.PlatformPc, "", 0, true},
+	} {
+		name, number, err := stat.ParseBattleTag(test.battleTag, test.platform)
+		if (err != nil) != test.malformed || name != test.name || number != test.number {
+			t.Errorf("unexpected result for %s (%s): %s, %d, %v", test.battleTag, test.platform, name, number, err)
+		}
+	}
+}
+
+func TestDiagnoseLayout(t *testing.T) {
+	for _, fixture := range stattest.Fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			report, err := stat.DiagnoseLayoutFromReader(bytes.NewReader(fixture.Html()), fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region)
+			if err != nil {
+				t.Fatalf("failed to diagnose layout: %s", err)
+			}
+			if report.Drifted() {
+				t.Errorf("layout of fixture should not be reported as drifted: %+v", report)
+			}
+			if report.Private != (fixture == stattest.FixturePcPrivate) {
+				t.Errorf("private profile should be reported as private")
+			}
+
+			// selectors should be reported as they were checked, without formatting verbs
+			for _, check := range report.Checks {
+				if strings.Contains(check.Selector, "%") {
+					t.Errorf("selector of '%s' in %s was not formatted: %s", check.Name, check.Section, check.Selector)
+				}
+			}
+		})
+	}
+
+	report, err := stat.DiagnoseLayoutFromReader(bytes.NewReader(stattest.FixturePcCompetitive.Html()), "meinside", 3155, stat.PlatformPc, "kr")
+	if err != nil {
+		t.Fatalf("failed to diagnose layout: %s", err)
+	}
+	for _, check := range report.Checks {
+		if check.Section == "competitive" && check.Name == "top hero names" && check.Selector != `#competitive > section.hero-comparison-section > div > div:nth-of-type(2) div.bar-text > div.title` {
+			t.Errorf("unexpected selector of top hero names: %s", check.Selector)
+		}
+	}
+
+	if report, err := stat.DiagnoseLayoutFromReader(bytes.NewReader([]byte("<html></html>")), "nobody", 1, stat.PlatformPc, "us"); err != nil {
+		t.Errorf("failed to diagnose layout: %s", err)
+	} else if !report.Drifted() {
+		t.Errorf("layout of an empty page should be reported as drifted")
+	}
+}
+
+// trimmed sections of a career page, for seeding the fuzzer
+//
+// (whole pages are too large for the fuzzer to explore)
+const (
+	fuzzMasthead = `<div class="masthead"><div class="masthead-player"><img src="p.png" class="player-portrait"><h1 class="header-masthead">meinside</h1>` +
+		`<div class="endorsement-level"><svg class="EndorsementIcon-border--shotcaller" data-value="0.22"></svg><div class="u-center">4</div></div>` +
+		`<div class="player-level" style="background-image:url(l.png)"><div>89</div><div class="player-rank" style="background-image:url(r.png)"></div></div>` +
+		`<div class="competitive-rank"><img src="c.png"><div>3537</div></div></div><p class="masthead-detail"><span>Won 328 games</span></p></div>`
+	fuzzPrivate  = `<div class="masthead-permission-level-text">Private Profile</div>`
+	fuzzPlayStat = `<div id="%s"><section class="highlights-section"><ul><li><div class="card-content"><h3>18.50</h3><p>Eliminations - Average</p></div></li></ul></section>` +
+		`<section class="hero-comparison-section"><div><div><select data-group-id="comparisons"><option>Time Played</option></select></div>` +
+		`<div><div><img src="h.png"><div class="bar-text"><div class="title">Ana</div><div class="description">40 hours</div></div></div></div></div></section>` +
+		`<section><div><select data-group-id="stats"><option value="0x02E00000FFFFFFFF">ALL HEROES</option></select></div>` +
+		`<div data-group-id="stats" data-category-id="0x02E00000FFFFFFFF"><div><div class="card-stat-block"><table class="data-table"><thead><tr><th><span class="stat-title">Combat</span></th></tr></thead>` +
+		`<tbody><tr><td>Eliminations</td><td>247</td></tr></tbody></table></div></div></div></div></section></div>`
+	fuzzAchievements = `<section id="achievements-section"><div><div><select><option>General</option></select></div>` +
+		`<div><ul><li><div class="achievement-card m-disabled"><img src="a.png"></div><div class="tooltip-tip"><h6>Level 10</h6><p>Reach level 10.</p></div></li></ul></div></div></section>`
+)
+
+// malformed pages should result in errors, not panics
+//
+// fuzz with: go test ./stat -run FuzzParseStat -fuzz FuzzParseStat -fuzzminimizetime 1s
+// (minimizing interesting inputs takes most of the time otherwise)
+func FuzzParseStat(f *testing.F) {
+	page := `<html lang="en-us"><body>` + fuzzMasthead + fmt.Sprintf(fuzzPlayStat, "quickplay") + fmt.Sprintf(fuzzPlayStat, "competitive") + fuzzAchievements + `</body></html>`
+	if s, err := stat.ParseStat(strings.NewReader(page), "meinside", 3155, stat.PlatformPc, "kr"); err != nil || len(s.CompetitivePlay.CareerStats) == 0 || len(s.Achievements) == 0 {
+		f.Fatalf("trimmed page should be parsed: %+v, %v", s, err)
+	}
+
+	for _, seed := range []string{
+		page,
+		fuzzMasthead + fuzzPrivate,
+		fuzzMasthead + fmt.Sprintf(fuzzPlayStat, "quickplay"),
+		fmt.Sprintf(fuzzPlayStat, "competitive"),
+		fuzzAchievements,
+		`<div id="achievements-section"><div><div></div><div><ul><div class="achievement-card"><img src="a.png"></div><div class="achievement-card"></div></ul></div></div><select><option>General</option></select></div>`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, html []byte) {
+		stat.ParseStat(bytes.NewReader(html), "meinside", 3155, stat.PlatformPc, "kr")
+		stat.DiagnoseLayoutFromReader(bytes.NewReader(html), "meinside", 3155, stat.PlatformPc, "kr")
+	})
+}
+
+func TestParseStatMismatchedElements(t *testing.T) {
+	html := string(stattest.FixturePcCompetitive.Html())
+
+	for name, broken := range map[string]string{
+		// a top hero without its image
+		"top heroes": strings.Replace(html, `<img src="https://d1u1mce87gyfbn.cloudfront.net/game/heroes/small/`, `<img data-src="`, 1),
+		// an achievement without its description
+		"achievements": strings.Replace(html, `<p class="h6">`, `<span class="h6">`, 1),
+		// a career stat without its value
+		"career stats": strings.Replace(html, `<tr class="data-table-row"><td>Eliminations</td><td>`, `<tr class="data-table-row"><td>Eliminations</td><th>`, 1),
+	} {
+		if broken == html {
+			t.Fatalf("failed to break fixture for %s", name)
+		}
+		if _, err := stat.ParseStat(strings.NewReader(broken), "meinside", 3155, stat.PlatformPc, "kr"); err == nil {
+			t.Errorf("expected an error for mismatched elements in %s", name)
+		} else if _, ok := err.(stat.ParseError); !ok {
+			t.Errorf("expected a parse error for mismatched elements in %s, got %T", name, err)
+		}
+	}
+}
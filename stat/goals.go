@@ -0,0 +1,326 @@
+package stat
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// metrics of goals
+const (
+	GoalCompetitiveRank  = "competitive_rank"
+	GoalLevel            = "level"
+	GoalEndorsementLevel = "endorsement_level"
+	GoalGamesPlayed      = "games_played"
+	GoalGamesWon         = "games_won"
+	GoalWinRate          = "win_rate"    // percentage of games won (0 ~ 100)
+	GoalTimePlayed       = "time_played" // in hours
+)
+
+// modes of goals (empty for both quick and competitive play)
+const (
+	GoalModeQuickPlay       = "quick_play"
+	GoalModeCompetitivePlay = "competitive_play"
+)
+
+const (
+	DefaultGoalTrendWindow = 14 * 24 * time.Hour // trends of goals are taken from snapshots in this duration
+
+	GoalEtaHorizon = 3 * 365 * 24 * time.Hour // ETAs of goals further than this are not estimated
+)
+
+// a goal of a player, reached when the value of its metric is equal to or greater than its target
+//
+// eg. {"name": "100 games on Ana", "metric": "games_played", "hero": "ana", "target": 100}
+type Goal struct {
+	Name   string  `json:"name,omitempty"` // eg. "reach 3000 SR" (default: generated from other values)
+	Metric string  `json:"metric"`         // GoalCompetitiveRank, GoalGamesPlayed, ...
+	Hero   string  `json:"hero,omitempty"` // id or name (in any language) of a hero, for games, win rate, and time played
+	Role   string  `json:"role,omitempty"` // role of heroes, for games, win rate, and time played
+	Mode   string  `json:"mode,omitempty"` // GoalModeQuickPlay, GoalModeCompetitivePlay, or empty for both
+	Target float64 `json:"target"`
+}
+
+func (g Goal) String() string {
+	if g.Name != "" {
+		return g.Name
+	}
+
+	subject := strings.Replace(g.Metric, "_", " ", -1)
+	if g.Hero != "" {
+		subject += " of " + g.Hero
+	} else if g.Role != "" {
+		subject += " of " + g.Role + " heroes"
+	}
+	if g.Mode != "" {
+		subject += " in " + strings.Replace(g.Mode, "_", " ", -1)
+	}
+	return fmt.Sprintf("%s >= %s", subject, formatChartValue(g.Target))
+}
+
+// check if the goal is well-defined
+func (g Goal) Validate() error {
+	switch g.Metric {
+	case GoalCompetitiveRank, GoalLevel, GoalEndorsementLevel:
+		if g.Hero != "" || g.Role != "" || g.Mode != "" {
+			return fmt.Errorf("hero, role, and mode are not applicable to %s (goal '%s')", g.Metric, g)
+		}
+	case GoalGamesPlayed, GoalGamesWon, GoalWinRate, GoalTimePlayed:
+		if g.Hero != "" && g.Role != "" {
+			return fmt.Errorf("hero and role cannot be given together (goal '%s')", g)
+		}
+		if _, exists := g.heroInfo(); g.Hero != "" && !exists {
+			return fmt.Errorf("no such hero: %s (goal '%s')", g.Hero, g)
+		}
+		if g.Role != "" && !containsLabel(HeroRoles, g.Role) {
+			return fmt.Errorf("unknown role: %s (goal '%s')", g.Role, g)
+		}
+		if g.Mode != "" && g.Mode != GoalModeQuickPlay && g.Mode != GoalModeCompetitivePlay {
+			return fmt.Errorf("unknown mode: %s (goal '%s')", g.Mode, g)
+		}
+	default:
+		return fmt.Errorf("unknown metric: %s (goal '%s')", g.Metric, g)
+	}
+	if g.Target <= 0 {
+		return fmt.Errorf("target should be positive (goal '%s')", g)
+	}
+	return nil
+}
+
+// value of the goal's metric in given stat (false when it is not available, eg. unranked or private)
+func (g Goal) Value(s Stat) (float64, bool) {
+	switch g.Metric {
+	case GoalCompetitiveRank:
+		return float64(s.CompetitiveRank), s.CompetitiveRank > 0
+	case GoalLevel:
+		return float64(s.Level), s.Level > 0
+	case GoalEndorsementLevel:
+		return float64(s.EndorsementLevel), s.EndorsementLevel > 0
+	}
+	if s.Private {
+		return 0, false
+	}
+
+	playStats := []PlayStat{s.QuickPlay, s.CompetitivePlay}
+	switch g.Mode {
+	case GoalModeQuickPlay:
+		playStats = playStats[:1]
+	case GoalModeCompetitivePlay:
+		playStats = playStats[1:]
+	}
+
+	found := false
+	duration := time.Duration(0)
+	won, wonOfPlayed, played := 0.0, 0.0, 0.0 // (win rate only with games of known numbers of played games)
+	for _, playStat := range playStats {
+		for _, r := range g.recordsOf(playStat) {
+			found = true
+			duration += r.duration
+			won += r.won
+			if r.played > 0 {
+				wonOfPlayed += r.won
+				played += r.played
+			}
+		}
+	}
+
+	switch g.Metric {
+	case GoalGamesPlayed:
+		return played, played > 0
+	case GoalGamesWon:
+		return won, found
+	case GoalWinRate:
+		if played > 0 {
+			return wonOfPlayed / played * 100, true
+		}
+		return 0, false
+	case GoalTimePlayed:
+		return duration.Hours(), found
+	}
+	return 0, false
+}
+
+// check if the goal is reached in given stat
+func (g Goal) Reached(s Stat) bool {
+	value, exists := g.Value(s)
+	return exists && value >= g.Target
+}
+
+// metadata of the goal's hero
+func (g Goal) heroInfo() (HeroInfo, bool) {
+	if h, exists := HeroById(g.Hero); exists {
+		return h, true
+	}
+	return HeroByName(g.Hero)
+}
+
+// records of heroes for the goal in given play stat
+//
+// (of all heroes when the goal has no hero nor role: the all heroes block, or known heroes without it)
+func (g Goal) recordsOf(playStat PlayStat) []*heroRecord {
+	if g.Hero == "" && g.Role == "" {
+		for _, careerStat := range playStat.CareerStats {
+			if IsAllHeroes(careerStat.HeroName) {
+				r := &heroRecord{name: careerStat.HeroName}
+				r.readCareerStat(careerStat)
+				return []*heroRecord{r}
+			}
+		}
+	}
+
+	hero, _ := g.heroInfo()
+	records := []*heroRecord{}
+	for _, r := range heroRecordsOf(playStat) {
+		switch {
+		case g.Hero != "":
+			if !(r.known && r.info.Id == hero.Id) && !strings.EqualFold(r.name, g.Hero) {
+				continue
+			}
+		case g.Role != "":
+			if !r.known || r.info.Role != g.Role {
+				continue
+			}
+		default:
+			if !r.known {
+				continue
+			}
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// progress of a goal
+type GoalProgress struct {
+	Goal      Goal       `json:"goal"`
+	Available bool       `json:"available"` // false when the value is not available (eg. unranked, or private profiles)
+	Current   float64    `json:"current"`
+	Baseline  float64    `json:"baseline"` // value in the first snapshot of history (or 0 without history)
+	Progress  float64    `json:"progress"` // percentage from the baseline to the target (0 ~ 100)
+	Reached   bool       `json:"reached"`
+	ReachedAt *time.Time `json:"reached_at,omitempty"` // since when the goal has been reached (only with history)
+	Trend     float64    `json:"trend"`                // change of the value per day in recent snapshots (0 when unknown)
+	Eta       *time.Time `json:"eta,omitempty"`        // when the goal will be reached with the trend
+}
+
+// a value of a goal at a time
+type goalPoint struct {
+	time  time.Time
+	value float64
+}
+
+// evaluate given goals against the current snapshot, with trends of recent snapshots in given window
+//
+// (history can be nil, and window will be DefaultGoalTrendWindow when it is 0)
+func EvaluateGoals(goals []Goal, current Snapshot, history []Snapshot, window time.Duration) []GoalProgress {
+	if window <= 0 {
+		window = DefaultGoalTrendWindow
+	}
+	sorted := []Snapshot{}
+	for _, snapshot := range sortedSnapshots(history) {
+		if snapshot.Time.Before(current.Time) {
+			sorted = append(sorted, snapshot)
+		}
+	}
+	sorted = append(sorted, current)
+
+	progresses := []GoalProgress{}
+	for _, goal := range goals {
+		progress := GoalProgress{Goal: goal}
+
+		points := []goalPoint{}
+		for _, snapshot := range sorted {
+			if value, exists := goal.Value(snapshot.Stat); exists {
+				points = append(points, goalPoint{time: snapshot.Time, value: value})
+			}
+		}
+		progress.Current, progress.Available = goal.Value(current.Stat)
+		if !progress.Available {
+			progresses = append(progresses, progress)
+			continue
+		}
+
+		// progress from the baseline
+		progress.Reached = progress.Current >= goal.Target
+		if len(points) > 1 && points[0].value < goal.Target {
+			progress.Baseline = points[0].value
+		}
+		progress.Progress = (progress.Current - progress.Baseline) / (goal.Target - progress.Baseline) * 100
+		progress.Progress = math.Max(0, math.Min(100, progress.Progress))
+
+		// since when it has been reached
+		if progress.Reached && len(points) > 1 {
+			since := points[len(points)-1].time
+			for i := len(points) - 2; i >= 0 && points[i].value >= goal.Target; i-- {
+				since = points[i].time
+			}
+			progress.ReachedAt = &since
+		}
+
+		// trend, and the estimated time to reach
+		recent := []goalPoint{}
+		for _, p := range points {
+			if !p.time.Before(current.Time.Add(-window)) {
+				recent = append(recent, p)
+			}
+		}
+		if trend, exists := goalTrend(recent); exists {
+			progress.Trend = trend
+			if !progress.Reached && trend > 0 {
+				if eta := time.Duration((goal.Target - progress.Current) / trend * float64(24*time.Hour)); eta <= GoalEtaHorizon {
+					at := current.Time.Add(eta)
+					progress.Eta = &at
+				}
+			}
+		}
+
+		progresses = append(progresses, progress)
+	}
+	return progresses
+}
+
+// change of values per day, with linear regression of given points
+func goalTrend(points []goalPoint) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+
+	n := float64(len(points))
+	sumX, sumY, sumXX, sumXY := 0.0, 0.0, 0.0, 0.0
+	for _, p := range points {
+		x := p.time.Sub(points[0].time).Hours() / 24 // in days
+		sumX += x
+		sumY += p.value
+		sumXX += x * x
+		sumXY += x * p.value
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 { // all at the same time
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator, true
+}
+
+// an event of a reached goal
+type GoalEvent struct {
+	Time  time.Time `json:"time"`
+	Goal  Goal      `json:"goal"`
+	Value float64   `json:"value"`
+}
+
+func (e GoalEvent) String() string {
+	return fmt.Sprintf("Goal reached: %s (%s)", e.Goal, formatChartValue(e.Value))
+}
+
+// events of goals which are reached in the new snapshot, but were not in the old one
+func GoalEvents(goals []Goal, old, new Snapshot) []GoalEvent {
+	events := []GoalEvent{}
+	for _, goal := range goals {
+		if goal.Reached(new.Stat) && !goal.Reached(old.Stat) {
+			value, _ := goal.Value(new.Stat)
+			events = append(events, GoalEvent{Time: new.Time, Goal: goal, Value: value})
+		}
+	}
+	return events
+}
@@ -0,0 +1,117 @@
+package stat_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestGoalValue(t *testing.T) {
+	s := stattest.FixturePcCompetitive.Stat(t)
+
+	for _, test := range []struct {
+		goal     stat.Goal
+		expected float64
+	}{
+		{stat.Goal{Metric: stat.GoalCompetitiveRank, Target: 3000}, 3537},
+		{stat.Goal{Metric: stat.GoalGamesWon, Target: 100}, 93},
+		{stat.Goal{Metric: stat.GoalTimePlayed, Hero: "Ana", Target: 200}, 109.58},
+		{stat.Goal{Metric: stat.GoalWinRate, Role: stat.RoleSupport, Mode: stat.GoalModeCompetitivePlay, Target: 55}, 357.0 / 483 * 100},
+	} {
+		if err := test.goal.Validate(); err != nil {
+			t.Errorf("goal '%s' should be valid: %s", test.goal, err)
+		}
+		if value, exists := test.goal.Value(s); !exists || math.Abs(value-test.expected) > 0.01 {
+			t.Errorf("expected %.2f for goal '%s', got %.2f", test.expected, test.goal, value)
+		}
+	}
+
+	// localized hero names
+	korean := stattest.FixturePcCompetitiveKorean.Stat(t)
+	if value, exists := (stat.Goal{Metric: stat.GoalGamesPlayed, Hero: "ana"}).Value(korean); !exists || value != 647 {
+		t.Errorf("expected 647 games of Ana in korean, got %.0f", value)
+	}
+
+	// all heroes in french, with or without the all heroes block (unknown heroes are not counted)
+	careerStat := func(hero, won string) stat.CareerStat {
+		return stat.CareerStat{HeroName: hero, Categories: []stat.CareerStatCategory{{Name: "Partie", Values: stat.KeyValues{{Key: "Parties gagnées", Value: won}}}}}
+	}
+	french := stat.Stat{Language: "fr-fr", QuickPlay: stat.PlayStat{CareerStats: []stat.CareerStat{
+		careerStat("TOUS LES HÉROS", "10"),
+		careerStat("Faucheur", "4"),
+		careerStat("Ange", "6"),
+		careerStat("Personne", "3"),
+	}}}
+	if value, exists := (stat.Goal{Metric: stat.GoalGamesWon}).Value(french); !exists || value != 10 {
+		t.Errorf("expected 10 games won of all heroes in french, got %.0f", value)
+	}
+	french.QuickPlay.CareerStats = french.QuickPlay.CareerStats[1:]
+	if value, exists := (stat.Goal{Metric: stat.GoalGamesWon}).Value(french); !exists || value != 10 {
+		t.Errorf("expected 10 games won of known heroes in french, got %.0f", value)
+	}
+
+	// not available
+	if _, exists := (stat.Goal{Metric: stat.GoalCompetitiveRank}).Value(stattest.FixturePcNoCompetitive.Stat(t)); exists {
+		t.Errorf("competitive rank of an unranked player should not be available")
+	}
+	if _, exists := (stat.Goal{Metric: stat.GoalGamesWon}).Value(stat.Stat{Private: true}); exists {
+		t.Errorf("games of a private profile should not be available")
+	}
+
+	// malformed goals
+	for _, goal := range []stat.Goal{
+		{Metric: "kills", Target: 1},
+		{Metric: stat.GoalLevel, Hero: "ana", Target: 1},
+		{Metric: stat.GoalGamesPlayed, Hero: "Nobody", Target: 1},
+		{Metric: stat.GoalGamesPlayed, Hero: "ana", Role: stat.RoleSupport, Target: 1},
+		{Metric: stat.GoalWinRate, Mode: "arcade", Target: 1},
+		{Metric: stat.GoalWinRate},
+	} {
+		if goal.Validate() == nil {
+			t.Errorf("goal %+v should be invalid", goal)
+		}
+	}
+}
+
+func TestEvaluateGoals(t *testing.T) {
+	base := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := func(days int, rank int32) stat.Snapshot {
+		return stat.Snapshot{Time: base.AddDate(0, 0, days), Stat: stat.Stat{CompetitiveRank: rank}}
+	}
+	history := []stat.Snapshot{snapshot(10, 3200), snapshot(0, 3000), snapshot(5, 3100)}
+	current := snapshot(15, 3300)
+
+	goals := []stat.Goal{
+		{Name: "reach 3500 SR", Metric: stat.GoalCompetitiveRank, Target: 3500},
+		{Metric: stat.GoalCompetitiveRank, Target: 3100},
+		{Metric: stat.GoalGamesWon, Target: 100},
+	}
+	progresses := stat.EvaluateGoals(goals, current, history, 0)
+	if len(progresses) != len(goals) {
+		t.Fatalf("expected %d progresses, got %d", len(goals), len(progresses))
+	}
+
+	// in progress: trend from snapshots in the recent 14 days (3100, 3200, and 3300)
+	if p := progresses[0]; !p.Available || p.Reached || p.Baseline != 3000 || p.Progress != 60 || math.Abs(p.Trend-20) > 0.001 || p.Eta == nil || !p.Eta.Equal(base.AddDate(0, 0, 25)) {
+		t.Errorf("unexpected progress of goal '%s': %+v", p.Goal, p)
+	}
+
+	// reached since the second snapshot
+	if p := progresses[1]; !p.Reached || p.Progress != 100 || p.ReachedAt == nil || !p.ReachedAt.Equal(base.AddDate(0, 0, 5)) || p.Eta != nil {
+		t.Errorf("unexpected progress of goal '%s': %+v", p.Goal, p)
+	}
+
+	// not available
+	if p := progresses[2]; p.Available || p.Progress != 0 {
+		t.Errorf("unexpected progress of goal '%s': %+v", p.Goal, p)
+	}
+
+	// events of reached goals
+	events := stat.GoalEvents(goals, snapshot(20, 3450), snapshot(21, 3512))
+	if len(events) != 1 || events[0].Goal.Name != "reach 3500 SR" || events[0].Value != 3512 || events[0].String() != "Goal reached: reach 3500 SR (3512)" {
+		t.Errorf("unexpected goal events: %+v", events)
+	}
+}
@@ -0,0 +1,246 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+// default size of hero banners
+const (
+	HeroBannerWidth  = 480
+	HeroBannerHeight = 160
+
+	maxHeroBannerBestStats = 3
+)
+
+// labels of top heroes' comparisons and career stat categories (in all supported languages)
+var (
+	labelsTimePlayed          = labelsOf("Time Played")
+	labelsWinPercentage       = labelsOf("Win Percentage")
+	labelsEliminationsPerLife = labelsOf("Eliminations per Life")
+	labelsBest                = labelsOf("Best")
+)
+
+// options for hero banners
+type HeroBannerOptions struct {
+	Width  int // when 0, HeroBannerWidth will be used
+	Height int // when 0, HeroBannerHeight will be used
+
+	BackgroundColor color.RGBA // when fully transparent, DefaultBannerSpec's will be used
+	TextColor       color.RGBA // when fully transparent, DefaultBannerSpec's will be used
+
+	Competitive bool // use stats of competitive play, instead of quick play
+
+	Font *truetype.Font // when nil, it will be loaded from KoverwatchFontUrl
+}
+
+// a labeled value on hero banners
+type heroBannerStat struct {
+	Label string
+	Value string
+}
+
+// stats of a hero for hero banners
+type heroSpotlight struct {
+	Name     string
+	ImageUrl string
+
+	TimePlayed          heroBannerStat
+	WinPercentage       heroBannerStat
+	EliminationsPerLife heroBannerStat
+	Best                []heroBannerStat
+}
+
+// render a card of given hero, in .png format
+//
+// it shows the hero's portrait, time played, win percentage, eliminations per life, and best stats
+// (heroName should be in the language of the fetched career page, eg. "Ana" or "아나")
+func RenderHeroBanner(stat Stat, heroName string, opts HeroBannerOptions) ([]byte, error) {
+	if image, err := genHeroBanner(stat, heroName, opts); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := png.Encode(imgBytes, image); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render a card of given hero to a file in .png format
+func RenderHeroBannerToPngFile(stat Stat, heroName string, opts HeroBannerOptions, outFilepath string) error {
+	if bytes, err := RenderHeroBanner(stat, heroName, opts); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// generate a hero banner image
+func genHeroBanner(stat Stat, heroName string, opts HeroBannerOptions) (result *image.RGBA, err error) {
+	var spotlight heroSpotlight
+	if spotlight, err = findHeroSpotlight(stat, heroName, opts.Competitive); err != nil {
+		return nil, err
+	}
+
+	// default options
+	if opts.Width <= 0 {
+		opts.Width = HeroBannerWidth
+	}
+	if opts.Height <= 0 {
+		opts.Height = HeroBannerHeight
+	}
+	if opts.BackgroundColor.A == 0 {
+		opts.BackgroundColor = DefaultBannerSpec.BackgroundColor
+	}
+	if opts.TextColor.A == 0 {
+		opts.TextColor = DefaultBannerSpec.TextColor
+	}
+	if opts.Font == nil {
+		if opts.Font, err = getFont(KoverwatchFontUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	banner := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+
+	// fill background color
+	draw.Draw(
+		banner,
+		banner.Bounds(),
+		&image.Uniform{opts.BackgroundColor},
+		image.ZP,
+		draw.Src,
+	)
+
+	// hero portrait on the left
+	size := opts.Height - Margin*2
+	portrait := image.Rect(Margin, Margin, Margin+size, Margin+size)
+
+	var heroImage image.Image
+	if heroImage, err = getImage(spotlight.ImageUrl); err == nil {
+		drawResized(banner, portrait, heroImage)
+	} else {
+		return nil, err
+	}
+
+	// setup context
+	context := freetype.NewContext()
+	context.SetFont(opts.Font)
+	context.SetDPI(72)
+	context.SetDst(banner)
+	context.SetSrc(&image.Uniform{opts.TextColor})
+
+	// battletag and hero name on the top, stats below them
+	area := image.Rect(portrait.Max.X+Margin*2, Margin, opts.Width-Margin, opts.Height-Margin)
+	header := image.Rect(area.Min.X, area.Min.Y, area.Max.X, area.Min.Y+area.Dy()*3/10)
+	if err = drawCaption(context, header, stat.BattleTag, spotlight.Name); err != nil {
+		return nil, err
+	}
+
+	stats := append([]heroBannerStat{
+		spotlight.TimePlayed,
+		spotlight.WinPercentage,
+		spotlight.EliminationsPerLife,
+	}, spotlight.Best...)
+	cells := splitArea(image.Rect(area.Min.X, header.Max.Y+Margin, area.Max.X, area.Max.Y), 3, 2)
+	for i, s := range stats {
+		if i >= len(cells) {
+			break
+		}
+
+		if err = drawCaption(context, cells[i], s.Label, s.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return banner, nil
+}
+
+// find stats of given hero from top heroes and career stats
+func findHeroSpotlight(stat Stat, heroName string, competitive bool) (spotlight heroSpotlight, err error) {
+	playStat := stat.QuickPlay
+	if competitive {
+		playStat = stat.CompetitivePlay
+	}
+
+	// name and portrait from top heroes
+	for _, comparison := range playStat.TopHeroes {
+		for _, hero := range comparison.Heroes {
+			if strings.EqualFold(hero.Name, heroName) {
+				spotlight.Name = hero.Name
+				spotlight.ImageUrl = hero.ImageUrl
+				break
+			}
+		}
+		if spotlight.ImageUrl != "" {
+			break
+		}
+	}
+	if spotlight.ImageUrl == "" {
+		return heroSpotlight{}, fmt.Errorf("no such hero in top heroes: %s", heroName)
+	}
+
+	spotlight.TimePlayed = topHeroValue(playStat, stat.Language, spotlight.Name, labelsTimePlayed)
+	spotlight.WinPercentage = topHeroValue(playStat, stat.Language, spotlight.Name, labelsWinPercentage)
+	spotlight.EliminationsPerLife = topHeroValue(playStat, stat.Language, spotlight.Name, labelsEliminationsPerLife)
+
+	// best stats from career stats
+	for _, careerStat := range playStat.CareerStats {
+		if !strings.EqualFold(careerStat.HeroName, spotlight.Name) {
+			continue
+		}
+
+		for _, category := range careerStat.Categories {
+			if !containsLabel(labelsBest, category.Name) {
+				continue
+			}
+
+			for _, value := range category.Values {
+				if len(spotlight.Best) >= maxHeroBannerBestStats {
+					break
+				}
+				spotlight.Best = append(spotlight.Best, heroBannerStat{Label: value.Key, Value: value.Value})
+			}
+		}
+	}
+
+	return spotlight, nil
+}
+
+// find value of given hero from the top heroes' comparison with one of given labels
+//
+// (when not found, the first label localized to given language and NoValue will be returned)
+func topHeroValue(playStat PlayStat, language, heroName string, labels []string) heroBannerStat {
+	for _, comparison := range playStat.TopHeroes {
+		if !containsLabel(labels, comparison.Name) {
+			continue
+		}
+
+		for _, hero := range comparison.Heroes {
+			if hero.Name == heroName {
+				return heroBannerStat{Label: comparison.Name, Value: hero.Value}
+			}
+		}
+	}
+	return heroBannerStat{Label: Localize(language, labels[0]), Value: NoValue}
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
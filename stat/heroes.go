@@ -0,0 +1,397 @@
+package stat
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// roles of heroes (and roster members: RoleFlex is for members who play any role)
+const (
+	RoleTank    = "tank"
+	RoleDamage  = "damage"
+	RoleSupport = "support"
+	RoleFlex    = "flex"
+)
+
+// roles of heroes, in order
+var HeroRoles = []string{RoleTank, RoleDamage, RoleSupport}
+
+const (
+	// url of hero images on the site (with game id of the hero)
+	HeroImageUrlFormat = "https://d1u1mce87gyfbn.cloudfront.net/game/heroes/small/%s.png"
+
+	// when the most played role has less share of time played than this, the primary role will be RoleFlex
+	PrimaryRoleMinShare = 0.4
+)
+
+// metadata of a hero
+type HeroInfo struct {
+	Id       string            `json:"id"`       // canonical id, eg. "soldier-76"
+	GameId   string            `json:"game_id"`  // id in the game (and in urls of hero images), eg. "0x02E000000000006E"
+	Role     string            `json:"role"`     // RoleTank, RoleDamage, or RoleSupport
+	Name     string            `json:"name"`     // english name, as on the site
+	Names    map[string]string `json:"names"`    // localized names by language (for all SupportedLanguages)
+	Released string            `json:"released"` // version of the game which released the hero, eg. "1.0"
+}
+
+// localized names of a hero in languages not written in latin alphabets (ko-kr, ja-jp, ru-ru, and zh-tw)
+//
+// (names in other languages are filled in init, see latinHeroNames)
+func heroNames(ko, ja, ru, zh string) map[string]string {
+	return map[string]string{"ko-kr": ko, "ja-jp": ja, "ru-ru": ru, "zh-tw": zh}
+}
+
+// all heroes, in the order of release
+//
+// (add new ones here, so they can be classified by their roles)
+var Heroes = []HeroInfo{
+	{Id: "bastion", GameId: "0x02E0000000000015", Role: RoleDamage, Name: "Bastion", Names: heroNames("바스티온", "バスティオン", "Бастион", "堡壘"), Released: "1.0"},
+	{Id: "dva", GameId: "0x02E000000000007A", Role: RoleTank, Name: "D.Va", Names: heroNames("D.Va", "D.Va", "D.Va", "D.Va"), Released: "1.0"},
+	{Id: "genji", GameId: "0x02E0000000000029", Role: RoleDamage, Name: "Genji", Names: heroNames("겐지", "ゲンジ", "Гэндзи", "源氏"), Released: "1.0"},
+	{Id: "hanzo", GameId: "0x02E0000000000005", Role: RoleDamage, Name: "Hanzo", Names: heroNames("한조", "ハンゾー", "Хандзо", "半藏"), Released: "1.0"},
+	{Id: "junkrat", GameId: "0x02E0000000000065", Role: RoleDamage, Name: "Junkrat", Names: heroNames("정크랫", "ジャンクラット", "Крысавчик", "炸彈鼠"), Released: "1.0"},
+	{Id: "lucio", GameId: "0x02E0000000000079", Role: RoleSupport, Name: "Lúcio", Names: heroNames("루시우", "ルシオ", "Лусио", "路西歐"), Released: "1.0"},
+	{Id: "mccree", GameId: "0x02E0000000000042", Role: RoleDamage, Name: "McCree", Names: heroNames("맥크리", "マクリー", "Маккри", "麥卡利"), Released: "1.0"},
+	{Id: "mei", GameId: "0x02E00000000000DD", Role: RoleDamage, Name: "Mei", Names: heroNames("메이", "メイ", "Мэй", "小美"), Released: "1.0"},
+	{Id: "mercy", GameId: "0x02E0000000000004", Role: RoleSupport, Name: "Mercy", Names: heroNames("메르시", "マーシー", "Ангел", "慈悲"), Released: "1.0"},
+	{Id: "pharah", GameId: "0x02E0000000000008", Role: RoleDamage, Name: "Pharah", Names: heroNames("파라", "ファラ", "Фарра", "法老之鷹"), Released: "1.0"},
+	{Id: "reaper", GameId: "0x02E0000000000002", Role: RoleDamage, Name: "Reaper", Names: heroNames("리퍼", "リーパー", "Жнец", "死神"), Released: "1.0"},
+	{Id: "reinhardt", GameId: "0x02E0000000000007", Role: RoleTank, Name: "Reinhardt", Names: heroNames("라인하르트", "ラインハルト", "Райнхардт", "萊因哈特"), Released: "1.0"},
+	{Id: "roadhog", GameId: "0x02E0000000000040", Role: RoleTank, Name: "Roadhog", Names: heroNames("로드호그", "ロードホッグ", "Турбосвин", "路霸"), Released: "1.0"},
+	{Id: "soldier-76", GameId: "0x02E000000000006E", Role: RoleDamage, Name: "Soldier: 76", Names: heroNames("솔저: 76", "ソルジャー76", "Солдат-76", "士兵：76"), Released: "1.0"},
+	{Id: "symmetra", GameId: "0x02E0000000000016", Role: RoleSupport, Name: "Symmetra", Names: heroNames("시메트라", "シンメトラ", "Симметра", "秩序之光"), Released: "1.0"},
+	{Id: "torbjorn", GameId: "0x02E0000000000006", Role: RoleDamage, Name: "Torbjörn", Names: heroNames("토르비욘", "トールビョーン", "Торбьорн", "托比昂"), Released: "1.0"},
+	{Id: "tracer", GameId: "0x02E0000000000003", Role: RoleDamage, Name: "Tracer", Names: heroNames("트레이서", "トレーサー", "Трейсер", "閃光"), Released: "1.0"},
+	{Id: "widowmaker", GameId: "0x02E000000000000A", Role: RoleDamage, Name: "Widowmaker", Names: heroNames("위도우메이커", "ウィドウメイカー", "Роковая вдова", "狙擊鷹"), Released: "1.0"},
+	{Id: "winston", GameId: "0x02E0000000000009", Role: RoleTank, Name: "Winston", Names: heroNames("윈스턴", "ウィンストン", "Уинстон", "溫斯頓"), Released: "1.0"},
+	{Id: "zarya", GameId: "0x02E0000000000068", Role: RoleTank, Name: "Zarya", Names: heroNames("자리야", "ザリア", "Заря", "札莉雅"), Released: "1.0"},
+	{Id: "zenyatta", GameId: "0x02E0000000000020", Role: RoleSupport, Name: "Zenyatta", Names: heroNames("젠야타", "ゼニヤッタ", "Дзенъятта", "禪亞塔"), Released: "1.0"},
+	{Id: "ana", GameId: "0x02E0000000000013", Role: RoleSupport, Name: "Ana", Names: heroNames("아나", "アナ", "Ана", "安娜"), Released: "1.1"},
+	{Id: "sombra", GameId: "0x02E000000000012E", Role: RoleDamage, Name: "Sombra", Names: heroNames("솜브라", "ソンブラ", "Сомбра", "駭影"), Released: "1.5"},
+	{Id: "orisa", GameId: "0x02E000000000013E", Role: RoleTank, Name: "Orisa", Names: heroNames("오리사", "オリーサ", "Орисса", "歐瑞莎"), Released: "1.8"},
+	{Id: "doomfist", GameId: "0x02E000000000012F", Role: RoleDamage, Name: "Doomfist", Names: heroNames("둠피스트", "ドゥームフィスト", "Кулак Смерти", "毀滅拳王"), Released: "1.14"},
+	{Id: "moira", GameId: "0x02E00000000001A2", Role: RoleSupport, Name: "Moira", Names: heroNames("모이라", "モイラ", "Мойра", "莫伊拉"), Released: "1.17"},
+	{Id: "brigitte", GameId: "0x02E0000000000195", Role: RoleSupport, Name: "Brigitte", Names: heroNames("브리기테", "ブリギッテ", "Бригитта", "布麗姬"), Released: "1.22"},
+	{Id: "wrecking-ball", GameId: "0x02E00000000001CA", Role: RoleTank, Name: "Wrecking Ball", Names: heroNames("레킹볼", "レッキング・ボール", "Таран", "破壞球"), Released: "1.25"},
+	{Id: "ashe", GameId: "0x02E0000000000200", Role: RoleDamage, Name: "Ashe", Names: heroNames("애쉬", "アッシュ", "Эш", "艾西"), Released: "1.30"},
+	{Id: "baptiste", GameId: "0x02E0000000000221", Role: RoleSupport, Name: "Baptiste", Names: heroNames("바티스트", "バティスト", "Батист", "巴蒂斯特"), Released: "1.35"},
+	{Id: "sigma", GameId: "0x02E000000000023B", Role: RoleTank, Name: "Sigma", Names: heroNames("시그마", "シグマ", "Сигма", "西格馬"), Released: "1.40"},
+	{Id: "echo", GameId: "0x02E0000000000206", Role: RoleDamage, Name: "Echo", Names: heroNames("에코", "エコー", "Эхо", "回音"), Released: "1.48"},
+}
+
+// names of heroes which differ from english ones in languages written in latin alphabets, by ids and languages
+var latinHeroNames = map[string]map[string]string{
+	"junkrat":       {"fr-fr": "Chacal", "pl-pl": "Złomiarz"},
+	"mercy":         {"fr-fr": "Ange"},
+	"reaper":        {"fr-fr": "Faucheur"},
+	"roadhog":       {"fr-fr": "Chopper"},
+	"soldier-76":    {"es-es": "Soldado: 76", "es-mx": "Soldado: 76", "fr-fr": "Soldat : 76", "it-it": "Soldato: 76", "pl-pl": "Żołnierz-76", "pt-br": "Soldado: 76"},
+	"widowmaker":    {"fr-fr": "Fatale"},
+	"wrecking-ball": {"fr-fr": "Bouldozer"},
+}
+
+// fill names of heroes in other supported languages (with latinHeroNames, or english names)
+func init() {
+	for _, h := range Heroes {
+		for _, language := range SupportedLanguages {
+			if _, exists := h.Names[language]; exists {
+				continue
+			}
+			if name, exists := latinHeroNames[h.Id][language]; exists {
+				h.Names[language] = name
+			} else {
+				h.Names[language] = h.Name
+			}
+		}
+	}
+}
+
+// name of the hero in given language (english one when not localized)
+func (h HeroInfo) LocalizedName(language string) string {
+	if name, exists := h.Names[strings.ToLower(language)]; exists {
+		return name
+	}
+	return h.Name
+}
+
+// url of the hero's image on the site
+func (h HeroInfo) ImageUrl() string {
+	return fmt.Sprintf(HeroImageUrlFormat, h.GameId)
+}
+
+// find a hero with given canonical id or game id
+func HeroById(id string) (HeroInfo, bool) {
+	for _, h := range Heroes {
+		if strings.EqualFold(h.Id, id) || strings.EqualFold(h.GameId, id) {
+			return h, true
+		}
+	}
+	return HeroInfo{}, false
+}
+
+// find a hero with given name, in any language
+func HeroByName(name string) (HeroInfo, bool) {
+	for _, h := range Heroes {
+		if strings.EqualFold(h.Name, name) {
+			return h, true
+		}
+		for _, localized := range h.Names {
+			if strings.EqualFold(localized, name) {
+				return h, true
+			}
+		}
+	}
+	return HeroInfo{}, false
+}
+
+// find metadata of a top hero, by the game id in its image url (or by its name)
+func HeroInfoOf(hero Hero) (HeroInfo, bool) {
+	if hero.ImageUrl != "" {
+		gameId := strings.TrimSuffix(path.Base(hero.ImageUrl), path.Ext(hero.ImageUrl))
+		if h, exists := HeroById(gameId); exists {
+			return h, true
+		}
+	}
+	return HeroByName(hero.Name)
+}
+
+// heroes of given role, in the order of release
+func HeroesOfRole(role string) []HeroInfo {
+	heroes := []HeroInfo{}
+	for _, h := range Heroes {
+		if h.Role == role {
+			heroes = append(heroes, h)
+		}
+	}
+	return heroes
+}
+
+// aggregated stats of heroes of a role
+type RoleStat struct {
+	Role        string   `json:"role"`
+	TimePlayed  string   `json:"time_played"` // eg. "12h 34m"
+	Share       float64  `json:"share"`       // ratio of time played among all roles (0.0 ~ 1.0)
+	GamesWon    int      `json:"games_won"`
+	GamesPlayed int      `json:"games_played"` // (estimated with win percentages of top heroes, when career stats don't have it)
+	WinRate     float64  `json:"win_rate"`     // percentage of games won (0 ~ 100, 0 when no games were played)
+	Heroes      []string `json:"heroes"`       // played heroes of the role, ordered by time played
+
+	duration time.Duration
+}
+
+// stats of a player aggregated by roles of heroes
+type RoleStats struct {
+	Roles         []RoleStat `json:"roles"`          // in the order of HeroRoles
+	PrimaryRole   string     `json:"primary_role"`   // the most played role (RoleFlex when no role is dominant, empty when nothing was played)
+	UnknownHeroes []string   `json:"unknown_heroes"` // played heroes which are not in Heroes
+}
+
+// get the stat of given role
+func (s RoleStats) Role(role string) (RoleStat, bool) {
+	for _, r := range s.Roles {
+		if r.Role == role {
+			return r, true
+		}
+	}
+	return RoleStat{}, false
+}
+
+// time played and record of a hero
+type heroRecord struct {
+	name     string
+	info     HeroInfo
+	known    bool
+	duration time.Duration
+	won      float64
+	lost     float64
+	played   float64 // (0 when unknown)
+}
+
+// aggregate given play stats (eg. quick and competitive play) by roles of heroes
+//
+// time played and games of each hero are taken from career stats, or from top heroes when career stats don't have them
+func NewRoleStats(playStats ...PlayStat) RoleStats {
+	records := []*heroRecord{}
+	indices := map[string]int{}
+	for _, playStat := range playStats {
+		for _, record := range heroRecordsOf(playStat) {
+			if record.played == 0 {
+				record.won = 0 // (only wins with known numbers of played games are summed, as in Goal.Value)
+			}
+
+			key := record.name
+			if record.known {
+				key = record.info.Id
+			}
+
+			if i, exists := indices[key]; exists {
+				records[i].duration += record.duration
+				records[i].won += record.won
+				records[i].played += record.played
+			} else {
+				indices[key] = len(records)
+				records = append(records, record)
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].duration > records[j].duration
+	})
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].duration == 0 && records[i].played == 0 { // not played at all
+			records = append(records[:i], records[i+1:]...)
+		}
+	}
+
+	stats := RoleStats{Roles: []RoleStat{}, UnknownHeroes: []string{}}
+	total := time.Duration(0)
+	for _, role := range HeroRoles {
+		roleStat := RoleStat{Role: role, Heroes: []string{}}
+
+		won, played := 0.0, 0.0
+		for _, record := range records {
+			if !record.known || record.info.Role != role {
+				continue
+			}
+
+			roleStat.duration += record.duration
+			if record.played > 0 {
+				won += record.won
+				played += record.played
+			}
+			roleStat.Heroes = append(roleStat.Heroes, record.name)
+		}
+		roleStat.TimePlayed = formatDuration(roleStat.duration)
+		roleStat.GamesWon = int(math.Round(won))
+		roleStat.GamesPlayed = int(math.Round(played))
+		if played > 0 {
+			roleStat.WinRate = won / played * 100
+		}
+		total += roleStat.duration
+
+		stats.Roles = append(stats.Roles, roleStat)
+	}
+	for _, record := range records {
+		if !record.known {
+			stats.UnknownHeroes = append(stats.UnknownHeroes, record.name)
+		}
+	}
+
+	// shares of time played, and the primary role
+	if total > 0 {
+		primary := 0
+		for i := range stats.Roles {
+			stats.Roles[i].Share = float64(stats.Roles[i].duration) / float64(total)
+			if stats.Roles[i].duration > stats.Roles[primary].duration {
+				primary = i
+			}
+		}
+		if stats.Roles[primary].Share >= PrimaryRoleMinShare {
+			stats.PrimaryRole = stats.Roles[primary].Role
+		} else {
+			stats.PrimaryRole = RoleFlex
+		}
+	}
+
+	return stats
+}
+
+// time played and records of heroes in given play stat
+func heroRecordsOf(playStat PlayStat) []*heroRecord {
+	records := []*heroRecord{}
+	indices := map[string]int{}
+	record := func(name string, info HeroInfo, known bool) *heroRecord {
+		if i, exists := indices[name]; exists {
+			return records[i]
+		}
+		indices[name] = len(records)
+		records = append(records, &heroRecord{name: name, info: info, known: known})
+		return records[len(records)-1]
+	}
+
+	// from career stats
+	for _, careerStat := range playStat.CareerStats {
+		if IsAllHeroes(careerStat.HeroName) {
+			continue
+		}
+
+		info, known := HeroByName(careerStat.HeroName)
+		record(careerStat.HeroName, info, known).readCareerStat(careerStat)
+	}
+
+	// from top heroes, when career stats don't have them
+	for _, hero := range timePlayedHeroesOf(playStat) {
+		info, known := HeroInfoOf(hero)
+		if r := record(hero.Name, info, known); r.duration == 0 {
+			r.duration, _ = ParseDuration(hero.Value)
+		}
+	}
+	wins, percentages := map[string]float64{}, map[string]float64{}
+	for _, comparison := range playStat.TopHeroes {
+		var dst map[string]float64
+		if containsLabel(labelsGamesWon, comparison.Name) {
+			dst = wins
+		} else if containsLabel(labelsWinPercentage, comparison.Name) {
+			dst = percentages
+		} else {
+			continue
+		}
+		for _, hero := range comparison.Heroes {
+			if number, err := ParseNumber(hero.Value); err == nil {
+				dst[hero.Name] = number
+			}
+		}
+	}
+	for _, r := range records {
+		if r.played > 0 {
+			continue
+		}
+		if r.won == 0 {
+			r.won = wins[r.name]
+		}
+		if percentage := percentages[r.name]; percentage > 0 && r.won > 0 {
+			r.played = math.Round(r.won * 100 / percentage)
+		}
+	}
+
+	return records
+}
+
+// read time played and games of given career stat
+func (r *heroRecord) readCareerStat(careerStat CareerStat) {
+	values := map[string]float64{}
+	for _, category := range careerStat.Categories {
+		for _, value := range category.Values {
+			if containsLabel(labelsTimePlayed, value.Key) {
+				if duration, err := ParseDuration(value.Value); err == nil {
+					r.duration = duration
+				}
+				continue
+			}
+			for _, labels := range [][]string{labelsGamesWon, labelsGamesLost, labelsGamesTied, labelsGamesPlayed} {
+				if containsLabel(labels, value.Key) {
+					if number, err := ParseNumber(value.Value); err == nil {
+						values[labels[0]] = number
+					}
+				}
+			}
+		}
+	}
+
+	// (lost or played games can be calculated with other ones)
+	r.won = values[labelsGamesWon[0]]
+	lost, hasLost := values[labelsGamesLost[0]]
+	if played, exists := values[labelsGamesPlayed[0]]; exists {
+		r.played = played
+		if !hasLost {
+			lost = played - r.won - values[labelsGamesTied[0]]
+		}
+	} else if hasLost {
+		r.played = r.won + lost + values[labelsGamesTied[0]]
+	}
+	r.lost = lost
+}
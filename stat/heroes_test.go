@@ -0,0 +1,165 @@
+package stat_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestHeroLookup(t *testing.T) {
+	ids, gameIds := map[string]bool{}, map[string]bool{}
+	for _, h := range stat.Heroes {
+		if ids[h.Id] || gameIds[h.GameId] {
+			t.Errorf("duplicated hero: %+v", h)
+		}
+		ids[h.Id], gameIds[h.GameId] = true, true
+
+		if !strings.Contains(strings.Join(stat.HeroRoles, ","), h.Role) || h.Name == "" || h.Released == "" {
+			t.Errorf("malformed hero: %+v", h)
+		}
+	}
+
+	if h, exists := stat.HeroById("SOLDIER-76"); !exists || h.Name != "Soldier: 76" || h.Role != stat.RoleDamage {
+		t.Errorf("failed to find hero by id: %+v", h)
+	}
+	if h, exists := stat.HeroByName("라인하르트"); !exists || h.Id != "reinhardt" || h.LocalizedName("de-de") != "Reinhardt" || h.LocalizedName("ja-jp") != "ラインハルト" {
+		t.Errorf("failed to find hero by localized name: %+v", h)
+	}
+	if h, exists := stat.HeroInfoOf(stat.Hero{Name: "?", ImageUrl: "https://d1u1mce87gyfbn.cloudfront.net/game/heroes/small/0x02E0000000000013.png"}); !exists || h.Id != "ana" || h.ImageUrl() != "https://d1u1mce87gyfbn.cloudfront.net/game/heroes/small/0x02E0000000000013.png" {
+		t.Errorf("failed to find hero by image url: %+v", h)
+	}
+	if _, exists := stat.HeroByName("Nobody"); exists {
+		t.Errorf("unknown hero should not be found")
+	}
+	if len(stat.HeroesOfRole(stat.RoleTank))+len(stat.HeroesOfRole(stat.RoleDamage))+len(stat.HeroesOfRole(stat.RoleSupport)) != len(stat.Heroes) {
+		t.Errorf("all heroes should have one of the roles")
+	}
+}
+
+func TestHeroNames(t *testing.T) {
+	// every hero has a name in every language, which finds the hero
+	for _, language := range stat.SupportedLanguages {
+		for _, h := range stat.Heroes {
+			name := h.LocalizedName(language)
+			if found, exists := stat.HeroByName(name); name == "" || !exists || found.Id != h.Id {
+				t.Errorf("hero %s is not found by its name in %s: '%s'", h.Id, language, name)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		language, id, name string
+	}{
+		{"de-de", "soldier-76", "Soldier: 76"},
+		{"en-gb", "reaper", "Reaper"},
+		{"en-us", "torbjorn", "Torbjörn"},
+		{"es-es", "soldier-76", "Soldado: 76"},
+		{"es-mx", "soldier-76", "Soldado: 76"},
+		{"fr-fr", "reaper", "Faucheur"},
+		{"fr-fr", "junkrat", "Chacal"},
+		{"fr-fr", "widowmaker", "Fatale"},
+		{"fr-fr", "roadhog", "Chopper"},
+		{"fr-fr", "soldier-76", "Soldat : 76"},
+		{"fr-fr", "mercy", "Ange"},
+		{"fr-fr", "wrecking-ball", "Bouldozer"},
+		{"it-it", "soldier-76", "Soldato: 76"},
+		{"ja-jp", "reinhardt", "ラインハルト"},
+		{"ko-kr", "soldier-76", "솔저: 76"},
+		{"pl-pl", "soldier-76", "Żołnierz-76"},
+		{"pl-pl", "junkrat", "Złomiarz"},
+		{"pt-br", "soldier-76", "Soldado: 76"},
+		{"ru-ru", "mercy", "Ангел"},
+		{"zh-tw", "tracer", "閃光"},
+	} {
+		if h, _ := stat.HeroById(test.id); h.LocalizedName(test.language) != test.name {
+			t.Errorf("expected name of %s in %s to be '%s', got '%s'", test.id, test.language, test.name, h.LocalizedName(test.language))
+		}
+	}
+}
+
+func TestNewRoleStats(t *testing.T) {
+	s := stattest.FixturePcCompetitive.Stat(t)
+
+	// from career stats
+	roles := stat.NewRoleStats(s.CompetitivePlay)
+	if roles.PrimaryRole != stat.RoleSupport || len(roles.Roles) != 3 || len(roles.UnknownHeroes) != 0 {
+		t.Errorf("unexpected role stats: %+v", roles)
+	}
+	if tank, _ := roles.Role(stat.RoleTank); tank.TimePlayed != "19h 26m" || tank.GamesWon != 25 || tank.GamesPlayed != 93 || strings.Join(tank.Heroes, ",") != "Reinhardt,D.Va" {
+		t.Errorf("unexpected stat of tanks: %+v", tank)
+	}
+	if support, _ := roles.Role(stat.RoleSupport); math.Abs(support.WinRate-357.0/483*100) > 0.001 || math.Abs(support.Share-0.7597) > 0.001 {
+		t.Errorf("unexpected stat of supports: %+v", support)
+	}
+
+	// from top heroes only, with games played estimated from win percentages
+	playStat := stat.PlayStat{
+		TopHeroes: stat.HeroComparisons{
+			{Name: "Time Played", Heroes: []stat.Hero{
+				{Name: "윈스턴", Value: "2 hours"},
+				{Name: "?", ImageUrl: "https://d1u1mce87gyfbn.cloudfront.net/game/heroes/small/0x02E0000000000003.png", Value: "2 hours"}, // tracer
+				{Name: "Lúcio", Value: "90 minutes"},
+				{Name: "Nobody", Value: "1 hour"},
+				{Name: "Mercy", Value: "--"},
+			}},
+			{Name: "Games Won", Heroes: []stat.Hero{{Name: "윈스턴", Value: "30"}, {Name: "Lúcio", Value: "9"}}},
+			{Name: "Win Percentage", Heroes: []stat.Hero{{Name: "윈스턴", Value: "60%"}, {Name: "Lúcio", Value: "--"}}},
+		},
+	}
+	roles = stat.NewRoleStats(playStat)
+	if roles.PrimaryRole != stat.RoleFlex || len(roles.UnknownHeroes) != 1 || roles.UnknownHeroes[0] != "Nobody" {
+		t.Errorf("unexpected role stats: %+v", roles)
+	}
+	if tank, _ := roles.Role(stat.RoleTank); tank.GamesWon != 30 || tank.GamesPlayed != 50 || tank.WinRate != 60 {
+		t.Errorf("unexpected stat of tanks: %+v", tank)
+	}
+	if support, _ := roles.Role(stat.RoleSupport); support.GamesPlayed != 0 || support.WinRate != 0 || strings.Join(support.Heroes, ",") != "Lúcio" {
+		t.Errorf("games of supports should be unknown, and unplayed heroes should be excluded: %+v", support)
+	}
+
+	// in french, without the all heroes block as an unknown hero
+	playStat = stat.PlayStat{
+		CareerStats: []stat.CareerStat{
+			{HeroName: "TOUS LES HÉROS", Categories: []stat.CareerStatCategory{{Name: "Partie", Values: stat.KeyValues{{Key: "Temps de jeu", Value: "10:00:00"}}}}},
+			{HeroName: "Faucheur", Categories: []stat.CareerStatCategory{{Name: "Partie", Values: stat.KeyValues{{Key: "Temps de jeu", Value: "04:00:00"}, {Key: "Parties gagnées", Value: "6"}, {Key: "Parties jouées", Value: "10"}}}}},
+			{HeroName: "Ange", Categories: []stat.CareerStatCategory{{Name: "Partie", Values: stat.KeyValues{{Key: "Temps de jeu", Value: "06:00:00"}}}}},
+		},
+	}
+	roles = stat.NewRoleStats(playStat)
+	if roles.PrimaryRole != stat.RoleSupport || len(roles.UnknownHeroes) != 0 {
+		t.Errorf("unexpected role stats in french: %+v", roles)
+	}
+	if damage, _ := roles.Role(stat.RoleDamage); damage.TimePlayed != "4h" || damage.GamesWon != 6 || damage.GamesPlayed != 10 || strings.Join(damage.Heroes, ",") != "Faucheur" {
+		t.Errorf("unexpected stat of damage heroes in french: %+v", damage)
+	}
+
+	// quick and competitive play: wins without games played are not summed with ones with games played
+	quickPlay := stat.PlayStat{
+		TopHeroes: stat.HeroComparisons{
+			{Name: "Time Played", Heroes: []stat.Hero{{Name: "Reaper", Value: "2 hours"}}},
+			{Name: "Games Won", Heroes: []stat.Hero{{Name: "Reaper", Value: "50"}}},
+		},
+	}
+	competitivePlay := stat.PlayStat{
+		CareerStats: []stat.CareerStat{
+			{HeroName: "Reaper", Categories: []stat.CareerStatCategory{{Name: "Game", Values: stat.KeyValues{{Key: "Time Played", Value: "01:00:00"}, {Key: "Games Won", Value: "5"}, {Key: "Games Played", Value: "10"}}}}},
+		},
+	}
+	roles = stat.NewRoleStats(quickPlay, competitivePlay)
+	if damage, _ := roles.Role(stat.RoleDamage); damage.TimePlayed != "3h" || damage.GamesWon != 5 || damage.GamesPlayed != 10 || damage.WinRate != 50 {
+		t.Errorf("unexpected stat of damage heroes in quick and competitive play: %+v", damage)
+	}
+	for _, role := range stat.NewRoleStats(s.QuickPlay, s.CompetitivePlay).Roles {
+		if role.WinRate < 0 || role.WinRate > 100 || role.GamesWon > role.GamesPlayed {
+			t.Errorf("implausible win rate of %s in quick and competitive play: %+v", role.Role, role)
+		}
+	}
+
+	// nothing played
+	if roles := stat.NewRoleStats(stat.PlayStat{}); roles.PrimaryRole != "" || len(roles.Roles) != 3 {
+		t.Errorf("unexpected role stats of nothing: %+v", roles)
+	}
+}
@@ -0,0 +1,95 @@
+package stat
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// in-memory cache of fetched assets, limited by total bytes (least recently used ones are evicted first)
+//
+// (entries expire after ttl)
+type assetLru struct {
+	sync.Mutex
+
+	maxBytes int
+	ttl      time.Duration
+	now      func() time.Time
+
+	bytes   int
+	entries map[string]*list.Element
+	order   *list.List // (most recently used ones at the front)
+}
+
+type assetLruEntry struct {
+	url      string
+	asset    asset
+	storedAt time.Time
+}
+
+// create a new cache of given limits
+func newAssetLru(maxBytes int, ttl time.Duration) *assetLru {
+	return &assetLru{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// get the cached asset of given url (expired ones are removed)
+func (c *assetLru) get(url string) (asset, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, exists := c.entries[url]; exists {
+		entry := element.Value.(*assetLruEntry)
+		if c.now().Sub(entry.storedAt) > c.ttl {
+			c.remove(element)
+			return asset{}, false
+		}
+
+		c.order.MoveToFront(element)
+		return entry.asset, true
+	}
+	return asset{}, false
+}
+
+// cache given asset of url, evicting least recently used ones when it exceeds the limit
+//
+// (assets bigger than the limit are not cached)
+func (c *assetLru) put(url string, a asset) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, exists := c.entries[url]; exists {
+		c.remove(element)
+	}
+	if len(a.bytes) > c.maxBytes {
+		return
+	}
+
+	c.entries[url] = c.order.PushFront(&assetLruEntry{url: url, asset: a, storedAt: c.now()})
+	c.bytes += len(a.bytes)
+
+	for c.bytes > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove all cached assets
+func (c *assetLru) clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.bytes = 0
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+func (c *assetLru) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*assetLruEntry)
+	delete(c.entries, entry.url)
+	c.bytes -= len(entry.asset.bytes)
+}
@@ -0,0 +1,97 @@
+package stat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAssetLru(t *testing.T) {
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := newAssetLru(10, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", asset{bytes: []byte("aaaa")})
+	cache.put("b", asset{bytes: []byte("bbbb")})
+	cache.get("a") // (b is the least recently used one now)
+	cache.put("c", asset{bytes: []byte("cccc")})
+
+	if _, exists := cache.get("b"); exists {
+		t.Errorf("the least recently used asset should be evicted")
+	}
+	for _, url := range []string{"a", "c"} {
+		if _, exists := cache.get(url); !exists {
+			t.Errorf("asset %s should be cached", url)
+		}
+	}
+	if cache.bytes != 8 {
+		t.Errorf("expected 8 bytes cached, got %d", cache.bytes)
+	}
+
+	// bigger than the limit
+	cache.put("d", asset{bytes: []byte("ddddddddddd")})
+	if _, exists := cache.get("d"); exists || cache.bytes != 8 {
+		t.Errorf("assets bigger than the limit should not be cached")
+	}
+
+	// expired
+	now = now.Add(2 * time.Hour)
+	if _, exists := cache.get("a"); exists || cache.bytes != 4 {
+		t.Errorf("expired assets should be removed")
+	}
+
+	cache.clear()
+	if _, exists := cache.get("c"); exists || cache.bytes != 0 {
+		t.Errorf("all assets should be removed")
+	}
+}
+
+func TestAssetCacheDir(t *testing.T) {
+	AssetCacheDir = t.TempDir()
+	t.Cleanup(func() { AssetCacheDir = "" })
+
+	old, recent := "http://localhost/old.png", "http://localhost/recent.png"
+	for _, url := range []string{old, recent} {
+		if err := storeCachedAsset(url, asset{bytes: []byte("0123456789"), contentType: "image/png"}); err != nil {
+			t.Fatalf("failed to store asset: %s", err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(assetCachePath(old), past, past)
+
+	// other files are not removed
+	other := filepath.Join(AssetCacheDir, "other.txt")
+	ioutil.WriteFile(other, []byte("not a cache file"), 0640)
+
+	// the oldest ones are removed first (20 bytes each, with the content type)
+	if err := pruneAssetCacheDir(40); err != nil {
+		t.Fatalf("failed to prune cache files: %s", err)
+	}
+	if _, err := loadCachedAsset(old); err != nil {
+		t.Errorf("cache files within the limit should be kept: %s", err)
+	}
+	if err := pruneAssetCacheDir(30); err != nil {
+		t.Fatalf("failed to prune cache files: %s", err)
+	}
+	if _, err := loadCachedAsset(old); err == nil {
+		t.Errorf("the oldest cache file should be removed")
+	}
+	if a, err := loadCachedAsset(recent); err != nil || a.contentType != "image/png" || string(a.bytes) != "0123456789" {
+		t.Errorf("unexpected cached asset: %+v (%v)", a, err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("files which are not cache files should not be removed")
+	}
+
+	// expired
+	expired := time.Now().Add(-AssetCacheTtl - time.Minute)
+	os.Chtimes(assetCachePath(recent), expired, expired)
+	if _, err := loadCachedAsset(recent); err == nil {
+		t.Errorf("expired cache file should not be loaded")
+	}
+	if _, err := os.Stat(assetCachePath(recent)); !os.IsNotExist(err) {
+		t.Errorf("expired cache file should be removed")
+	}
+}
@@ -0,0 +1,199 @@
+package stat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// a labeled value of stats
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// labeled values of stats, in the order of the career page
+//
+// (encoded as a json object, with its keys in order)
+type KeyValues []KeyValue
+
+// top heroes of a comparison (eg. "Time Played"), ranked as in the career page
+type HeroComparison struct {
+	Name   string
+	Heroes []Hero
+}
+
+// top heroes of all comparisons, in the order of the career page
+//
+// (encoded as a json object of comparison names and heroes, with its keys in order)
+type HeroComparisons []HeroComparison
+
+// get the value of given key (empty string when there is no such key)
+func (kv KeyValues) Get(key string) string {
+	value, _ := kv.Lookup(key)
+	return value
+}
+
+// get the value of given key, and whether it exists or not
+func (kv KeyValues) Lookup(key string) (value string, exists bool) {
+	for _, v := range kv {
+		if v.Key == key {
+			return v.Value, true
+		}
+	}
+	return "", false
+}
+
+// keys in order
+func (kv KeyValues) Keys() []string {
+	keys := []string{}
+	for _, v := range kv {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
+
+// convert to a map (which has no order)
+func (kv KeyValues) Map() map[string]string {
+	m := map[string]string{}
+	for _, v := range kv {
+		m[v.Key] = v.Value
+	}
+	return m
+}
+
+func (kv KeyValues) MarshalJSON() ([]byte, error) {
+	if kv == nil {
+		return []byte("null"), nil
+	}
+
+	return marshalOrderedObject(len(kv), func(i int) (string, interface{}) {
+		return kv[i].Key, kv[i].Value
+	})
+}
+
+func (kv *KeyValues) UnmarshalJSON(data []byte) error {
+	values := KeyValues{}
+	if null, err := unmarshalOrderedObject(data, func(key string, decoder *json.Decoder) error {
+		var value string
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		values = append(values, KeyValue{Key: key, Value: value})
+		return nil
+	}); err != nil {
+		return err
+	} else if null {
+		values = nil
+	}
+
+	*kv = values
+	return nil
+}
+
+// get heroes of given comparison (nil when there is no such comparison)
+func (hc HeroComparisons) Get(name string) []Hero {
+	for _, comparison := range hc {
+		if comparison.Name == name {
+			return comparison.Heroes
+		}
+	}
+	return nil
+}
+
+// names of comparisons in order
+func (hc HeroComparisons) Names() []string {
+	names := []string{}
+	for _, comparison := range hc {
+		names = append(names, comparison.Name)
+	}
+	return names
+}
+
+func (hc HeroComparisons) MarshalJSON() ([]byte, error) {
+	if hc == nil {
+		return []byte("null"), nil
+	}
+
+	return marshalOrderedObject(len(hc), func(i int) (string, interface{}) {
+		return hc[i].Name, hc[i].Heroes
+	})
+}
+
+func (hc *HeroComparisons) UnmarshalJSON(data []byte) error {
+	comparisons := HeroComparisons{}
+	if null, err := unmarshalOrderedObject(data, func(key string, decoder *json.Decoder) error {
+		var heroes []Hero
+		if err := decoder.Decode(&heroes); err != nil {
+			return err
+		}
+		comparisons = append(comparisons, HeroComparison{Name: key, Heroes: heroes})
+		return nil
+	}); err != nil {
+		return err
+	} else if null {
+		comparisons = nil
+	}
+
+	*hc = comparisons
+	return nil
+}
+
+// marshal n key/value pairs into a json object, with its keys in order
+func marshalOrderedObject(n int, pair func(i int) (key string, value interface{})) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, value := pair(i)
+		if bytes, err := json.Marshal(key); err == nil {
+			buffer.Write(bytes)
+		} else {
+			return nil, err
+		}
+		buffer.WriteByte(':')
+		if bytes, err := json.Marshal(value); err == nil {
+			buffer.Write(bytes)
+		} else {
+			return nil, err
+		}
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// unmarshal a json object in order, decoding each value with given function
+//
+// (returns true when it is null)
+func unmarshalOrderedObject(data []byte, decodeValue func(key string, decoder *json.Decoder) error) (null bool, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var token json.Token
+	if token, err = decoder.Token(); err != nil {
+		return false, err
+	}
+	if token == nil {
+		return true, nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return false, fmt.Errorf("expected a json object, got: %v", token)
+	}
+
+	for decoder.More() {
+		if token, err = decoder.Token(); err != nil {
+			return false, err
+		}
+		if err = decodeValue(token.(string), decoder); err != nil {
+			return false, err
+		}
+	}
+
+	// closing '}'
+	_, err = decoder.Token()
+	return false, err
+}
@@ -0,0 +1,82 @@
+package stat_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestOrderedJson(t *testing.T) {
+	playStat := stat.PlayStat{
+		FeaturedStats: stat.KeyValues{
+			{Key: "Eliminations - Avg per 10 Min", Value: "17.44"},
+			{Key: "Deaths - Avg per 10 Min", Value: "6.16"},
+			{Key: "Assists - Avg per 10 Min", Value: "8.01"},
+		},
+		TopHeroes: stat.HeroComparisons{
+			{Name: "Time Played", Heroes: []stat.Hero{{Name: "Mercy", Value: "40 hours"}, {Name: "Ana", Value: "12 hours"}}},
+			{Name: "Games Won", Heroes: []stat.Hero{{Name: "Ana", Value: "35"}}},
+		},
+	}
+
+	bytes, err := json.Marshal(playStat)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	expected := `{"featured_stats":{"Eliminations - Avg per 10 Min":"17.44","Deaths - Avg per 10 Min":"6.16","Assists - Avg per 10 Min":"8.01"},` +
+		`"top_heroes":{"Time Played":[{"name":"Mercy","image_url":"","value":"40 hours"},{"name":"Ana","image_url":"","value":"12 hours"}],"Games Won":[{"name":"Ana","image_url":"","value":"35"}]},` +
+		`"career_stats":null}`
+	if string(bytes) != expected {
+		t.Errorf("unexpected json:\n%s\nexpected:\n%s", bytes, expected)
+	}
+
+	var decoded stat.PlayStat
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, playStat) {
+		t.Errorf("round trip mismatch: %+v", decoded)
+	}
+
+	if value := playStat.FeaturedStats.Get("Deaths - Avg per 10 Min"); value != "6.16" {
+		t.Errorf("unexpected value: %s", value)
+	}
+	if heroes := playStat.TopHeroes.Get("Time Played"); len(heroes) != 2 || heroes[0].Name != "Mercy" {
+		t.Errorf("unexpected heroes: %+v", heroes)
+	}
+	if names := playStat.TopHeroes.Names(); !reflect.DeepEqual(names, []string{"Time Played", "Games Won"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	var empty stat.KeyValues
+	if err := json.Unmarshal([]byte("null"), &empty); err != nil || empty != nil {
+		t.Errorf("expected nil for null, got %v (%v)", empty, err)
+	}
+	if err := json.Unmarshal([]byte(`["not", "an", "object"]`), &empty); err == nil {
+		t.Errorf("expected an error for a json array")
+	}
+}
+
+// fetched stats should keep the order of the career page
+func TestFetchedStatOrder(t *testing.T) {
+	s := stattest.FixturePcCompetitive.Stat(t)
+
+	if names := s.QuickPlay.TopHeroes.Names(); len(names) <= 0 || names[0] != "Time Played" {
+		t.Errorf("expected 'Time Played' first in top heroes, got %v", names)
+	}
+
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	var decoded stat.Stat
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	if !reflect.DeepEqual(decoded.QuickPlay, s.QuickPlay) || !reflect.DeepEqual(decoded.CompetitivePlay, s.CompetitivePlay) {
+		t.Errorf("round trip of fetched stat mismatch")
+	}
+}
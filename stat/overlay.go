@@ -0,0 +1,412 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// layouts of overlays
+const (
+	OverlayLayoutBar     = "bar"     // a horizontal strip: portrait, level, rank, and session record
+	OverlayLayoutBox     = "box"     // a box with a bigger portrait, for corners of the screen
+	OverlayLayoutMinimal = "minimal" // rank and session record only
+)
+
+const (
+	// default interval of polling stats for overlays
+	DefaultOverlayPollInterval = 1 * time.Minute
+
+	// url path of server-sent events of overlay servers
+	OverlayEventsPath = "/events"
+)
+
+// html template for browser-source overlays (eg. in OBS), with transparent background
+//
+// - "overlay" is the part which is re-rendered and pushed to browsers on changes
+const SampleOverlayHtmlTemplate = `<html{{with .Language}} lang="{{.}}"{{end}}>
+	<head>
+		<title>Overwatch: {{.BattleTag}}</title>
+		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+		<style>
+			@font-face {
+				font-family: Koverwatch;
+				src: url(` + KoverwatchFontUrl + `) format("truetype");
+			}
+			html, body {
+				margin: 0;
+				padding: 0;
+				background-color: transparent;
+				color: #ffffff;
+				font-family: Koverwatch, Futura, century gothic, arial, sans-serif;
+				text-shadow: 0 0 3px #000000, 1px 1px 2px #000000;
+			}
+			.overlay {
+				display: inline-flex;
+				align-items: center;
+				padding: 6px 12px;
+				background-color: rgba(0, 0, 0, 0.35);
+				border-radius: 6px;
+			}
+			.overlay .item {
+				margin: 0 8px;
+				text-align: center;
+			}
+			.overlay .label {
+				display: block;
+				font-size: 12px;
+				color: #f99e1a;
+			}
+			.overlay .value {
+				font-size: 26px;
+			}
+			.overlay img.portrait {
+				width: 48px;
+				height: 48px;
+				border-radius: 4px;
+			}
+			.overlay img.rank {
+				width: 36px;
+				vertical-align: middle;
+			}
+			.overlay .delta.up {
+				color: #40ce44;
+			}
+			.overlay .delta.down {
+				color: #e0403c;
+			}
+			.overlay .wins {
+				color: #40ce44;
+			}
+			.overlay .losses {
+				color: #e0403c;
+			}
+			.layout-box {
+				flex-wrap: wrap;
+				width: 220px;
+			}
+			.layout-box img.portrait {
+				width: 96px;
+				height: 96px;
+			}
+			.layout-minimal .portrait, .layout-minimal .level {
+				display: none;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="overlay">{{template "overlay" .}}</div>
+		<script>
+			var events = new EventSource(".` + OverlayEventsPath + `");
+			events.addEventListener("update", function(e) {
+				document.getElementById("overlay").innerHTML = e.data;
+			});
+		</script>
+	</body>
+</html>
+{{define "overlay"}}
+{{if .Waiting}}
+<div class="overlay layout-{{.Layout}}">
+	<div class="item waiting"><span class="label">{{localize .Language "Waiting for stats..."}}</span></div>
+</div>
+{{else}}
+<div class="overlay layout-{{.Layout}}">
+	<div class="item portrait"><img src="{{.ProfileImageUrl}}" class="portrait"></div>
+	<div class="item level">
+		<span class="label">{{localize .Language "Level"}}</span>
+		<span class="value">{{.Level}}</span>
+	</div>
+	{{if ge .CompetitiveRank 0}}
+	<div class="item rank">
+		<span class="label">{{localize .Language "Competitive Rank"}}</span>
+		<span class="value">{{with .CompetitiveRankImageUrl}}<img src="{{.}}" class="rank">{{end}}{{.CompetitiveRank}}</span>
+		{{if gt .RankDelta 0}}<span class="delta up">+{{.RankDelta}}</span>{{else if lt .RankDelta 0}}<span class="delta down">{{.RankDelta}}</span>{{end}}
+	</div>
+	{{end}}
+	<div class="item session">
+		<span class="label">{{localize .Language "Session"}}</span>
+		<span class="value"><span class="wins">{{.SessionWins}}W</span> - <span class="losses">{{.SessionLosses}}L</span></span>
+	</div>
+</div>
+{{end}}
+{{end}}`
+
+// labels of stats for session records (in all supported languages)
+var (
+	labelsGamesWon    = labelsOf("Games Won")
+	labelsGamesLost   = labelsOf("Games Lost")
+	labelsGamesTied   = labelsOf("Games Tied")
+	labelsGamesPlayed = labelsOf("Games Played")
+)
+
+// data for overlay templates: the current stat, with changes since the start of the session
+type Overlay struct {
+	Stat
+
+	Layout        string
+	RankDelta     int32 // change of competitive rank since the start of the session
+	SessionWins   int   // games won since the start of the session
+	SessionLosses int   // games lost since the start of the session
+	Waiting       bool  // true before the first successful poll of overlay servers (a placeholder is shown)
+}
+
+// create an overlay of current stat, compared with the stat at the start of the session
+//
+// (games of competitive play are counted when the player has a competitive rank, otherwise those of quick play)
+func NewOverlay(initial, current Stat, layout string) Overlay {
+	overlay := Overlay{
+		Stat:   current,
+		Layout: layout,
+	}
+
+	if initial.CompetitiveRank != NoCompetitiveRank && current.CompetitiveRank != NoCompetitiveRank {
+		overlay.RankDelta = current.CompetitiveRank - initial.CompetitiveRank
+	}
+
+	initialPlay, currentPlay := initial.QuickPlay, current.QuickPlay
+	if current.CompetitiveRank != NoCompetitiveRank {
+		initialPlay, currentPlay = initial.CompetitivePlay, current.CompetitivePlay
+	}
+	initialWon, initialLost := gameRecord(initialPlay)
+	currentWon, currentLost := gameRecord(currentPlay)
+	if currentWon >= initialWon {
+		overlay.SessionWins = currentWon - initialWon
+	}
+	if currentLost >= initialLost {
+		overlay.SessionLosses = currentLost - initialLost
+	}
+
+	return overlay
+}
+
+// render given overlay to .html format, using template (with HtmlFuncMap)
+func RenderOverlayToHtml(overlay Overlay, templateStr string) (result string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = ParseHtmlTemplate(templateStr); err == nil {
+		var buffer bytes.Buffer
+		if err = tmpl.Execute(&buffer, overlay); err == nil {
+			return buffer.String(), nil
+		}
+	}
+	return "", err
+}
+
+// http server of overlays, which polls stats periodically and pushes changes to browsers with server-sent events
+//
+//	/        the overlay page
+//	/events  server-sent events of re-rendered overlays ("update" events)
+type OverlayServer struct {
+	Layout   string        // one of OverlayLayoutBar, OverlayLayoutBox, or OverlayLayoutMinimal
+	Interval time.Duration // interval of polling
+	Template string        // html template with "overlay" defined (default: SampleOverlayHtmlTemplate)
+
+	// function for fetching stats (default: FetchStat with the player's params)
+	Fetch func() (Stat, error)
+
+	language    string
+	lock        sync.RWMutex
+	initial     Stat
+	current     Stat
+	fetched     bool
+	subscribers map[chan string]bool
+}
+
+// create an overlay server for given player
+func NewOverlayServer(battleTagString string, battleTagNumber int, platform, region, language, layout string) *OverlayServer {
+	return &OverlayServer{
+		Layout:   layout,
+		Interval: DefaultOverlayPollInterval,
+		Template: SampleOverlayHtmlTemplate,
+		Fetch: func() (Stat, error) {
+			return FetchStat(battleTagString, battleTagNumber, platform, region, language)
+		},
+		language:    language,
+		subscribers: map[chan string]bool{},
+	}
+}
+
+// fetch stat once, and push the overlay to browsers when it has changed
+//
+// (the first successful one will be the start of the session)
+func (s *OverlayServer) Poll() (changed bool, err error) {
+	var stat Stat
+	if stat, err = s.Fetch(); err != nil && err != ErrPrivateProfile {
+		return false, err
+	}
+
+	s.lock.Lock()
+	if !s.fetched {
+		s.initial, s.current, s.fetched = stat, stat, true
+		changed = true
+	} else if !reflect.DeepEqual(s.current, stat) {
+		s.current = stat
+		changed = true
+	}
+	s.lock.Unlock()
+
+	if changed {
+		var fragment string
+		if fragment, err = s.render("overlay"); err != nil {
+			return changed, err
+		}
+		s.broadcast(fragment)
+	}
+	return changed, nil
+}
+
+// the latest polled stat, and whether it was polled successfully at least once
+func (s *OverlayServer) Stat() (stat Stat, fetched bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.current, s.fetched
+}
+
+// poll stats periodically until stop is closed
+func (s *OverlayServer) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Poll(); err != nil {
+			log.Printf("* Failed to poll stat for overlay: %s", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// poll stats in background, and serve overlays at given address (eg. "localhost:8080")
+func (s *OverlayServer) ListenAndServe(addr string) error {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go s.Run(stop)
+
+	return http.ListenAndServe(addr, s)
+}
+
+func (s *OverlayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		if html, err := s.render(""); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(html))
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case OverlayEventsPath:
+		s.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serve re-rendered overlays as server-sent events, until the client disconnects
+func (s *OverlayServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	updates := make(chan string, 1)
+	s.lock.Lock()
+	if s.subscribers == nil {
+		s.subscribers = map[chan string]bool{}
+	}
+	s.subscribers[updates] = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.subscribers, updates)
+		s.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case fragment := <-updates:
+			fmt.Fprint(w, "event: update\n")
+			for _, line := range strings.Split(fragment, "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// push given fragment to all connected browsers (skipping the ones which are lagging behind)
+func (s *OverlayServer) broadcast(fragment string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for updates := range s.subscribers {
+		select {
+		case updates <- fragment:
+		default:
+		}
+	}
+}
+
+// render the current overlay with the whole template (name == ""), or with a template of given name
+func (s *OverlayServer) render(name string) (string, error) {
+	s.lock.RLock()
+	overlay := NewOverlay(s.initial, s.current, s.Layout)
+	if !s.fetched {
+		overlay = Overlay{Layout: s.Layout, Waiting: true}
+		overlay.Language = s.language
+	}
+	s.lock.RUnlock()
+
+	templateStr := s.Template
+	if templateStr == "" {
+		templateStr = SampleOverlayHtmlTemplate
+	}
+
+	if tmpl, err := ParseHtmlTemplate(templateStr); err == nil {
+		var buffer bytes.Buffer
+		if name == "" {
+			err = tmpl.Execute(&buffer, overlay)
+		} else {
+			err = tmpl.ExecuteTemplate(&buffer, name, overlay)
+		}
+		if err == nil {
+			return buffer.String(), nil
+		}
+		return "", err
+	} else {
+		return "", err
+	}
+}
+
+// numbers of games won and lost in given play stat (from career stats of all heroes)
+func gameRecord(playStat PlayStat) (won, lost int) {
+	for _, careerStat := range playStat.CareerStats {
+		if IsAllHeroes(careerStat.HeroName) {
+			var r heroRecord
+			r.readCareerStat(careerStat)
+			return int(r.won), int(r.lost)
+		}
+	}
+	return 0, 0
+}
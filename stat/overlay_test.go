@@ -0,0 +1,162 @@
+package stat_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+// a stat with given competitive rank, and games of competitive play
+func overlayStat(rank int32, games stat.KeyValues) stat.Stat {
+	return stat.Stat{
+		BattleTag:       "meinside#3155",
+		Level:           85,
+		CompetitiveRank: rank,
+		CompetitivePlay: stat.PlayStat{
+			CareerStats: []stat.CareerStat{{
+				HeroName:   "ALL HEROES",
+				Categories: []stat.CareerStatCategory{{Name: "Game", Values: games}},
+			}},
+		},
+	}
+}
+
+func TestNewOverlay(t *testing.T) {
+	initial := overlayStat(2500, stat.KeyValues{{Key: "Games Won", Value: "10"}, {Key: "Games Played", Value: "21"}, {Key: "Games Tied", Value: "1"}})
+	current := overlayStat(2475, stat.KeyValues{{Key: "Games Won", Value: "13"}, {Key: "Games Played", Value: "26"}, {Key: "Games Tied", Value: "1"}})
+
+	overlay := stat.NewOverlay(initial, current, stat.OverlayLayoutBar)
+	if overlay.RankDelta != -25 || overlay.SessionWins != 3 || overlay.SessionLosses != 2 {
+		t.Errorf("unexpected overlay: delta %d, %dW %dL", overlay.RankDelta, overlay.SessionWins, overlay.SessionLosses)
+	}
+
+	// with lost games
+	initial = overlayStat(2500, stat.KeyValues{{Key: "Games Won", Value: "10"}, {Key: "Games Lost", Value: "7"}})
+	current = overlayStat(2530, stat.KeyValues{{Key: "Games Won", Value: "12"}, {Key: "Games Lost", Value: "7"}})
+	if overlay = stat.NewOverlay(initial, current, stat.OverlayLayoutBox); overlay.RankDelta != 30 || overlay.SessionWins != 2 || overlay.SessionLosses != 0 {
+		t.Errorf("unexpected overlay: delta %d, %dW %dL", overlay.RankDelta, overlay.SessionWins, overlay.SessionLosses)
+	}
+
+	// in other languages
+	initial = overlayStat(2500, stat.KeyValues{{Key: "Parties gagnées", Value: "10"}, {Key: "Parties jouées", Value: "20"}})
+	current = overlayStat(2500, stat.KeyValues{{Key: "Parties gagnées", Value: "11"}, {Key: "Parties jouées", Value: "23"}})
+	initial.CompetitivePlay.CareerStats[0].HeroName = "TOUS LES HÉROS"
+	current.CompetitivePlay.CareerStats[0].HeroName = "TOUS LES HÉROS"
+	if overlay = stat.NewOverlay(initial, current, stat.OverlayLayoutBar); overlay.SessionWins != 1 || overlay.SessionLosses != 2 {
+		t.Errorf("unexpected overlay in french: %dW %dL", overlay.SessionWins, overlay.SessionLosses)
+	}
+
+	// with fixtures, for all layouts
+	for _, fixture := range []stattest.Fixture{stattest.FixturePcCompetitive, stattest.FixturePcCompetitiveKorean, stattest.FixturePcNoCompetitive} {
+		s := fixture.Stat(t)
+		for _, layout := range []string{stat.OverlayLayoutBar, stat.OverlayLayoutBox, stat.OverlayLayoutMinimal} {
+			html, err := stat.RenderOverlayToHtml(stat.NewOverlay(s, s, layout), stat.SampleOverlayHtmlTemplate)
+			if err != nil {
+				t.Fatalf("failed to render overlay: %s", err)
+			}
+			if !strings.Contains(html, "layout-"+layout) || !strings.Contains(html, "0W</span> - <span class=\"losses\">0L") {
+				t.Errorf("unexpected overlay html of %s (%s):\n%s", fixture.Name, layout, html)
+			}
+			if hasRank := strings.Contains(html, `class="item rank"`); hasRank != (s.CompetitiveRank != stat.NoCompetitiveRank) {
+				t.Errorf("competitive rank should be shown only when it exists (%s)", fixture.Name)
+			}
+		}
+	}
+}
+
+func TestOverlayServer(t *testing.T) {
+	stats := make(chan stat.Stat, 2)
+	stats <- overlayStat(2500, stat.KeyValues{{Key: "Games Won", Value: "10"}, {Key: "Games Lost", Value: "7"}})
+
+	overlays := stat.NewOverlayServer("meinside", 3155, stat.PlatformPc, "kr", "en-us", stat.OverlayLayoutBar)
+	overlays.Fetch = func() (stat.Stat, error) {
+		s := <-stats
+		stats <- s // (same one again, unless changed)
+		return s, nil
+	}
+
+	// placeholder before the first poll
+	recorder := httptest.NewRecorder()
+	overlays.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, "Waiting for stats...") || strings.Contains(body, `class="item level"`) {
+		t.Errorf("unexpected overlay page before the first poll:\n%s", body)
+	}
+
+	if changed, err := overlays.Poll(); err != nil || !changed {
+		t.Fatalf("the first poll should be a change: %v (%v)", changed, err)
+	}
+
+	server := httptest.NewServer(overlays)
+	t.Cleanup(server.Close)
+
+	// overlay page
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to get overlay page: %s", err)
+	}
+	page, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if !strings.Contains(string(page), "EventSource") || !strings.Contains(string(page), ">2500<") {
+		t.Errorf("unexpected overlay page:\n%s", page)
+	}
+
+	// events
+	if res, err = http.Get(server.URL + stat.OverlayEventsPath); err != nil {
+		t.Fatalf("failed to get events: %s", err)
+	}
+	defer res.Body.Close()
+	if contentType := res.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("unexpected content type of events: %s", contentType)
+	}
+
+	events := make(chan string)
+	go func() {
+		var event strings.Builder
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				event.WriteString(line + "\n")
+			} else {
+				events <- event.String()
+				event.Reset()
+			}
+		}
+		close(events)
+	}()
+
+	// no change, no event
+	if changed, err := overlays.Poll(); err != nil || changed {
+		t.Errorf("nothing should be changed: %v (%v)", changed, err)
+	}
+
+	// changed: won a game
+	<-stats
+	stats <- overlayStat(2525, stat.KeyValues{{Key: "Games Won", Value: "11"}, {Key: "Games Lost", Value: "7"}})
+	deadline := time.After(5 * time.Second)
+	for {
+		if changed, err := overlays.Poll(); err != nil || !changed {
+			t.Fatalf("should be changed: %v (%v)", changed, err)
+		}
+
+		// (poll again if the subscription was not ready yet)
+		select {
+		case event := <-events:
+			if !strings.HasPrefix(event, "event: update\n") || !strings.Contains(event, ">2525<") || !strings.Contains(event, "+25") || !strings.Contains(event, "1W") {
+				t.Errorf("unexpected event:\n%s", event)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+			<-stats
+			stats <- overlayStat(2525, stat.KeyValues{{Key: "Games Won", Value: "11"}, {Key: "Games Lost", Value: "7"}, {Key: "Time Played", Value: time.Now().String()}})
+		case <-deadline:
+			t.Fatalf("no event was received")
+		}
+	}
+}
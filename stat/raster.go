@@ -0,0 +1,357 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"io/ioutil"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+const (
+	DefaultJpegQuality       = 90              // quality of .jpg banners (1 ~ 100)
+	DefaultAnimatedGifDelay  = 3 * time.Second // delay between panels of animated .gif banners
+	BannerPanelCompetitive   = "Competitive"   // title of the competitive rank panel
+	maxBannerPanelTopHeroes  = 3
+	maxBannerPanelCellHeight = 0.5 // maximum ratio of height to width of featured stats' cells
+)
+
+// render given stat to a banner file in .jpg format, with given layout, colors, and quality (1 ~ 100)
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToJpegFile(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, quality int, outFilepath string) error {
+	if bytes, err := RenderStatToJpegBytes(stat, spec, logo, font, quality); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// return bytes of generated banner in .jpg format, with given layout, colors, and quality (1 ~ 100)
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToJpegBytes(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, quality int) ([]byte, error) {
+	if image, err := genBanner(stat, spec, logo, font); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := jpeg.Encode(imgBytes, image, &jpeg.Options{Quality: quality}); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render given stat to a banner file in .gif format, with given layout and colors
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToGifFile(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, outFilepath string) error {
+	if bytes, err := RenderStatToGifBytes(stat, spec, logo, font); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// return bytes of generated banner in .gif format, with given layout and colors
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToGifBytes(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font) ([]byte, error) {
+	if image, err := genBanner(stat, spec, logo, font); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := gif.Encode(imgBytes, toPaletted(image, spec), nil); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render given stat to an animated banner file in .gif format, with given layout and colors
+//
+// it cycles through panels of: profile and level, competitive rank, top heroes by time played, and featured stats
+// (panels without any data are skipped)
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+// - when delay is not positive: DefaultAnimatedGifDelay will be used
+func RenderStatToAnimatedGifFile(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, delay time.Duration, outFilepath string) error {
+	if bytes, err := RenderStatToAnimatedGifBytes(stat, spec, logo, font, delay); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// return bytes of generated animated banner in .gif format, with given layout and colors
+//
+// it cycles through panels of: profile and level, competitive rank, top heroes by time played, and featured stats
+// (panels without any data are skipped)
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+// - when delay is not positive: DefaultAnimatedGifDelay will be used
+func RenderStatToAnimatedGifBytes(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, delay time.Duration) ([]byte, error) {
+	if delay <= 0 {
+		delay = DefaultAnimatedGifDelay
+	}
+
+	if panels, err := genBannerPanels(stat, spec, logo, font); err == nil {
+		anim := &gif.GIF{}
+		for _, panel := range panels {
+			anim.Image = append(anim.Image, toPaletted(panel, spec))
+			anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond))) // in 100ths of a second
+		}
+
+		imgBytes := new(bytes.Buffer)
+		if err := gif.EncodeAll(imgBytes, anim); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// generate panels of an animated banner with given layout and colors
+func genBannerPanels(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font) (panels []*image.RGBA, err error) {
+	// load logo image and .ttf font only once, for all panels
+	if spec.Logo.Show && logo == nil {
+		if logo, err = getImage(spec.logoImageUrl()); err != nil {
+			return nil, err
+		}
+	}
+	if font == nil {
+		if font, err = getFont(KoverwatchFontUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	// profile and level (rank and top hero have their own panels)
+	profileSpec := spec
+	profileSpec.Rank.Show = false
+	profileSpec.TopHero.Show = false
+
+	var profile *image.RGBA
+	if profile, err = genBanner(stat, profileSpec, logo, font); err != nil {
+		return nil, err
+	}
+	panels = append(panels, profile)
+
+	// other panels share the background and logo, and use the area next to the logo
+	baseSpec := spec
+	baseSpec.Portrait.Show = false
+
+	var base *image.RGBA
+	if base, err = genBannerBase(stat, baseSpec, logo); err != nil {
+		return nil, err
+	}
+	area := bannerPanelArea(spec)
+
+	context := freetype.NewContext()
+	context.SetFont(font)
+	context.SetDPI(72)
+	context.SetSrc(&image.Uniform{spec.TextColor})
+
+	// competitive rank (only when it exists)
+	if stat.CompetitiveRank != NoCompetitiveRank {
+		panel := cloneRGBA(base)
+		context.SetDst(panel)
+
+		icon, caption := splitIconAndCaption(area)
+
+		var rankIcon image.Image
+		if rankIcon, err = getImage(stat.CompetitiveRankImageUrl); err == nil {
+			drawResized(panel, icon, rankIcon)
+		} else {
+			return nil, err
+		}
+
+		if err = drawCaption(context, caption, Localize(stat.Language, BannerPanelCompetitive), fmt.Sprintf("%d", stat.CompetitiveRank)); err != nil {
+			return nil, err
+		}
+
+		panels = append(panels, panel)
+	}
+
+	// top heroes by time played (only when they exist)
+	if heroes := timePlayedHeroes(stat); len(heroes) > 0 {
+		if len(heroes) > maxBannerPanelTopHeroes {
+			heroes = heroes[:maxBannerPanelTopHeroes]
+		}
+
+		panel := cloneRGBA(base)
+		context.SetDst(panel)
+
+		var cells []image.Rectangle
+		if area.Dx() >= area.Dy() {
+			cells = splitArea(area, maxBannerPanelTopHeroes, 1)
+		} else {
+			cells = splitArea(area, 1, maxBannerPanelTopHeroes)
+		}
+
+		for i, hero := range heroes {
+			icon, caption := splitIconAndCaption(cells[i])
+
+			var heroImage image.Image
+			if heroImage, err = getImage(hero.ImageUrl); err == nil {
+				drawResized(panel, icon, heroImage)
+			} else {
+				return nil, err
+			}
+
+			if err = drawCaption(context, caption, hero.Name, hero.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		panels = append(panels, panel)
+	}
+
+	// featured stats (only when they exist)
+	if featured := stat.QuickPlay.FeaturedStats; len(featured) > 0 {
+		// fit as many rows as possible, with cells not taller than half of their width
+		columns := 3
+		if area.Dx() < area.Dy() {
+			columns = 2
+		}
+		rows := int(math.Round(float64(area.Dy()) / (float64(area.Dx()/columns) * maxBannerPanelCellHeight)))
+		if maxRows := (len(featured) + columns - 1) / columns; rows > maxRows {
+			rows = maxRows
+		}
+		if rows < 1 {
+			rows = 1
+		}
+
+		panel := cloneRGBA(base)
+		context.SetDst(panel)
+
+		for i, cell := range splitArea(area, columns, rows) {
+			if i >= len(featured) {
+				break
+			}
+
+			if err = drawCaption(context, cell, featured[i].Key, featured[i].Value); err != nil {
+				return nil, err
+			}
+		}
+
+		panels = append(panels, panel)
+	}
+
+	return panels, nil
+}
+
+// area of panels, next to the logo (on its right or below it, whichever is larger)
+func bannerPanelArea(spec BannerSpec) image.Rectangle {
+	area := image.Rect(Margin, Margin, spec.Width-Margin, spec.Height-Margin)
+	if !spec.Logo.Show {
+		return area
+	}
+
+	right := image.Rect(spec.Logo.Rect.Max.X+Margin, area.Min.Y, area.Max.X, area.Max.Y)
+	below := image.Rect(area.Min.X, spec.Logo.Rect.Max.Y+Margin, area.Max.X, area.Max.Y)
+	if below.Dx()*below.Dy() > right.Dx()*right.Dy() {
+		return below.Canon()
+	}
+	return right.Canon()
+}
+
+// split given area into cells of given columns and rows (with margins between them)
+func splitArea(area image.Rectangle, columns, rows int) (cells []image.Rectangle) {
+	width := (area.Dx() - Margin*(columns-1)) / columns
+	height := (area.Dy() - Margin*(rows-1)) / rows
+
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			x := area.Min.X + column*(width+Margin)
+			y := area.Min.Y + row*(height+Margin)
+			cells = append(cells, image.Rect(x, y, x+width, y+height))
+		}
+	}
+	return cells
+}
+
+// split given area into an icon and its caption
+// (icon on the left, or on the top when the area is taller than its width)
+func splitIconAndCaption(area image.Rectangle) (icon, caption image.Rectangle) {
+	if area.Dy() > area.Dx() {
+		size := minInt(area.Dx(), area.Dy()*3/5)
+		x := area.Min.X + (area.Dx()-size)/2
+		icon = image.Rect(x, area.Min.Y, x+size, area.Min.Y+size)
+		caption = image.Rect(area.Min.X, icon.Max.Y+Margin, area.Max.X, area.Max.Y)
+	} else {
+		size := minInt(area.Dy(), area.Dx()*2/5)
+		y := area.Min.Y + (area.Dy()-size)/2
+		icon = image.Rect(area.Min.X, y, area.Min.X+size, y+size)
+		caption = image.Rect(icon.Max.X+Margin, icon.Min.Y, area.Max.X, icon.Max.Y)
+	}
+	return icon, caption
+}
+
+// draw a title and its value in given area, with font sizes fit to the area (roughly)
+func drawCaption(context *freetype.Context, rect image.Rectangle, title, value string) (err error) {
+	context.SetClip(rect)
+
+	height := float64(rect.Dy())
+	if err = drawFittedString(context, rect, title, height*0.3, int(height*0.38)); err != nil {
+		return err
+	}
+	return drawFittedString(context, rect, value, height*0.4, int(height*0.88))
+}
+
+// draw a string at given baseline of the area, shrinking its font size when it seems too wide for the area
+func drawFittedString(context *freetype.Context, rect image.Rectangle, text string, fontSize float64, baselineY int) error {
+	// (width of a glyph is assumed to be about 0.55 times the font size)
+	if length := utf8.RuneCountInString(text); length > 0 {
+		fontSize = math.Min(fontSize, float64(rect.Dx())/(0.55*float64(length)))
+	}
+
+	context.SetFontSize(fontSize)
+	_, err := context.DrawString(text, freetype.Pt(rect.Min.X, rect.Min.Y+baselineY))
+	return err
+}
+
+// convert given image to a paletted one for .gif files
+//
+// (background and text colors of the spec are kept exact, so they are not dithered)
+func toPaletted(img image.Image, spec BannerSpec) *image.Paletted {
+	colors := color.Palette{spec.BackgroundColor, spec.TextColor}
+	colors = append(colors, palette.Plan9[:len(palette.Plan9)-len(colors)]...)
+
+	paletted := image.NewPaletted(img.Bounds(), colors)
+	draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, image.ZP)
+	return paletted
+}
+
+// copy given image
+func cloneRGBA(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
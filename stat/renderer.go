@@ -8,8 +8,7 @@ import (
 	"image/color"
 	"image/draw"
 	"image/png"
-	"io/ioutil"
-	"net/http"
+	"math"
 	"os"
 	"strings"
 
@@ -19,12 +18,16 @@ import (
 )
 
 const (
-	SampleHtmlTemplate = `<html>
+	SampleHtmlTemplate = `<html{{with .Language}} lang="{{.}}"{{end}}>
 	<head>
 		<title>Overwatch: Stats of {{.BattleTag}} / {{.Region}} ({{.Platform}})</title>
 		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
 		<meta name="viewport" content="user-scalable=yes, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0, width=device-width">
 		<style>
+			@font-face {
+				font-family: Koverwatch;
+				src: url(` + KoverwatchFontUrl + `) format("truetype");
+			}
 			body {
 				display: block;
 				padding: 3px;
@@ -125,6 +128,39 @@ const (
 			img.rank {
 				width: 60px;
 			}
+			div.endorsement {
+				display: inline-block;
+				width: 80px;
+				height: 80px;
+				text-align: center;
+				position: relative;
+				float: left;
+			}
+			div.endorsement-level {
+				width: 44px;
+				height: 44px;
+				margin: 6px auto 8px auto;
+				border: 3px solid rgba(240,237,242,.6);
+				border-radius: 50%;
+				line-height: 44px;
+				font-size: 1.2rem;
+				font-family: Koverwatch, sans-serif;
+			}
+			div.endorsement-breakdown {
+				display: flex;
+				width: 60px;
+				height: 6px;
+				margin: 0 auto;
+			}
+			span.shotcaller {
+				background-color: #f19512;
+			}
+			span.teammate {
+				background-color: #c81af5;
+			}
+			span.sportsmanship {
+				background-color: #40ce44;
+			}
 			img.hero-portrait {
 				width: 32px;
 			}
@@ -156,6 +192,10 @@ const (
 			span.value {
 				font-weight: bold;
 			}
+			div.chart {
+				display: inline-block;
+				margin: 5px;
+			}
 		</style>
 	</head>
 	<body>
@@ -179,30 +219,56 @@ const (
 						</div>
 					</div>
 				{{end}}
+				{{if ge .EndorsementLevel 1}}
+					<div class="info-item">
+						<div class="endorsement">
+							<div class="endorsement-level">{{.EndorsementLevel}}</div>
+							<div class="endorsement-breakdown">
+								<span class="shotcaller" style="flex-grow: {{.EndorsementBreakdown.Shotcaller}}" title="{{localize .Language "Shotcaller"}} {{percent .EndorsementBreakdown.Shotcaller}}"></span>
+								<span class="teammate" style="flex-grow: {{.EndorsementBreakdown.Teammate}}" title="{{localize .Language "Good Teammate"}} {{percent .EndorsementBreakdown.Teammate}}"></span>
+								<span class="sportsmanship" style="flex-grow: {{.EndorsementBreakdown.Sportsmanship}}" title="{{localize .Language "Sportsmanship"}} {{percent .EndorsementBreakdown.Sportsmanship}}"></span>
+							</div>
+						</div>
+					</div>
+				{{end}}
 			</div>
 		</div>
+		{{if .Charts}}
+		<div id="charts">
+			<h1>{{localize .Language "Charts"}}</h1>
+			{{range .Charts}}
+				<div class="chart">{{.}}</div>
+			{{end}}
+		</div>
+		{{end}}
+		{{if .Private}}
+		<div id="private">
+			<h1>{{localize .Language "Private Profile"}}</h1>
+			<p>{{localize .Language "Stats and achievements of this player are not public."}}</p>
+		</div>
+		{{else}}
 		<div id="quick-play">
-			<h1>Quick Play</h1>
+			<h1>{{localize .Language "Quick Play"}}</h1>
 			<div class="featured-stats">
-				<h2>Featured Stats</h2>
+				<h2>{{localize .Language "Featured Stats"}}</h2>
 				<ul>
-					{{range $key, $val := .QuickPlay.FeaturedStats}}
+					{{range .QuickPlay.FeaturedStats}}
 						<li>
-							<span class="key">{{$key}}</span><span class="value">{{$val}}</span>
+							<span class="key">{{.Key}}</span><span class="value">{{.Value}}</span>
 						</li>
 					{{end}}
 				</ul>
 			</div>
 			<div class="top-heroes">
-				<h2>Top Heroes</h2>
+				<h2>{{localize .Language "Top Heroes"}}</h2>
 				<ul>
-				{{range $key, $val := .QuickPlay.TopHeroes}}
+				{{range .QuickPlay.TopHeroes}}
 					<li>
-						<h3>{{$key}}</h3>
+						<h3>{{.Name}}</h3>
 						<ul>
-							{{range $key, $val := $val}}
+							{{range .Heroes}}
 								<li>
-									<span class="key"><img src="{{$val.ImageUrl}}" class="hero-portrait"> {{$val.Name}}</span><span class="value">{{$val.Value}}</span>
+									<span class="key"><img src="{{.ImageUrl}}" class="hero-portrait"> {{.Name}}</span><span class="value">{{.Value}}</span>
 								</li>
 							{{end}}
 						</ul>
@@ -211,7 +277,7 @@ const (
 				</ul>
 			</div>
 			<div class="career-stats">
-				<h2>Career Stats</h2>
+				<h2>{{localize .Language "Career Stats"}}</h2>
 				<ul>
 					{{range .QuickPlay.CareerStats}}
 						<li>
@@ -221,9 +287,9 @@ const (
 									<li>
 										<h4>{{.Name}}</h4>
 										<ul>
-											{{range $key, $val := .Values}}
+											{{range .Values}}
 												<li>
-													<span class="key">{{$key}}</span><span class="value">{{$val}}</span>
+													<span class="key">{{.Key}}</span><span class="value">{{.Value}}</span>
 												</li>
 											{{end}}
 										</ul>
@@ -236,27 +302,27 @@ const (
 			</div>
 		</div>
 		<div id="competitive-play">
-			<h1>Competitive Play</h1>
+			<h1>{{localize .Language "Competitive Play"}}</h1>
 			<div class="featured-stats">
-				<h2>Featured Stats</h2>
+				<h2>{{localize .Language "Featured Stats"}}</h2>
 				<ul>
-					{{range $key, $val := .CompetitivePlay.FeaturedStats}}
+					{{range .CompetitivePlay.FeaturedStats}}
 						<li>
-							<span class="key">{{$key}}</span><span class="value">{{$val}}</span>
+							<span class="key">{{.Key}}</span><span class="value">{{.Value}}</span>
 						</li>
 					{{end}}
 				</ul>
 			</div>
 			<div class="top-heroes">
-				<h2>Top Heroes</h2>
+				<h2>{{localize .Language "Top Heroes"}}</h2>
 				<ul>
-					{{range $key, $val := .CompetitivePlay.TopHeroes}}
+					{{range .CompetitivePlay.TopHeroes}}
 						<li>
-							<h3>{{$key}}</h3>
+							<h3>{{.Name}}</h3>
 							<ul>
-								{{range $key, $val := $val}}
+								{{range .Heroes}}
 									<li>
-										<span class="key"><img src="{{$val.ImageUrl}}" class="hero-portrait"> {{$val.Name}}</span><span class="value">{{$val.Value}}</span>
+										<span class="key"><img src="{{.ImageUrl}}" class="hero-portrait"> {{.Name}}</span><span class="value">{{.Value}}</span>
 									</li>
 								{{end}}
 							</ul>
@@ -265,7 +331,7 @@ const (
 				</ul>
 			</div>
 			<div class="career-stats">
-				<h2>Career Stats</h2>
+				<h2>{{localize .Language "Career Stats"}}</h2>
 				<ul>
 					{{range .CompetitivePlay.CareerStats}}
 						<li>
@@ -275,9 +341,9 @@ const (
 									<li>
 										<h4>{{.Name}}</h4>
 										<ul>
-											{{range $key, $val := .Values}}
+											{{range .Values}}
 												<li>
-													<span class="key">{{$key}}</span><span class="value">{{$val}}</span>
+													<span class="key">{{.Key}}</span><span class="value">{{.Value}}</span>
 												</li>
 											{{end}}
 										</ul>
@@ -290,7 +356,7 @@ const (
 			</div>
 		</div>
 		<div id="achievements">
-			<h1>Achievements</h1>
+			<h1>{{localize .Language "Achievements"}}</h1>
 			<ul>
 			{{range .Achievements}}
 				<li>
@@ -317,6 +383,7 @@ const (
 			{{end}}
 			</ul>
 		</div>
+		{{end}}
 	</body>
 </html>`
 )
@@ -324,17 +391,26 @@ const (
 // sizes
 const (
 	// positions and sizes on banner
-	BannerWidth        = 320
-	BannerHeight       = 50
-	Margin             = 4
-	BannerRankIconSize = 35
-	BannerLevelBgSize  = BannerHeight
+	BannerWidth           = 320
+	BannerHeight          = 50
+	Margin                = 4
+	BannerRankIconSize    = 35
+	BannerLevelBgSize     = BannerHeight
+	BannerEndorsementSize = 18
 
 	// font sizes
-	FontSizeBattleTag float64 = 17.0
-	FontSizeDetail    float64 = 13.0
-	FontSizeLevel     float64 = 11.0
-	FontSizeRank      float64 = 11.0
+	FontSizeBattleTag   float64 = 17.0
+	FontSizeDetail      float64 = 13.0
+	FontSizeLevel       float64 = 11.0
+	FontSizeRank        float64 = 11.0
+	FontSizeEndorsement float64 = 10.0
+)
+
+// colors of endorsements
+var (
+	colorShotcaller    = color.RGBA{241, 149, 18, 255}
+	colorTeammate      = color.RGBA{200, 26, 245, 255}
+	colorSportsmanship = color.RGBA{64, 206, 68, 255}
 )
 
 const (
@@ -343,14 +419,65 @@ const (
 	KoverwatchFontUrl     = "http://kr.battle.net/forums/static/fonts/koverwatch/koverwatch.ttf"
 )
 
-// render given stat to .html format, using template
+// data for html templates: a stat, with charts of its snapshots
+type HtmlReport struct {
+	Stat
+
+	Charts []template.HTML // charts in .svg format
+}
+
+// render given stat to .html format, using template (with HtmlFuncMap)
 func RenderStatToHtml(stat Stat, templateStr string) (result string, err error) {
 	var tmpl *template.Template
-	if tmpl, err = template.New("html").Parse(templateStr); err == nil {
-		var buffer bytes.Buffer
-		if err = tmpl.Execute(&buffer, stat); err == nil {
-			return buffer.String(), nil
+	if tmpl, err = ParseHtmlTemplate(templateStr); err == nil {
+		return RenderStatToHtmlWithTemplate(stat, nil, tmpl)
+	}
+	return "", err
+}
+
+// render given stat to .html format with charts of its snapshots (competitive rank, level, and time played), using template (with HtmlFuncMap)
+//
+// (charts without any data are skipped)
+func RenderStatToHtmlWithCharts(stat Stat, snapshots []Snapshot, templateStr string) (result string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = ParseHtmlTemplate(templateStr); err == nil {
+		return RenderStatToHtmlWithTemplate(stat, snapshots, tmpl)
+	}
+	return "", err
+}
+
+// render given stat to .html format with charts of its snapshots (if any), using a parsed or loaded template
+//
+// (see ParseHtmlTemplate and LoadHtmlTemplate)
+func RenderStatToHtmlWithTemplate(stat Stat, snapshots []Snapshot, tmpl *template.Template) (result string, err error) {
+	report := HtmlReport{Stat: stat}
+	for _, chart := range []Chart{
+		CompetitiveRankChart(snapshots),
+		LevelChart(snapshots),
+		TimePlayedChart(snapshots),
+	} {
+		if len(chart.Points) <= 0 {
+			continue
+		}
+
+		// labels in the language of the stat
+		chart.Title = Localize(stat.Language, chart.Title)
+		for i := range chart.Points {
+			if chart.Points[i].Label == ChartLabelOthers {
+				chart.Points[i].Label = Localize(stat.Language, ChartLabelOthers)
+			}
 		}
+
+		var svg string
+		if svg, err = RenderChartToSvg(chart); err != nil {
+			return "", err
+		}
+		report.Charts = append(report.Charts, template.HTML(svg))
+	}
+
+	var buffer bytes.Buffer
+	if err = tmpl.Execute(&buffer, report); err == nil {
+		return buffer.String(), nil
 	}
 	return "", err
 }
@@ -360,9 +487,17 @@ func RenderStatToHtml(stat Stat, templateStr string) (result string, err error)
 // - when logo is nil: it will be loaded from OverwatchLogoImageUrl
 // - when font is nil: it will be loaded from KoverwatchFontUrl
 func RenderStatToPngFile(stat Stat, logo image.Image, font *truetype.Font, outFilepath string) error {
-	if image, err := genBanner(stat, logo, font); err == nil {
+	return RenderStatToPngFileWithSpec(stat, DefaultBannerSpec, logo, font, outFilepath)
+}
+
+// render given stat to a banner file in .png format, with given layout and colors
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToPngFileWithSpec(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font, outFilepath string) error {
+	if image, err := genBanner(stat, spec, logo, font); err == nil {
 		var file *os.File
-		if file, err = os.OpenFile(outFilepath, os.O_WRONLY|os.O_CREATE, 0640); err == nil {
+		if file, err = os.OpenFile(outFilepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640); err == nil {
 			defer file.Close()
 
 			return png.Encode(file, image)
@@ -379,7 +514,15 @@ func RenderStatToPngFile(stat Stat, logo image.Image, font *truetype.Font, outFi
 // - when logo is nil: it will be loaded from OverwatchLogoImageUrl
 // - when font is nil: it will be loaded from KoverwatchFontUrl
 func RenderStatToPngBytes(stat Stat, logo image.Image, font *truetype.Font) ([]byte, error) {
-	if image, err := genBanner(stat, logo, font); err == nil {
+	return RenderStatToPngBytesWithSpec(stat, DefaultBannerSpec, logo, font)
+}
+
+// return bytes of generated banner in .png format, with given layout and colors
+//
+// - when logo is nil: it will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderStatToPngBytesWithSpec(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font) ([]byte, error) {
+	if image, err := genBanner(stat, spec, logo, font); err == nil {
 		imgBytes := new(bytes.Buffer)
 		if err := png.Encode(imgBytes, image); err == nil {
 			return imgBytes.Bytes(), nil
@@ -391,202 +534,250 @@ func RenderStatToPngBytes(stat Stat, logo image.Image, font *truetype.Font) ([]b
 	}
 }
 
-// generate a banner image
-func genBanner(stat Stat, logo image.Image, font *truetype.Font) (result *image.RGBA, err error) {
-	banner := image.NewRGBA(image.Rect(0, 0, BannerWidth, BannerHeight))
-
-	// fill background color (#405275)
-	draw.Draw(
-		banner,
-		banner.Bounds(),
-		&image.Uniform{color.RGBA{64, 82, 117, 255}},
-		image.ZP,
-		draw.Src,
-	)
+// generate a banner image with given layout and colors
+func genBanner(stat Stat, spec BannerSpec, logo image.Image, font *truetype.Font) (result *image.RGBA, err error) {
+	var banner *image.RGBA
+	if banner, err = genBannerBase(stat, spec, logo); err != nil {
+		return nil, err
+	}
 
-	// load logo image
-	if logo == nil {
-		if logo, err = getImage(OverwatchLogoImageUrl); err != nil {
+	// load .ttf font
+	if font == nil {
+		if font, err = getFont(KoverwatchFontUrl); err != nil {
 			return nil, err
 		}
 	}
 
-	// resize logo image to fit in the banner
-	logo = resize.Resize(BannerHeight, BannerHeight, logo, resize.Lanczos3)
-
-	// draw logo image
-	draw.Draw(
-		banner,
-		image.Rectangle{
-			Min: image.Point{X: 0, Y: 0},
-			Max: image.Point{X: BannerHeight, Y: BannerHeight},
-		},
-		logo,
-		image.ZP,
-		draw.Over,
-	)
-
-	// load profile image from url
-	var profile image.Image
-	if profile, err = getImage(stat.ProfileImageUrl); err != nil {
+	if err = drawBannerElements(banner, stat, spec, font); err != nil {
 		return nil, err
 	}
 
-	// resize profile image to fit in the banner
-	profile = resize.Resize(BannerHeight, BannerHeight, profile, resize.Lanczos3)
+	return banner, nil
+}
+
+// generate a banner image with background color, logo, and profile image only
+func genBannerBase(stat Stat, spec BannerSpec, logo image.Image) (result *image.RGBA, err error) {
+	banner := image.NewRGBA(image.Rect(0, 0, spec.Width, spec.Height))
 
-	// draw profile image
+	// fill background color
 	draw.Draw(
 		banner,
-		image.Rectangle{
-			Min: image.Point{X: BannerWidth - BannerHeight, Y: 0},
-			Max: image.Point{X: BannerWidth, Y: BannerHeight},
-		},
-		profile,
+		banner.Bounds(),
+		&image.Uniform{spec.BackgroundColor},
 		image.ZP,
-		draw.Over,
+		draw.Src,
 	)
 
-	// load .ttf font
-	if font == nil {
-		if font, err = getFont(KoverwatchFontUrl); err != nil {
+	// logo image
+	if spec.Logo.Show {
+		// load logo image
+		if logo == nil {
+			if logo, err = getImage(spec.logoImageUrl()); err != nil {
+				return nil, err
+			}
+		}
+
+		drawResized(banner, spec.Logo.Rect, logo)
+	}
+
+	// profile image
+	if spec.Portrait.Show {
+		// load profile image from url
+		var profile image.Image
+		if profile, err = getImage(stat.ProfileImageUrl); err != nil {
 			return nil, err
 		}
+
+		drawResized(banner, spec.Portrait.Rect, profile)
 	}
 
+	return banner, nil
+}
+
+// draw texts and icons of given stat on banner
+func drawBannerElements(banner *image.RGBA, stat Stat, spec BannerSpec, font *truetype.Font) (err error) {
 	// setup context
 	context := freetype.NewContext()
 	context.SetFont(font)
 	context.SetDPI(72)
 	context.SetClip(banner.Bounds())
 	context.SetDst(banner)
-	context.SetSrc(image.White)
-
-	var label string
+	context.SetSrc(&image.Uniform{spec.TextColor})
 
 	// print battletag, platform, and region
-	context.SetFontSize(FontSizeBattleTag)
-	if strings.EqualFold(stat.Platform, PlatformPc) {
-		label = fmt.Sprintf("%s  %s/%s", stat.BattleTag, stat.Platform, stat.Region)
-	} else {
-		label = fmt.Sprintf("%s / %s", stat.BattleTag, stat.Platform)
-	}
-	if _, err = context.DrawString(
-		label,
-		freetype.Pt(
-			int(BannerHeight+Margin),
-			int(context.PointToFixed(FontSizeBattleTag)>>6),
-		),
-	); err != nil {
-		return nil, err
+	if spec.BattleTag.Show {
+		var label string
+		if strings.EqualFold(stat.Platform, PlatformPc) {
+			label = fmt.Sprintf("%s  %s/%s", stat.BattleTag, stat.Platform, stat.Region)
+		} else {
+			label = fmt.Sprintf("%s / %s", stat.BattleTag, stat.Platform)
+		}
+		if err = drawText(context, spec.BattleTag, label, 0); err != nil {
+			return err
+		}
 	}
 
 	// print detail,
-	context.SetFontSize(FontSizeDetail)
-	if _, err = context.DrawString(
-		stat.Detail,
-		freetype.Pt(
-			BannerHeight+Margin,
-			int(context.PointToFixed(BannerHeight*0.88)>>6),
-		),
-	); err != nil {
-		return nil, err
+	if spec.Detail.Show {
+		if err = drawText(context, spec.Detail, stat.Detail, 0); err != nil {
+			return err
+		}
 	}
-	// level stars (if exists)
-	levelBgStartY := 0.0
-	levelTextY := BannerHeight * 0.58
-	if stat.LevelStarImageUrl != "" {
-		levelBgStartY = -BannerHeight * 0.1
-		levelTextY = BannerHeight * 0.48
-
-		var levelStar image.Image
-		if levelStar, err = getImage(stat.LevelStarImageUrl); err == nil {
-			// load and resize stars to fit in the banner
-			levelStar = resize.Resize(BannerLevelBgSize, BannerLevelBgSize*0.5, levelStar, resize.Lanczos3)
-
-			draw.Draw(
-				banner,
-				image.Rectangle{
-					Min: image.Point{X: BannerWidth - BannerHeight*2, Y: BannerLevelBgSize * 0.5},
-					Max: image.Point{X: BannerWidth - BannerHeight, Y: BannerLevelBgSize},
-				},
-				levelStar,
-				image.ZP,
-				draw.Over,
-			)
+
+	// level stars (if exists), level, and level bg
+	if spec.Level.Show {
+		levelRect := spec.Level.Rect
+		levelTextShiftY := 0
+		if stat.LevelStarImageUrl != "" {
+			// shift level bg and text up, for making room for the stars
+			shift := -int(float64(levelRect.Dy()) * 0.1)
+			levelRect = levelRect.Add(image.Pt(0, shift))
+			levelTextShiftY = shift
+
+			var levelStar image.Image
+			if levelStar, err = getImage(stat.LevelStarImageUrl); err == nil {
+				// stars on the lower half
+				drawResized(banner, image.Rect(
+					spec.Level.Rect.Min.X,
+					spec.Level.Rect.Min.Y+spec.Level.Rect.Dy()/2,
+					spec.Level.Rect.Max.X,
+					spec.Level.Rect.Max.Y,
+				), levelStar)
+			} else {
+				return err
+			}
+		}
+
+		var levelBg image.Image
+		if levelBg, err = getImage(stat.LevelImageUrl); err == nil {
+			drawResized(banner, levelRect, levelBg)
 		} else {
-			return nil, err
+			return err
+		}
+
+		if err = drawText(context, spec.Level, fmt.Sprintf("%3d", stat.Level), levelTextShiftY); err != nil {
+			return err
 		}
 	}
-	// level and level bg
-	var levelBg image.Image
-	if levelBg, err = getImage(stat.LevelImageUrl); err == nil {
-		// load and resize level bg to fit in the banner
-		levelBg = resize.Resize(BannerLevelBgSize, BannerLevelBgSize, levelBg, resize.Lanczos3)
-
-		draw.Draw(
-			banner,
-			image.Rectangle{
-				Min: image.Point{X: BannerWidth - BannerHeight*2, Y: int(levelBgStartY)},
-				Max: image.Point{X: BannerWidth - BannerHeight, Y: BannerHeight + int(levelBgStartY)},
-			},
-			levelBg,
-			image.ZP,
-			draw.Over,
-		)
-	} else {
-		return nil, err
-	}
-	context.SetFontSize(FontSizeLevel)
-	if _, err = context.DrawString(
-		fmt.Sprintf("%3d", stat.Level),
-		freetype.Pt(
-			int(BannerWidth-BannerHeight*1.64),
-			int(context.PointToFixed(levelTextY)>>6),
-		),
-	); err != nil {
-		return nil, err
-	}
+
 	// rank (only when it exists)
-	if stat.CompetitiveRank != NoCompetitiveRank {
+	if spec.Rank.Show && stat.CompetitiveRank != NoCompetitiveRank {
 		var rankIcon image.Image
 		if rankIcon, err = getImage(stat.CompetitiveRankImageUrl); err == nil {
-			// resize it to fit in the banner
-			rankIcon = resize.Resize(BannerRankIconSize, BannerRankIconSize, rankIcon, resize.Lanczos3)
-
-			draw.Draw(
-				banner,
-				image.Rectangle{
-					Min: image.Point{X: BannerWidth - BannerHeight*2 - BannerRankIconSize, Y: 0},
-					Max: image.Point{X: BannerWidth - BannerHeight*2, Y: BannerRankIconSize},
-				},
-				rankIcon,
-				image.ZP,
-				draw.Over,
-			)
+			drawResized(banner, spec.Rank.Rect, rankIcon)
 		} else {
-			return nil, err
+			return err
 		}
-		context.SetFontSize(FontSizeRank)
-		if _, err = context.DrawString(
-			fmt.Sprintf("%4d", stat.CompetitiveRank),
-			freetype.Pt(
-				int(BannerWidth-BannerHeight*2.52),
-				int(context.PointToFixed(BannerHeight*0.86)>>6),
-			),
-		); err != nil {
-			return nil, err
+
+		if err = drawText(context, spec.Rank, fmt.Sprintf("%4d", stat.CompetitiveRank), 0); err != nil {
+			return err
 		}
 	}
-	return banner, nil
+
+	// the most played hero (only when it exists)
+	if spec.TopHero.Show {
+		if hero, exists := mostPlayedHero(stat); exists {
+			var heroImage image.Image
+			if heroImage, err = getImage(hero.ImageUrl); err == nil {
+				drawResized(banner, spec.TopHero.Rect, heroImage)
+			} else {
+				return err
+			}
+
+			if err = drawText(context, spec.TopHero, hero.Value, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	// endorsement (only when it exists)
+	if spec.Endorsement.Show && stat.EndorsementLevel >= 1 {
+		rect := spec.Endorsement.Rect
+		center := image.Point{
+			X: (rect.Min.X + rect.Max.X) / 2,
+			Y: (rect.Min.Y + rect.Max.Y) / 2,
+		}
+		drawEndorsement(banner, center, rect.Dx()/2, stat.EndorsementBreakdown, spec.BackgroundColor)
+
+		if err = drawText(context, spec.Endorsement, fmt.Sprintf("%d", stat.EndorsementLevel), 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// read image from given url
-func getImage(url string) (image.Image, error) {
-	if res, err := http.Get(url); err == nil {
-		defer res.Body.Close()
+// resize given image and draw it on given area
+func drawResized(dst draw.Image, rect image.Rectangle, img image.Image) {
+	img = resize.Resize(uint(rect.Dx()), uint(rect.Dy()), img, resize.Lanczos3)
+
+	draw.Draw(
+		dst,
+		rect,
+		img,
+		image.ZP,
+		draw.Over,
+	)
+}
+
+// draw text of given element (its baseline can be shifted vertically)
+func drawText(context *freetype.Context, element BannerElement, text string, shiftY int) error {
+	context.SetFontSize(element.FontSize)
+	_, err := context.DrawString(
+		text,
+		freetype.Pt(element.TextAt.X, element.TextAt.Y+shiftY),
+	)
+	return err
+}
+
+// draw a ring of endorsement ratios (clockwise from the top), filled with given color
+func drawEndorsement(dst *image.RGBA, center image.Point, radius int, endorsement Endorsement, fill color.Color) {
+	total := float64(endorsement.Shotcaller + endorsement.Teammate + endorsement.Sportsmanship)
+	ringWidth := radius / 3
+
+	drawPie(dst, center, radius-ringWidth, 0, []float64{1}, []color.Color{fill})
+	if total <= 0 {
+		drawPie(dst, center, radius, radius-ringWidth, []float64{1}, []color.Color{color.RGBA{128, 128, 128, 255}})
+	} else {
+		drawPie(dst, center, radius, radius-ringWidth, []float64{
+			float64(endorsement.Shotcaller) / total,
+			float64(endorsement.Teammate) / total,
+			float64(endorsement.Sportsmanship) / total,
+		}, []color.Color{colorShotcaller, colorTeammate, colorSportsmanship})
+	}
+}
+
+// draw a pie (or a ring, when innerRadius > 0) of given ratios and colors, clockwise from the top
+func drawPie(dst *image.RGBA, center image.Point, radius, innerRadius int, ratios []float64, colors []color.Color) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			distance := math.Sqrt(float64(x*x + y*y))
+			if distance > float64(radius) || (innerRadius > 0 && distance <= float64(innerRadius)) {
+				continue
+			}
+
+			// ratio of the angle, starting from 12 o'clock
+			angle := math.Atan2(float64(x), float64(-y)) / (2 * math.Pi)
+			if angle < 0 {
+				angle += 1.0
+			}
 
-		if img, _, err := image.Decode(res.Body); err == nil {
+			// find the segment of the angle (the last one, for rounding errors)
+			i, sum := 0, 0.0
+			for ; i < len(ratios)-1; i++ {
+				if sum += ratios[i]; angle < sum {
+					break
+				}
+			}
+			dst.Set(center.X+x, center.Y+y, colors[i])
+		}
+	}
+}
+
+// read image from given url (or from the asset cache)
+func getImage(url string) (image.Image, error) {
+	if asset, err := fetchAsset(url); err == nil {
+		if img, _, err := image.Decode(bytes.NewReader(asset.bytes)); err == nil {
 			return img, nil
 		} else {
 			return nil, err
@@ -596,17 +787,11 @@ func getImage(url string) (image.Image, error) {
 	}
 }
 
-// read ttf font from given url
+// read ttf font from given url (or from the asset cache)
 func getFont(url string) (*truetype.Font, error) {
-	if res, err := http.Get(url); err == nil {
-		defer res.Body.Close()
-
-		if bytes, err := ioutil.ReadAll(res.Body); err == nil {
-			if font, err := truetype.Parse(bytes); err == nil {
-				return font, nil
-			} else {
-				return nil, err
-			}
+	if asset, err := fetchAsset(url); err == nil {
+		if font, err := truetype.Parse(asset.bytes); err == nil {
+			return font, nil
 		} else {
 			return nil, err
 		}
@@ -0,0 +1,725 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+// order of roles in team reports (other roles follow them, in the order of members)
+var rosterRoles = []string{RoleTank, RoleDamage, RoleSupport, RoleFlex}
+
+const (
+	DefaultRosterConcurrency = 4                   // number of members fetched at the same time
+	DefaultInactiveAfter     = 14 * 24 * time.Hour // members are inactive when their stats haven't changed for this duration
+
+	HeroPoolMinTimePlayed = time.Hour // heroes played for this duration or more are in the player's hero pool
+	TeamReportTopHeroes   = 3         // number of top heroes of each member (and of each role) in team reports
+	RosterHistorySize     = 100       // max number of snapshots of each member in roster histories (see CompactSnapshots)
+)
+
+const (
+	SampleTeamHtmlTemplate = `<html lang="{{.Language}}">
+	<head>
+		<title>Overwatch: {{.Name}}</title>
+		<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+		<meta name="viewport" content="user-scalable=yes, initial-scale=1.0, maximum-scale=1.0, minimum-scale=1.0, width=device-width">
+		<style>
+			body {
+				display: block;
+				padding: 3px;
+				margin: 3px;
+				background-color: #405275;
+				color: #f0edf2;
+				font-family: Futura,century gothic,arial,sans-serif;
+			}
+			h1, h2 {
+				font-family: Koverwatch, sans-serif;
+			}
+			table {
+				border-collapse: collapse;
+			}
+			th, td {
+				padding: 4px 10px;
+				text-align: left;
+			}
+			td.number {
+				text-align: right;
+			}
+			img.profile {
+				width: 40px;
+				vertical-align: middle;
+			}
+			img.hero {
+				width: 30px;
+				vertical-align: middle;
+			}
+			.inactive, .error {
+				color: #f99e1a;
+			}
+			.uncovered {
+				color: #c0c8d8;
+			}
+		</style>
+	</head>
+	<body>
+		<h1>{{.Name}}</h1>
+		<table>
+			<tr><th>{{localize .Language "Ranked Members"}}</th><td class="number">{{.RankedMembers}}</td></tr>
+			{{if .RankedMembers}}
+				<tr><th>{{localize .Language "Average Rank"}}</th><td class="number">{{printf "%.0f" .AverageRank}}</td></tr>
+				<tr><th>{{localize .Language "Median Rank"}}</th><td class="number">{{printf "%.0f" .MedianRank}}</td></tr>
+				<tr><th>{{localize .Language "Rank Spread"}}</th><td class="number">{{.RankSpread}}</td></tr>
+			{{end}}
+			<tr><th>{{localize .Language "Hero Pool"}}</th><td class="number">{{.HeroPool.Covered}} / {{len .HeroPool.Heroes}}</td></tr>
+		</table>
+		{{range .Roles}}
+			<h2>{{.Role}}</h2>
+			<table>
+				{{range .Members}}{{with member $ .}}
+					<tr>
+						<td><img src="{{.ProfileImageUrl}}" class="profile"> {{.Name}}</td>
+						{{if .Error}}
+							<td class="error" colspan="4">{{.Error}}</td>
+						{{else}}
+							<td class="number">{{if ge .CompetitiveRank 0}}{{.CompetitiveRank}}{{else}}-{{end}}</td>
+							<td>{{.PrimaryRole}}</td>
+							<td>{{range .TopHeroes}}<img src="{{.ImageUrl}}" class="hero" title="{{.Name}}: {{.Value}}"> {{end}}</td>
+							<td>{{if .Inactive}}<span class="inactive">{{localize $.Language "Inactive since"}} {{.UnchangedSince.Format "2006-01-02"}}</span>{{end}}</td>
+						{{end}}
+					</tr>
+				{{end}}{{end}}
+			</table>
+		{{end}}
+		<h2>{{localize .Language "Hero Pool"}}</h2>
+		<table>
+			{{range .HeroPool.Heroes}}
+				<tr{{if not .Players}} class="uncovered"{{end}}>
+					<td><img src="{{.ImageUrl}}" class="hero"> {{.Name}}</td>
+					<td class="number">{{.TimePlayed}}</td>
+					<td>{{range $i, $p := .Players}}{{if $i}}, {{end}}{{$p}}{{end}}</td>
+				</tr>
+			{{end}}
+		</table>
+		{{if .HeroPool.Uncovered}}
+			<p class="uncovered">{{localize .Language "Uncovered"}}: {{range $i, $h := .HeroPool.Uncovered}}{{if $i}}, {{end}}{{$h}}{{end}}</p>
+		{{end}}
+	</body>
+</html>`
+)
+
+// sizes of team report images
+const (
+	TeamReportWidth        = 640
+	TeamReportHeaderHeight = 60
+	TeamReportRowHeight    = 44
+	TeamReportRoleHeight   = 24
+	TeamReportFooterHeight = 24
+
+	FontSizeTeamReport float64 = 14.0
+)
+
+// color of images which failed to load in team reports
+var colorPlaceholder = color.RGBA{84, 100, 132, 255}
+
+// a player in a roster
+type RosterMember struct {
+	Name      string `json:"name"`      // display name (default: battle tag)
+	BattleTag string `json:"battletag"` // eg. "meinside#3155" (or the id for consoles)
+	Platform  string `json:"platform"`
+	Region    string `json:"region"`
+	Language  string `json:"language"` // (should be the same for all members, as hero names are localized)
+	Role      string `json:"role"`     // eg. RoleTank (default: RoleFlex)
+}
+
+// a named set of players
+type Roster struct {
+	Name    string         `json:"name"`
+	Members []RosterMember `json:"members"`
+}
+
+// fetched stat of a roster member
+type RosterStat struct {
+	Member    RosterMember
+	Stat      Stat
+	FetchedAt time.Time
+	Err       error // (private profiles are not errors, see Stat.Private)
+}
+
+// report of a team
+type TeamReport struct {
+	Name     string       `json:"name"`
+	Time     time.Time    `json:"time"`     // when the stats were fetched
+	Language string       `json:"language"` // language of hero names (of the first fetched member)
+	Members  []TeamMember `json:"members"`
+
+	// competitive ranks of ranked members
+	RankedMembers int     `json:"ranked_members"`
+	AverageRank   float64 `json:"average_rank"`
+	MedianRank    float64 `json:"median_rank"`
+	RankSpread    int32   `json:"rank_spread"` // difference between the highest and the lowest ones
+
+	Roles    []TeamRole `json:"roles"` // members grouped by their roles
+	HeroPool HeroPool   `json:"hero_pool"`
+	Inactive []string   `json:"inactive"` // battle tags of inactive members
+}
+
+// a member in a team report
+type TeamMember struct {
+	Name            string            `json:"name"`
+	BattleTag       string            `json:"battletag"`
+	Role            string            `json:"role"`
+	PrimaryRole     string            `json:"primary_role"` // most played role of heroes (see RoleStats)
+	ProfileImageUrl string            `json:"profile_image_url"`
+	Level           int32             `json:"level"`
+	CompetitiveRank int32             `json:"competitive_rank"`
+	Private         bool              `json:"private"`
+	TopHeroes       []Hero            `json:"top_heroes"`                // most played heroes of the member's role (of any role for flex and others), with time played of quick and competitive play
+	TopHeroesByRole map[string][]Hero `json:"top_heroes_by_role"`        // most played heroes of each role in HeroRoles (only played roles)
+	UnchangedSince  *time.Time        `json:"unchanged_since,omitempty"` // since when level and time played haven't changed (only with history)
+	Inactive        bool              `json:"inactive"`
+	Error           string            `json:"error,omitempty"` // when failed to fetch
+}
+
+// battle tags of members with a role
+type TeamRole struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+}
+
+// heroes played by members
+type HeroPool struct {
+	Heroes    []HeroPoolHero `json:"heroes"`    // ordered by number of players, then total time played
+	Covered   int            `json:"covered"`   // number of heroes in anyone's hero pool
+	Uncovered []string       `json:"uncovered"` // heroes in nobody's hero pool (including ones in Heroes which nobody played)
+}
+
+// a hero in the hero pool
+type HeroPoolHero struct {
+	Name       string   `json:"name"`
+	ImageUrl   string   `json:"image_url"`
+	TimePlayed string   `json:"time_played"` // total of all members
+	Players    []string `json:"players"`     // names of members who played it for HeroPoolMinTimePlayed or more
+}
+
+// a hero with its time played
+type heroTime struct {
+	hero     Hero
+	duration time.Duration
+}
+
+// display name of the member
+func (m RosterMember) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.BattleTag
+}
+
+// fetch stats of all members of given roster concurrently (at most concurrency ones at the same time)
+//
+// results are in the order of members
+func FetchRoster(roster Roster, concurrency int) []RosterStat {
+	if concurrency <= 0 {
+		concurrency = DefaultRosterConcurrency
+	}
+
+	results := make([]RosterStat, len(roster.Members))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, member := range roster.Members {
+		wg.Add(1)
+		go func(i int, member RosterMember) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = fetchRosterMember(member)
+		}(i, member)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// fetch stat of a roster member
+func fetchRosterMember(member RosterMember) (result RosterStat) {
+	result.Member = member
+
+	var name string
+	var number int
+	if name, number, result.Err = ParseBattleTag(member.BattleTag, member.Platform); result.Err != nil {
+		return result
+	}
+
+	result.Stat, result.Err = FetchStat(name, number, member.Platform, member.Region, member.Language)
+	result.FetchedAt = time.Now()
+	if result.Err == ErrPrivateProfile {
+		result.Err = nil
+	}
+	return result
+}
+
+// build a report of a team from fetched stats of its members
+//
+// history has previous snapshots of members (keyed by battle tags, eg. saved from earlier reports) for checking inactivity:
+// members whose level and time played haven't changed for inactiveAfter are inactive
+func NewTeamReport(name string, stats []RosterStat, history map[string][]Snapshot, inactiveAfter time.Duration) TeamReport {
+	report := TeamReport{
+		Name:     name,
+		Members:  []TeamMember{},
+		Roles:    []TeamRole{},
+		Inactive: []string{},
+	}
+	for _, s := range stats {
+		if s.FetchedAt.After(report.Time) {
+			report.Time = s.FetchedAt
+		}
+		if report.Language == "" && s.Err == nil {
+			report.Language = s.Stat.Language
+		}
+	}
+
+	ranks := []int32{}
+	pool := heroPool{indices: map[string]int{}}
+	roles := map[string][]string{}
+	otherRoles := []string{}
+	for _, s := range stats {
+		member := TeamMember{
+			Name:            s.Member.DisplayName(),
+			BattleTag:       s.Member.BattleTag,
+			Role:            strings.ToLower(s.Member.Role),
+			TopHeroes:       []Hero{},
+			TopHeroesByRole: map[string][]Hero{},
+		}
+		if member.Role == "" {
+			member.Role = RoleFlex
+		}
+		if _, exists := roles[member.Role]; !exists && !containsLabel(rosterRoles, member.Role) {
+			otherRoles = append(otherRoles, member.Role)
+		}
+		roles[member.Role] = append(roles[member.Role], member.BattleTag)
+
+		if s.Err != nil {
+			member.Error = s.Err.Error()
+			member.CompetitiveRank = NoCompetitiveRank
+			report.Members = append(report.Members, member)
+			continue
+		}
+
+		member.ProfileImageUrl = s.Stat.ProfileImageUrl
+		member.Level = s.Stat.Level
+		member.CompetitiveRank = s.Stat.CompetitiveRank
+		member.Private = s.Stat.Private
+		member.PrimaryRole = NewRoleStats(s.Stat.QuickPlay, s.Stat.CompetitivePlay).PrimaryRole
+		if member.CompetitiveRank != NoCompetitiveRank {
+			ranks = append(ranks, member.CompetitiveRank)
+		}
+
+		times := heroTimesPlayed(s.Stat)
+		member.TopHeroes = topHeroesOfRole(times, member.Role)
+		for _, role := range HeroRoles {
+			if heroes := topHeroesOfRole(times, role); len(heroes) > 0 {
+				member.TopHeroesByRole[role] = heroes
+			}
+		}
+		for _, t := range times {
+			pool.add(t, member.Name)
+		}
+
+		if snapshots, exists := history[s.Member.BattleTag]; exists && len(snapshots) > 0 {
+			since := unchangedSince(snapshots, Snapshot{Time: s.FetchedAt, Stat: s.Stat})
+			member.UnchangedSince = &since
+			if s.FetchedAt.Sub(since) >= inactiveAfter {
+				member.Inactive = true
+				report.Inactive = append(report.Inactive, member.BattleTag)
+			}
+		}
+
+		report.Members = append(report.Members, member)
+	}
+
+	// competitive ranks
+	if len(ranks) > 0 {
+		sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+
+		sum := 0.0
+		for _, rank := range ranks {
+			sum += float64(rank)
+		}
+		report.RankedMembers = len(ranks)
+		report.AverageRank = sum / float64(len(ranks))
+		if middle := len(ranks) / 2; len(ranks)%2 == 0 {
+			report.MedianRank = float64(ranks[middle-1]+ranks[middle]) / 2
+		} else {
+			report.MedianRank = float64(ranks[middle])
+		}
+		report.RankSpread = ranks[len(ranks)-1] - ranks[0]
+	}
+
+	// roles
+	for _, role := range append(append([]string{}, rosterRoles...), otherRoles...) {
+		if battleTags, exists := roles[role]; exists {
+			report.Roles = append(report.Roles, TeamRole{Role: role, Members: battleTags})
+		}
+	}
+
+	report.HeroPool = pool.build(report.Language)
+
+	return report
+}
+
+// find a member of given battle tag in the report (case-insensitive)
+func (r TeamReport) Member(battleTag string) (TeamMember, bool) {
+	for _, member := range r.Members {
+		if strings.EqualFold(member.BattleTag, battleTag) {
+			return member, true
+		}
+	}
+	return TeamMember{}, false
+}
+
+// heroes played by members, in the order of appearance
+type heroPool struct {
+	heroes    []HeroPoolHero
+	durations []time.Duration
+	indices   map[string]int
+}
+
+// add time played of a hero by a member
+func (p *heroPool) add(t heroTime, memberName string) {
+	i, exists := p.indices[t.hero.Name]
+	if !exists {
+		i = len(p.heroes)
+		p.indices[t.hero.Name] = i
+		p.heroes = append(p.heroes, HeroPoolHero{Name: t.hero.Name, ImageUrl: t.hero.ImageUrl, Players: []string{}})
+		p.durations = append(p.durations, 0)
+	}
+
+	p.durations[i] += t.duration
+	if t.duration >= HeroPoolMinTimePlayed {
+		p.heroes[i].Players = append(p.heroes[i].Players, memberName)
+	}
+}
+
+// build the hero pool, ordered by number of players and total time played
+//
+// (heroes in Heroes which nobody played are also uncovered, with their names in given language)
+func (p *heroPool) build(language string) HeroPool {
+	pool := HeroPool{Heroes: []HeroPoolHero{}, Uncovered: []string{}}
+
+	indices := make([]int, len(p.heroes))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := indices[i], indices[j]
+		if len(p.heroes[a].Players) != len(p.heroes[b].Players) {
+			return len(p.heroes[a].Players) > len(p.heroes[b].Players)
+		}
+		return p.durations[a] > p.durations[b]
+	})
+
+	for _, i := range indices {
+		hero := p.heroes[i]
+		hero.TimePlayed = formatDuration(p.durations[i])
+		pool.Heroes = append(pool.Heroes, hero)
+
+		if len(hero.Players) > 0 {
+			pool.Covered++
+		} else {
+			pool.Uncovered = append(pool.Uncovered, hero.Name)
+		}
+	}
+
+	// heroes which nobody played
+	played := map[string]bool{}
+	for _, hero := range p.heroes {
+		if info, exists := HeroInfoOf(Hero{Name: hero.Name, ImageUrl: hero.ImageUrl}); exists {
+			played[info.Id] = true
+		}
+	}
+	for _, info := range Heroes {
+		if !played[info.Id] {
+			pool.Uncovered = append(pool.Uncovered, info.LocalizedName(language))
+		}
+	}
+
+	return pool
+}
+
+// heroes with time played of both quick and competitive play, ordered by time played
+func heroTimesPlayed(stat Stat) []heroTime {
+	times := []heroTime{}
+	indices := map[string]int{}
+	for _, playStat := range []PlayStat{stat.QuickPlay, stat.CompetitivePlay} {
+		for _, hero := range timePlayedHeroesOf(playStat) {
+			duration, _ := ParseDuration(hero.Value)
+			if i, exists := indices[hero.Name]; exists {
+				times[i].duration += duration
+			} else {
+				indices[hero.Name] = len(times)
+				times = append(times, heroTime{hero: hero, duration: duration})
+			}
+		}
+	}
+
+	sort.SliceStable(times, func(i, j int) bool {
+		return times[i].duration > times[j].duration
+	})
+	return times
+}
+
+// top heroes of given role with their time played, from heroes ordered by time played
+//
+// (heroes of any role when given role is not one of HeroRoles, eg. RoleFlex)
+func topHeroesOfRole(times []heroTime, role string) []Hero {
+	anyRole := !containsLabel(HeroRoles, role)
+
+	heroes := []Hero{}
+	for _, t := range times {
+		if len(heroes) >= TeamReportTopHeroes || t.duration <= 0 {
+			break
+		}
+		if info, exists := HeroInfoOf(t.hero); anyRole || (exists && info.Role == role) {
+			heroes = append(heroes, Hero{Name: t.hero.Name, ImageUrl: t.hero.ImageUrl, Value: formatDuration(t.duration)})
+		}
+	}
+	return heroes
+}
+
+// since when level and time played of the player haven't changed, with given snapshots and the current one
+func unchangedSince(snapshots []Snapshot, current Snapshot) time.Time {
+	since, activity := current.Time, activityOf(current.Stat)
+
+	sorted := sortedSnapshots(snapshots)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].Time.After(current.Time) {
+			continue
+		}
+		if activityOf(sorted[i].Stat) != activity {
+			break
+		}
+		since = sorted[i].Time
+	}
+	return since
+}
+
+// compact snapshots of a member in a roster history, keeping at most max ones (the latest ones, unlimited when max <= 0)
+//
+// snapshots whose level and time played haven't changed since the previous one are dropped (except the latest one),
+// as only the first ones of unchanged snapshots are needed for checking inactivity
+func CompactSnapshots(snapshots []Snapshot, max int) []Snapshot {
+	sorted := sortedSnapshots(snapshots)
+
+	compacted := []Snapshot{}
+	for i, snapshot := range sorted {
+		if i > 0 && i < len(sorted)-1 && activityOf(snapshot.Stat) == activityOf(sorted[i-1].Stat) {
+			continue
+		}
+		compacted = append(compacted, snapshot)
+	}
+	if max > 0 && len(compacted) > max {
+		compacted = compacted[len(compacted)-max:]
+	}
+	return compacted
+}
+
+// level and total time played of the player, which changes after playing
+func activityOf(stat Stat) string {
+	var total time.Duration
+	for _, t := range heroTimesPlayed(stat) {
+		total += t.duration
+	}
+	return fmt.Sprintf("%d/%d", stat.Level, total)
+}
+
+// render given team report to .html format, using template with HtmlFuncMap
+//
+// (in templates, `member $ battleTag` returns the member of given battle tag)
+func RenderTeamReportToHtml(report TeamReport, templateStr string) (result string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = template.New("team").Funcs(HtmlFuncMap).Funcs(template.FuncMap{
+		"member": func(report TeamReport, battleTag string) (TeamMember, error) {
+			if member, exists := report.Member(battleTag); exists {
+				return member, nil
+			}
+			return TeamMember{}, fmt.Errorf("no such member: %s", battleTag)
+		},
+	}).Parse(templateStr); err == nil {
+		var buffer bytes.Buffer
+		if err = tmpl.Execute(&buffer, report); err == nil {
+			return buffer.String(), nil
+		}
+	}
+	return "", err
+}
+
+// return bytes of given team report rendered in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderTeamReportToPngBytes(report TeamReport, font *truetype.Font) ([]byte, error) {
+	if image, err := genTeamReportImage(report, font); err == nil {
+		imgBytes := new(bytes.Buffer)
+		if err := png.Encode(imgBytes, image); err == nil {
+			return imgBytes.Bytes(), nil
+		} else {
+			return []byte{}, err
+		}
+	} else {
+		return []byte{}, err
+	}
+}
+
+// render given team report to a file in .png format
+//
+// - when font is nil: it will be loaded from KoverwatchFontUrl
+func RenderTeamReportToPngFile(report TeamReport, font *truetype.Font, outFilepath string) error {
+	if bytes, err := RenderTeamReportToPngBytes(report, font); err == nil {
+		return ioutil.WriteFile(outFilepath, bytes, 0640)
+	} else {
+		return err
+	}
+}
+
+// generate an image of given team report, with colors of DefaultBannerSpec
+//
+// (team summary, members grouped by roles with their ranks and top heroes, and uncovered heroes)
+//
+// images which failed to load are drawn as placeholders
+func genTeamReportImage(report TeamReport, font *truetype.Font) (result *image.RGBA, err error) {
+	// load .ttf font
+	if font == nil {
+		if font, err = getFont(KoverwatchFontUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	height := TeamReportHeaderHeight + TeamReportRoleHeight*len(report.Roles) + TeamReportRowHeight*len(report.Members) + TeamReportFooterHeight + Margin
+	img := image.NewRGBA(image.Rect(0, 0, TeamReportWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{DefaultBannerSpec.BackgroundColor}, image.ZP, draw.Src)
+
+	context := freetype.NewContext()
+	context.SetFont(font)
+	context.SetDPI(72)
+	context.SetDst(img)
+
+	textColor := &image.Uniform{DefaultBannerSpec.TextColor}
+	highlightColor := &image.Uniform{ColorLeader}
+
+	// draws given text in given area, with given color
+	drawText := func(rect image.Rectangle, text string, fontSize float64, c *image.Uniform) error {
+		context.SetSrc(c)
+		context.SetClip(rect)
+		return drawFittedString(context, rect, text, fontSize, int(float64(rect.Dy())/2+fontSize/3))
+	}
+
+	// draws an image of given url in given area, or a placeholder when it failed to load
+	drawImage := func(rect image.Rectangle, url string) {
+		if loaded, err := getImage(url); err == nil {
+			drawResized(img, rect, loaded)
+		} else {
+			if Verbose {
+				log.Printf("> failed to load image for team report: %s\n", err)
+			}
+			draw.Draw(img, rect, &image.Uniform{colorPlaceholder}, image.ZP, draw.Src)
+		}
+	}
+
+	localize := func(label string) string {
+		return Localize(report.Language, label)
+	}
+
+	// header: name and summary
+	summary := fmt.Sprintf("%s: %d / %d", localize("Hero Pool"), report.HeroPool.Covered, len(report.HeroPool.Heroes))
+	if report.RankedMembers > 0 {
+		summary = fmt.Sprintf("%s: %.0f  %s: %.0f  %s: %d  %s", localize("Average Rank"), report.AverageRank, localize("Median Rank"), report.MedianRank, localize("Rank Spread"), report.RankSpread, summary)
+	}
+	if err = drawText(image.Rect(Margin*2, Margin, TeamReportWidth-Margin*2, TeamReportHeaderHeight/2+Margin), report.Name, FontSizeTeamReport+8, textColor); err != nil {
+		return nil, err
+	}
+	if err = drawText(image.Rect(Margin*2, TeamReportHeaderHeight/2, TeamReportWidth-Margin*2, TeamReportHeaderHeight-Margin), summary, FontSizeTeamReport, textColor); err != nil {
+		return nil, err
+	}
+
+	// members grouped by roles
+	y := TeamReportHeaderHeight
+	for _, role := range report.Roles {
+		if err = drawText(image.Rect(Margin*2, y, TeamReportWidth-Margin*2, y+TeamReportRoleHeight), strings.ToUpper(role.Role), FontSizeTeamReport, highlightColor); err != nil {
+			return nil, err
+		}
+		y += TeamReportRoleHeight
+
+		for _, battleTag := range role.Members {
+			member, _ := report.Member(battleTag)
+			size := TeamReportRowHeight - Margin*2
+
+			// portrait
+			if member.ProfileImageUrl != "" {
+				drawImage(image.Rect(Margin*2, y+Margin, Margin*2+size, y+Margin+size), member.ProfileImageUrl)
+			}
+
+			// name, rank, and top heroes (or error)
+			x := Margin*4 + size
+			if err = drawText(image.Rect(x, y, x+200, y+TeamReportRowHeight), member.Name, FontSizeTeamReport, textColor); err != nil {
+				return nil, err
+			}
+			x += 200 + Margin*2
+			if member.Error != "" {
+				if err = drawText(image.Rect(x, y, TeamReportWidth-Margin*2, y+TeamReportRowHeight), member.Error, FontSizeTeamReport, highlightColor); err != nil {
+					return nil, err
+				}
+				y += TeamReportRowHeight
+				continue
+			}
+
+			rank := NoValue
+			if member.CompetitiveRank != NoCompetitiveRank {
+				rank = fmt.Sprintf("%d", member.CompetitiveRank)
+			}
+			if err = drawText(image.Rect(x, y, x+60, y+TeamReportRowHeight), rank, FontSizeTeamReport, textColor); err != nil {
+				return nil, err
+			}
+			x += 60 + Margin*2
+
+			for _, hero := range member.TopHeroes {
+				drawImage(image.Rect(x, y+Margin, x+size, y+Margin+size), hero.ImageUrl)
+				x += size + Margin
+			}
+
+			if member.Inactive {
+				if err = drawText(image.Rect(x+Margin*2, y, TeamReportWidth-Margin*2, y+TeamReportRowHeight), localize("Inactive"), FontSizeTeamReport, highlightColor); err != nil {
+					return nil, err
+				}
+			}
+
+			y += TeamReportRowHeight
+		}
+	}
+
+	// footer: uncovered heroes
+	footer := localize("Uncovered") + ": " + NoValue
+	if len(report.HeroPool.Uncovered) > 0 {
+		footer = localize("Uncovered") + ": " + strings.Join(report.HeroPool.Uncovered, ", ")
+	}
+	if err = drawText(image.Rect(Margin*2, y, TeamReportWidth-Margin*2, y+TeamReportFooterHeight), footer, FontSizeTeamReport, textColor); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
@@ -0,0 +1,231 @@
+package stat_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meinside/overwatch-go/stat"
+	"github.com/meinside/overwatch-go/stattest"
+)
+
+func TestFetchRoster(t *testing.T) {
+	stattest.UseServer(t)
+
+	member := func(f stattest.Fixture, role string) stat.RosterMember {
+		battleTag := f.BattleTagString
+		if f.Platform == stat.PlatformPc {
+			battleTag = fmt.Sprintf("%s#%d", f.BattleTagString, f.BattleTagNumber)
+		}
+		return stat.RosterMember{BattleTag: battleTag, Platform: f.Platform, Region: f.Region, Language: f.Language, Role: role}
+	}
+	roster := stat.Roster{
+		Name: "test",
+		Members: []stat.RosterMember{
+			member(stattest.FixturePcCompetitive, stat.RoleTank),
+			member(stattest.FixturePcNoCompetitive, stat.RoleSupport),
+			member(stattest.FixturePcPrivate, stat.RoleDamage),
+			{BattleTag: "nobody#1", Platform: stat.PlatformPc, Region: "us", Language: "en-us"},
+			{BattleTag: "malformed", Platform: stat.PlatformPc, Region: "us", Language: "en-us"},
+		},
+	}
+
+	results := stat.FetchRoster(roster, 2)
+	if len(results) != len(roster.Members) {
+		t.Fatalf("expected %d results, got %d", len(roster.Members), len(results))
+	}
+	for i, result := range results[:3] {
+		if result.Err != nil || result.Member != roster.Members[i] || result.FetchedAt.IsZero() {
+			t.Errorf("unexpected result of %s: %+v", roster.Members[i].BattleTag, result.Err)
+		}
+	}
+	if !results[2].Stat.Private {
+		t.Errorf("private profile should be fetched as private")
+	}
+	if results[3].Err != stat.ErrPlayerNotFound || results[4].Err == nil {
+		t.Errorf("expected errors for unknown and malformed players, got: %v, %v", results[3].Err, results[4].Err)
+	}
+
+	report := stat.NewTeamReport(roster.Name, results, nil, stat.DefaultInactiveAfter)
+	if len(report.Members) != len(roster.Members) || report.Members[3].Error == "" || report.RankedMembers != 1 {
+		t.Errorf("unexpected team report: %+v", report)
+	}
+}
+
+func TestNewTeamReport(t *testing.T) {
+	server := newImageServer(t)
+	now := time.Date(2018, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	// (genji is not in anyone's hero pool)
+	competitive := func(rank int32) stat.Stat {
+		s := statWithLocalImages(t, stattest.FixturePcCompetitive, server.URL)
+		s.CompetitiveRank = rank
+		for _, comparison := range s.QuickPlay.TopHeroes {
+			for i, hero := range comparison.Heroes {
+				if hero.Name == "Genji" {
+					comparison.Heroes[i].Value = "20 minutes"
+				}
+			}
+		}
+		return s
+	}
+	a, c, d := competitive(3537), competitive(3000), competitive(2500)
+	b := statWithLocalImages(t, stattest.FixturePcNoCompetitive, server.URL) // no sr
+
+	stats := []stat.RosterStat{
+		{Member: stat.RosterMember{Name: "A", BattleTag: "a#1", Role: stat.RoleDamage}, Stat: a, FetchedAt: now},
+		{Member: stat.RosterMember{Name: "B", BattleTag: "b#1", Role: "Support"}, Stat: b, FetchedAt: now},
+		{Member: stat.RosterMember{BattleTag: "c#1", Role: stat.RoleTank}, Stat: c, FetchedAt: now},
+		{Member: stat.RosterMember{Name: "D", BattleTag: "d#1"}, Stat: d, FetchedAt: now},
+		{Member: stat.RosterMember{Name: "E", BattleTag: "e#1", Role: "coach"}, Err: stat.ErrPlayerNotFound},
+	}
+
+	// a hasn't played for 30 days, b has leveled up since then
+	old := statWithLocalImages(t, stattest.FixturePcNoCompetitive, server.URL)
+	old.Level--
+	history := map[string][]stat.Snapshot{
+		"a#1": {{Time: now.AddDate(0, 0, -30), Stat: a}, {Time: now.AddDate(0, 0, -20), Stat: a}},
+		"b#1": {{Time: now.AddDate(0, 0, -30), Stat: old}},
+	}
+
+	report := stat.NewTeamReport("team", stats, history, stat.DefaultInactiveAfter)
+
+	if !report.Time.Equal(now) || report.RankedMembers != 3 || report.MedianRank != 3000 || report.RankSpread != 1037 || int(report.AverageRank) != 3012 {
+		t.Errorf("unexpected ranks: %d members, average %f, median %f, spread %d", report.RankedMembers, report.AverageRank, report.MedianRank, report.RankSpread)
+	}
+
+	// roles: known ones first, then others
+	roles := []string{}
+	for _, role := range report.Roles {
+		roles = append(roles, role.Role+":"+strings.Join(role.Members, ","))
+	}
+	if expected := "tank:c#1,damage:a#1,support:b#1,flex:d#1,coach:e#1"; strings.Join(roles, ",") != expected {
+		t.Errorf("expected roles %s, got %s", expected, strings.Join(roles, ","))
+	}
+
+	// members by battle tags (case-insensitive)
+	if member, exists := report.Member("A#1"); !exists || member.Name != "A" {
+		t.Errorf("expected member A of a#1, got: %+v", member)
+	}
+	if _, exists := report.Member("A"); exists {
+		t.Errorf("members should not be found by their names")
+	}
+
+	// top heroes of roles with time played of quick and competitive play
+	if member, _ := report.Member("a#1"); len(member.TopHeroes) != 3 || member.TopHeroes[0].Name != "Soldier: 76" || member.TopHeroes[0].Value != "53h" || member.TopHeroes[1].Name != "Reaper" {
+		t.Errorf("unexpected top damage heroes of A: %+v", member.TopHeroes)
+	}
+	if member, _ := report.Member("a#1"); len(member.TopHeroesByRole) != len(stat.HeroRoles) || member.TopHeroesByRole[stat.RoleSupport][0].Name != "Ana" || member.TopHeroesByRole[stat.RoleDamage][0].Name != "Soldier: 76" {
+		t.Errorf("unexpected top heroes by role of A: %+v", member.TopHeroesByRole)
+	}
+	if member, _ := report.Member("d#1"); len(member.TopHeroes) != 3 || member.TopHeroes[1].Name != "Ana" {
+		t.Errorf("top heroes of flex members should be of any role: %+v", member.TopHeroes)
+	}
+	if member, _ := report.Member("e#1"); member.Error == "" || member.CompetitiveRank != stat.NoCompetitiveRank {
+		t.Errorf("E should have an error: %+v", member)
+	}
+
+	// inactivity
+	if len(report.Inactive) != 1 || report.Inactive[0] != "a#1" {
+		t.Errorf("expected only A to be inactive, got: %v", report.Inactive)
+	}
+	if member, _ := report.Member("a#1"); member.UnchangedSince == nil || !member.UnchangedSince.Equal(now.AddDate(0, 0, -30)) {
+		t.Errorf("unexpected unchanged time of A: %v", member.UnchangedSince)
+	}
+	if member, _ := report.Member("b#1"); member.UnchangedSince == nil || !member.UnchangedSince.Equal(now) || member.Inactive {
+		t.Errorf("B should be active: %+v", member)
+	}
+	if member, _ := report.Member("d#1"); member.UnchangedSince != nil {
+		t.Errorf("D has no history, so its activity should be unknown")
+	}
+
+	// hero pool
+	if report.HeroPool.Covered != len(report.HeroPool.Heroes)-1 || report.HeroPool.Covered+len(report.HeroPool.Uncovered) != len(stat.Heroes) || report.HeroPool.Uncovered[0] != "Genji" {
+		t.Errorf("unexpected hero pool: %+v", report.HeroPool)
+	}
+	if uncovered := strings.Join(report.HeroPool.Uncovered, ","); !strings.Contains(uncovered, "Tracer") || strings.Contains(uncovered, "Ana") {
+		t.Errorf("heroes which nobody played should be uncovered: %s", uncovered)
+	}
+	if member, _ := report.Member("a#1"); member.PrimaryRole != stat.RoleSupport {
+		t.Errorf("unexpected primary role of A: %s", member.PrimaryRole)
+	}
+	if first := report.HeroPool.Heroes[0]; len(first.Players) != 4 {
+		t.Errorf("the first hero of the pool should be played by all members: %+v", first)
+	}
+
+	// html
+	html, err := stat.RenderTeamReportToHtml(report, stat.SampleTeamHtmlTemplate)
+	if err != nil {
+		t.Fatalf("failed to render team report to html: %s", err)
+	}
+	for _, expected := range []string{"<h2>tank</h2>", `lang="en-us"`, "Ranked Members", "Inactive since 2018-05-31", "player not found", `class="uncovered"`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected '%s' in html", expected)
+		}
+	}
+
+	// png
+	pngBytes, err := stat.RenderTeamReportToPngBytes(report, testFont(t))
+	if err != nil {
+		t.Fatalf("failed to render team report to png: %s", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngBytes))
+	if err != nil {
+		t.Fatalf("failed to decode team report png: %s", err)
+	}
+	if height := stat.TeamReportHeaderHeight + stat.TeamReportRoleHeight*5 + stat.TeamReportRowHeight*5 + stat.TeamReportFooterHeight + stat.Margin; img.Bounds().Dy() != height {
+		t.Errorf("expected height %d, got %d", height, img.Bounds().Dy())
+	}
+
+	// images which failed to load are drawn as placeholders
+	missing := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(missing.Close)
+	report.Members[0].ProfileImageUrl = missing.URL + "/portrait.png"
+	report.Members[0].TopHeroes[0].ImageUrl = missing.URL + "/hero.png"
+	if _, err := stat.RenderTeamReportToPngBytes(report, testFont(t)); err != nil {
+		t.Errorf("failed images should not fail team report png: %s", err)
+	}
+
+	// localized
+	report.Language = "ko-kr"
+	if html, err := stat.RenderTeamReportToHtml(report, stat.SampleTeamHtmlTemplate); err != nil || !strings.Contains(html, "점수 있는 멤버") || !strings.Contains(html, "비활동 시작 2018-05-31") {
+		t.Errorf("team report html should be localized: %v", err)
+	}
+}
+
+func TestCompactSnapshots(t *testing.T) {
+	s := stattest.FixturePcNoCompetitive.Stat(t)
+	leveled := s
+	leveled.Level++
+
+	now := time.Date(2018, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return now.AddDate(0, 0, d) }
+	snapshots := []stat.Snapshot{
+		{Time: day(2), Stat: s}, // (not sorted)
+		{Time: day(0), Stat: s},
+		{Time: day(1), Stat: s},
+		{Time: day(3), Stat: leveled},
+		{Time: day(4), Stat: leveled},
+		{Time: day(5), Stat: leveled},
+	}
+
+	// only the first ones of unchanged snapshots, and the latest one are kept
+	times := func(snapshots []stat.Snapshot) string {
+		days := []string{}
+		for _, snapshot := range snapshots {
+			days = append(days, fmt.Sprintf("%d", int(snapshot.Time.Sub(now).Hours()/24)))
+		}
+		return strings.Join(days, ",")
+	}
+	if compacted := times(stat.CompactSnapshots(snapshots, 0)); compacted != "0,3,5" {
+		t.Errorf("unexpected compacted snapshots: %s", compacted)
+	}
+	if compacted := times(stat.CompactSnapshots(snapshots, 2)); compacted != "3,5" {
+		t.Errorf("only the latest snapshots should be kept: %s", compacted)
+	}
+}
@@ -0,0 +1,227 @@
+package stat
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"io/ioutil"
+	"math"
+	"strings"
+)
+
+// style of svg banners (can be overridden with css, using these classes)
+const svgBannerStyle = `
+		@font-face { font-family: Koverwatch; src: url(%s); }
+		text { font-family: Koverwatch, sans-serif; white-space: pre; }
+		.background { fill: %s; }
+		.battletag, .detail, .level-text, .rank-text, .top-hero-text, .endorsement-text { fill: %s; }
+		.endorsement-fill { fill: %s; }
+		.endorsement-shotcaller { stroke: %s; }
+		.endorsement-teammate { stroke: %s; }
+		.endorsement-sportsmanship { stroke: %s; }
+		.endorsement-none { stroke: #808080; }
+	`
+
+// render given stat to a banner in .svg format, with given layout and colors
+//
+// - logo will be loaded from spec's LogoImageUrl (or OverwatchLogoImageUrl)
+// - when embedImages is true: images will be embedded as data uris (otherwise, linked with their urls)
+func RenderStatToSvg(stat Stat, spec BannerSpec, embedImages bool) (result string, err error) {
+	imageHref := func(url string) (string, error) {
+		if embedImages {
+			return getDataUri(url)
+		}
+		return url, nil
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", spec.Width, spec.Height, spec.Width, spec.Height)
+
+	// style
+	fmt.Fprintf(&b, "\t<style>%s</style>\n", fmt.Sprintf(svgBannerStyle,
+		KoverwatchFontUrl,
+		cssColor(spec.BackgroundColor),
+		cssColor(spec.TextColor),
+		cssColor(spec.BackgroundColor),
+		cssColor(colorShotcaller),
+		cssColor(colorTeammate),
+		cssColor(colorSportsmanship),
+	))
+
+	// background
+	fmt.Fprintf(&b, "\t<rect class=\"background\" x=\"0\" y=\"0\" width=\"%d\" height=\"%d\"/>\n", spec.Width, spec.Height)
+
+	// logo image
+	if spec.Logo.Show {
+		var href string
+		if href, err = imageHref(spec.logoImageUrl()); err != nil {
+			return "", err
+		}
+		writeSvgImage(&b, "logo", spec.Logo.Rect, href)
+	}
+
+	// profile image
+	if spec.Portrait.Show {
+		var href string
+		if href, err = imageHref(stat.ProfileImageUrl); err != nil {
+			return "", err
+		}
+		writeSvgImage(&b, "portrait", spec.Portrait.Rect, href)
+	}
+
+	// battletag, platform, and region
+	if spec.BattleTag.Show {
+		var label string
+		if strings.EqualFold(stat.Platform, PlatformPc) {
+			label = fmt.Sprintf("%s  %s/%s", stat.BattleTag, stat.Platform, stat.Region)
+		} else {
+			label = fmt.Sprintf("%s / %s", stat.BattleTag, stat.Platform)
+		}
+		writeSvgText(&b, "battletag", spec.BattleTag, label, 0)
+	}
+
+	// detail
+	if spec.Detail.Show {
+		writeSvgText(&b, "detail", spec.Detail, stat.Detail, 0)
+	}
+
+	// level stars (if exists), level, and level bg
+	if spec.Level.Show {
+		levelRect := spec.Level.Rect
+		levelTextShiftY := 0
+		if stat.LevelStarImageUrl != "" {
+			// shift level bg and text up, for making room for the stars
+			shift := -int(float64(levelRect.Dy()) * 0.1)
+			levelRect = levelRect.Add(image.Pt(0, shift))
+			levelTextShiftY = shift
+
+			var href string
+			if href, err = imageHref(stat.LevelStarImageUrl); err != nil {
+				return "", err
+			}
+			writeSvgImage(&b, "level-stars", image.Rect(
+				spec.Level.Rect.Min.X,
+				spec.Level.Rect.Min.Y+spec.Level.Rect.Dy()/2,
+				spec.Level.Rect.Max.X,
+				spec.Level.Rect.Max.Y,
+			), href)
+		}
+
+		var href string
+		if href, err = imageHref(stat.LevelImageUrl); err != nil {
+			return "", err
+		}
+		writeSvgImage(&b, "level", levelRect, href)
+		writeSvgText(&b, "level-text", spec.Level, fmt.Sprintf("%3d", stat.Level), levelTextShiftY)
+	}
+
+	// rank (only when it exists)
+	if spec.Rank.Show && stat.CompetitiveRank != NoCompetitiveRank {
+		var href string
+		if href, err = imageHref(stat.CompetitiveRankImageUrl); err != nil {
+			return "", err
+		}
+		writeSvgImage(&b, "rank", spec.Rank.Rect, href)
+		writeSvgText(&b, "rank-text", spec.Rank, fmt.Sprintf("%4d", stat.CompetitiveRank), 0)
+	}
+
+	// the most played hero (only when it exists)
+	if spec.TopHero.Show {
+		if hero, exists := mostPlayedHero(stat); exists {
+			var href string
+			if href, err = imageHref(hero.ImageUrl); err != nil {
+				return "", err
+			}
+			writeSvgImage(&b, "top-hero", spec.TopHero.Rect, href)
+			writeSvgText(&b, "top-hero-text", spec.TopHero, hero.Value, 0)
+		}
+	}
+
+	// endorsement (only when it exists)
+	if spec.Endorsement.Show && stat.EndorsementLevel >= 1 {
+		writeSvgEndorsement(&b, spec.Endorsement.Rect, stat.EndorsementBreakdown)
+		writeSvgText(&b, "endorsement-text", spec.Endorsement, fmt.Sprintf("%d", stat.EndorsementLevel), 0)
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.String(), nil
+}
+
+// render given stat to a banner file in .svg format, with given layout and colors
+//
+// - when embedImages is true: images will be embedded as data uris (otherwise, linked with their urls)
+func RenderStatToSvgFile(stat Stat, spec BannerSpec, embedImages bool, outFilepath string) error {
+	if svg, err := RenderStatToSvg(stat, spec, embedImages); err == nil {
+		return ioutil.WriteFile(outFilepath, []byte(svg), 0640)
+	} else {
+		return err
+	}
+}
+
+func writeSvgImage(b *strings.Builder, class string, rect image.Rectangle, href string) {
+	fmt.Fprintf(b, "\t<image class=\"%s\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" href=\"%s\" xlink:href=\"%s\"/>\n",
+		class,
+		rect.Min.X, rect.Min.Y,
+		rect.Dx(), rect.Dy(),
+		html.EscapeString(href), html.EscapeString(href),
+	)
+}
+
+func writeSvgText(b *strings.Builder, class string, element BannerElement, text string, shiftY int) {
+	fmt.Fprintf(b, "\t<text class=\"%s\" x=\"%d\" y=\"%d\" font-size=\"%g\" xml:space=\"preserve\">%s</text>\n",
+		class,
+		element.TextAt.X, element.TextAt.Y+shiftY,
+		element.FontSize,
+		html.EscapeString(text),
+	)
+}
+
+// write a ring of endorsement ratios (clockwise from the top) with dashed circles
+func writeSvgEndorsement(b *strings.Builder, rect image.Rectangle, endorsement Endorsement) {
+	cx := float64(rect.Min.X+rect.Max.X) / 2
+	cy := float64(rect.Min.Y+rect.Max.Y) / 2
+	radius := float64(rect.Dx()) / 2
+	ringWidth := float64(int(radius) / 3)
+	r := radius - ringWidth/2
+	circumference := 2 * math.Pi * r
+
+	fmt.Fprintf(b, "\t<g class=\"endorsement\" transform=\"rotate(-90 %g %g)\">\n", cx, cy)
+	fmt.Fprintf(b, "\t\t<circle class=\"endorsement-fill\" cx=\"%g\" cy=\"%g\" r=\"%g\"/>\n", cx, cy, radius)
+
+	total := float64(endorsement.Shotcaller + endorsement.Teammate + endorsement.Sportsmanship)
+	if total <= 0 {
+		fmt.Fprintf(b, "\t\t<circle class=\"endorsement-none\" cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"none\" stroke-width=\"%g\"/>\n", cx, cy, r, ringWidth)
+	} else {
+		offset := 0.0
+		for _, segment := range []struct {
+			class string
+			ratio float32
+		}{
+			{"endorsement-shotcaller", endorsement.Shotcaller},
+			{"endorsement-teammate", endorsement.Teammate},
+			{"endorsement-sportsmanship", endorsement.Sportsmanship},
+		} {
+			length := circumference * float64(segment.ratio) / total
+			fmt.Fprintf(b, "\t\t<circle class=\"%s\" cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"none\" stroke-width=\"%g\" stroke-dasharray=\"%.3f %.3f\" stroke-dashoffset=\"%.3f\"/>\n",
+				segment.class,
+				cx, cy, r,
+				ringWidth,
+				length, circumference-length,
+				-offset,
+			)
+			offset += length
+		}
+	}
+
+	b.WriteString("\t</g>\n")
+}
+
+func cssColor(c interface{ RGBA() (r, g, b, a uint32) }) string {
+	r, g, b, a := c.RGBA()
+	if a == 0xffff {
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", r>>8, g>>8, b>>8, float64(a)/0xffff)
+}
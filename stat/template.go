@@ -0,0 +1,261 @@
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// name of the main template in template directories (other .tmpl files in it are partials)
+	DefaultTemplateName = "index.tmpl"
+
+	// extension of partial template files
+	TemplateExtension = ".tmpl"
+)
+
+// functions available in html templates
+//
+//	sortHeroes:  sort heroes by their numeric values (or durations), descending. eg. {{range sortHeroes $heroes}}
+//	first:       take the first n elements of a slice. eg. {{range sortHeroes $heroes | first 3}}
+//	number:      format a number with thousands separators. eg. {{number "187720"}} => 187,720
+//	percent:     format a ratio (0.0 ~ 1.0) as a percentage. eg. {{percent .EndorsementBreakdown.Teammate}} => 54%
+//	hero:        find a hero in top heroes of a play stat. eg. {{with hero "Ana" .QuickPlay}}{{.ImageUrl}}{{end}}
+//	careerStat:  find career stats of a hero in a play stat. eg. {{with careerStat "Ana" .QuickPlay}}...{{end}}
+//	humanize:    humanize a duration. eg. {{humanize "64:17:50"}} => 64h 17m
+//	localize:    localize a label to given language. eg. {{localize .Language "Quick Play"}} => 빠른 대전 (for "ko-kr")
+//	json:        embed a value as json (eg. in <script>). eg. var stat = {{json .}};
+var HtmlFuncMap = template.FuncMap{
+	"sortHeroes": sortHeroes,
+	"first":      first,
+	"number":     formatNumber,
+	"percent":    formatPercent,
+	"hero":       findHero,
+	"careerStat": findCareerStat,
+	"humanize":   humanizeDuration,
+	"localize":   Localize,
+	"json":       toJson,
+}
+
+// parse given template string for html reports, with HtmlFuncMap
+func ParseHtmlTemplate(templateStr string) (*template.Template, error) {
+	return template.New("html").Funcs(HtmlFuncMap).Parse(templateStr)
+}
+
+// load a template file for html reports, with partials in given directories, and HtmlFuncMap
+//
+// - when path is a directory: its DefaultTemplateName will be loaded, with other .tmpl files in it as partials
+// - partials can be used with their file names (eg. {{template "hero.tmpl" .}}), or with their {{define}}d names
+func LoadHtmlTemplate(path string, partialDirs ...string) (tmpl *template.Template, err error) {
+	var info os.FileInfo
+	if info, err = os.Stat(path); err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return LoadHtmlTemplate(filepath.Join(path, DefaultTemplateName), append([]string{path}, partialDirs...)...)
+	}
+
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+	if tmpl, err = template.New(filepath.Base(path)).Funcs(HtmlFuncMap).Parse(string(bytes)); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range partialDirs {
+		var partials []string
+		if partials, err = filepath.Glob(filepath.Join(dir, "*"+TemplateExtension)); err != nil {
+			return nil, err
+		}
+
+		for _, partial := range partials {
+			if same, _ := sameFile(partial, path); same {
+				continue
+			}
+
+			if bytes, err = ioutil.ReadFile(partial); err != nil {
+				return nil, err
+			}
+			if _, err = tmpl.New(filepath.Base(partial)).Parse(string(bytes)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tmpl, nil
+}
+
+// sort heroes by their numeric values (or durations), descending
+//
+// (heroes with values which cannot be parsed come last)
+func sortHeroes(heroes []Hero) []Hero {
+	sorted := append([]Hero{}, heroes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aOk := comparableValue(sorted[i].Value)
+		b, bOk := comparableValue(sorted[j].Value)
+		if aOk != bOk {
+			return aOk
+		}
+		return a > b
+	})
+	return sorted
+}
+
+// take the first n elements of given slice
+func first(n int, list interface{}) (interface{}, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot take elements of %T", list)
+	}
+
+	if n < 0 {
+		n = 0
+	}
+	if n > value.Len() {
+		n = value.Len()
+	}
+	return value.Slice(0, n).Interface(), nil
+}
+
+// format given number (or numeric string) with thousands separators, and up to 2 decimals
+//
+// (strings which are not numeric will be returned as they are)
+func formatNumber(value interface{}) (string, error) {
+	var number float64
+	switch v := value.(type) {
+	case string:
+		var err error
+		if number, err = ParseNumber(v); err != nil {
+			return v, nil
+		}
+	default:
+		var err error
+		if number, err = toFloat64(value); err != nil {
+			return "", err
+		}
+	}
+
+	str := strconv.FormatFloat(math.Abs(number), 'f', 2, 64)
+	str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+
+	integer, fraction := str, ""
+	if i := strings.Index(str, "."); i >= 0 {
+		integer, fraction = str[:i], str[i:]
+	}
+	for i := len(integer) - 3; i > 0; i -= 3 {
+		integer = integer[:i] + "," + integer[i:]
+	}
+
+	if number < 0 {
+		integer = "-" + integer
+	}
+	return integer + fraction, nil
+}
+
+// format given ratio (0.0 ~ 1.0) as a percentage
+func formatPercent(value interface{}) (string, error) {
+	if number, err := toFloat64(value); err == nil {
+		return fmt.Sprintf("%.0f%%", number*100), nil
+	} else {
+		return "", err
+	}
+}
+
+// find a hero with given name in top heroes of given play stat
+func findHero(name string, playStat PlayStat) (Hero, error) {
+	for _, comparison := range playStat.TopHeroes {
+		for _, hero := range comparison.Heroes {
+			if strings.EqualFold(hero.Name, name) {
+				return hero, nil
+			}
+		}
+	}
+	return Hero{}, fmt.Errorf("no such hero in top heroes: %s", name)
+}
+
+// find career stats of a hero with given name in given play stat
+func findCareerStat(name string, playStat PlayStat) (CareerStat, error) {
+	for _, careerStat := range playStat.CareerStats {
+		if strings.EqualFold(careerStat.HeroName, name) {
+			return careerStat, nil
+		}
+	}
+	return CareerStat{}, fmt.Errorf("no such hero in career stats: %s", name)
+}
+
+// humanize given duration (eg. "64:17:50" => "64h 17m", "40 hours" => "40h")
+//
+// (values which are not durations will be returned as they are)
+func humanizeDuration(value string) string {
+	duration, err := ParseDuration(value)
+	if err != nil {
+		return value
+	}
+	return formatDuration(duration)
+}
+
+// format given duration in a short form (eg. "64h 17m", "40h", "3m 20s")
+func formatDuration(duration time.Duration) string {
+	hours := int64(duration / time.Hour)
+	minutes := int64(duration % time.Hour / time.Minute)
+	seconds := int64(duration % time.Minute / time.Second)
+
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0 && seconds > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// embed given value as json
+func toJson(value interface{}) (template.JS, error) {
+	if bytes, err := json.Marshal(value); err == nil {
+		return template.JS(bytes), nil
+	} else {
+		return "", err
+	}
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return ParseNumber(v.String())
+	}
+	return 0, fmt.Errorf("not a number: %v", value)
+}
+
+func sameFile(a, b string) (bool, error) {
+	if infoA, err := os.Stat(a); err == nil {
+		if infoB, err := os.Stat(b); err == nil {
+			return os.SameFile(infoA, infoB), nil
+		} else {
+			return false, err
+		}
+	} else {
+		return false, err
+	}
+}
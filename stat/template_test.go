@@ -0,0 +1,161 @@
+package stat
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestHtmlFuncs(t *testing.T) {
+	heroes := []Hero{
+		{Name: "Mercy", Value: "12 hours"},
+		{Name: "Ana", Value: "40 hours"},
+		{Name: "Genji", Value: NoValue},
+		{Name: "Reaper", Value: "15 minutes"},
+	}
+	sorted := sortHeroes(heroes)
+	names := []string{}
+	for _, hero := range sorted {
+		names = append(names, hero.Name)
+	}
+	if strings.Join(names, ",") != "Ana,Mercy,Reaper,Genji" {
+		t.Errorf("unexpected order of sorted heroes: %v", names)
+	}
+	if heroes[0].Name != "Mercy" {
+		t.Errorf("original heroes should not be sorted")
+	}
+
+	if taken, err := first(2, sorted); err != nil || len(taken.([]Hero)) != 2 {
+		t.Errorf("expected 2 heroes, got %v (%v)", taken, err)
+	}
+	if taken, err := first(10, sorted); err != nil || len(taken.([]Hero)) != 4 {
+		t.Errorf("expected 4 heroes, got %v (%v)", taken, err)
+	}
+	if _, err := first(1, 42); err == nil {
+		t.Errorf("expected an error for a non-slice")
+	}
+
+	for _, test := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{"187720", "187,720"},
+		{"5,527,480", "5,527,480"},
+		{"2.37", "2.37"},
+		{"54%", "54"},
+		{"--", "0"},
+		{"n/a", "n/a"},
+		{int32(1234567), "1,234,567"},
+		{-1234.5, "-1,234.5"},
+		{0.126, "0.13"},
+		{999, "999"},
+	} {
+		if formatted, err := formatNumber(test.value); err != nil || formatted != test.expected {
+			t.Errorf("expected '%s' for %v, got '%s' (%v)", test.expected, test.value, formatted, err)
+		}
+	}
+
+	if formatted, _ := formatPercent(float32(0.54)); formatted != "54%" {
+		t.Errorf("expected 54%%, got %s", formatted)
+	}
+	if _, err := formatPercent(struct{}{}); err == nil {
+		t.Errorf("expected an error for a non-number")
+	}
+
+	for value, expected := range map[string]string{
+		"64:17:50":   "64h 17m",
+		"40 hours":   "40h",
+		"17:05":      "17m 5s",
+		"15 minutes": "15m",
+		"00:01":      "1s",
+		"n/a":        "n/a",
+	} {
+		if humanized := humanizeDuration(value); humanized != expected {
+			t.Errorf("expected '%s' for '%s', got '%s'", expected, value, humanized)
+		}
+	}
+
+	if localized := Localize("ko-KR", "Quick Play"); localized != "빠른 대전" {
+		t.Errorf("expected a korean label, got %s", localized)
+	}
+	if localized := Localize("xx-xx", "Quick Play"); localized != "Quick Play" {
+		t.Errorf("expected the label itself for unknown languages, got %s", localized)
+	}
+
+	playStat := PlayStat{
+		TopHeroes:   HeroComparisons{{Name: "Time Played", Heroes: heroes}},
+		CareerStats: []CareerStat{{HeroName: "Ana"}},
+	}
+	if hero, err := findHero("ana", playStat); err != nil || hero.Name != "Ana" {
+		t.Errorf("expected to find Ana, got %v (%v)", hero, err)
+	}
+	if _, err := findHero("Nobody", playStat); err == nil {
+		t.Errorf("expected an error for an unknown hero")
+	}
+	if careerStat, err := findCareerStat("ANA", playStat); err != nil || careerStat.HeroName != "Ana" {
+		t.Errorf("expected to find career stats of Ana, got %v (%v)", careerStat, err)
+	}
+}
+
+func TestLoadHtmlTemplate(t *testing.T) {
+	stat := Stat{
+		BattleTag:            "meinside#3155",
+		EndorsementBreakdown: Endorsement{Teammate: 0.54},
+		QuickPlay: PlayStat{
+			TopHeroes: HeroComparisons{{Name: "Time Played", Heroes: []Hero{
+				{Name: "Mercy", ImageUrl: "mercy.png", Value: "12 hours"},
+				{Name: "Ana", ImageUrl: "ana.png", Value: "40 hours"},
+				{Name: "Genji", ImageUrl: "genji.png", Value: "1 hour"},
+			}}},
+			CareerStats: []CareerStat{{HeroName: "Ana", Categories: []CareerStatCategory{
+				{Name: "Combat", Values: KeyValues{{Key: "All Damage Done", Value: "5527480"}}},
+			}}},
+		},
+	}
+
+	// a directory with index.tmpl and partials, or a file with a directory of partials
+	for _, load := range []func() (*template.Template, error){
+		func() (*template.Template, error) { return LoadHtmlTemplate("testdata/templates") },
+		func() (*template.Template, error) {
+			return LoadHtmlTemplate("testdata/templates/index.tmpl", "testdata/templates")
+		},
+	} {
+		tmpl, err := load()
+		if err != nil {
+			t.Fatalf("failed to load template: %s", err)
+		}
+
+		html, err := RenderStatToHtmlWithTemplate(stat, nil, tmpl)
+		if err != nil {
+			t.Fatalf("failed to render html: %s", err)
+		}
+
+		for _, expected := range []string{
+			"영웅 순위",
+			`<img src="ana.png"> Ana 40h`,
+			`<img src="mercy.png"> Mercy 12h`,
+			"All Damage Done: 5,527,480",
+			"54%",
+			`"battletag":"meinside#3155"`,
+		} {
+			if !strings.Contains(html, expected) {
+				t.Errorf("expected '%s' in html:\n%s", expected, html)
+			}
+		}
+		if strings.Contains(html, `<img src="genji.png">`) {
+			t.Errorf("only the first 2 heroes should be in html")
+		}
+	}
+
+	// a file without partials
+	if _, err := LoadHtmlTemplate("testdata/templates/index.tmpl"); err != nil {
+		t.Errorf("failed to parse template without partials: %s", err)
+	} else if tmpl, _ := LoadHtmlTemplate("testdata/templates/index.tmpl"); tmpl != nil {
+		if _, err := RenderStatToHtmlWithTemplate(stat, nil, tmpl); err == nil {
+			t.Errorf("expected an error for a missing partial")
+		}
+	}
+	if _, err := LoadHtmlTemplate("testdata/templates/no-such-file.tmpl"); err == nil {
+		t.Errorf("expected an error for a missing template file")
+	}
+}
@@ -12,16 +12,20 @@ type Stat struct {
 	BattleTag string `json:"battletag"`
 	Platform  string `json:"platform"`
 	Region    string `json:"region"`
+	Language  string `json:"language"` // language of the career page (eg. "ko-kr")
 
 	// info
-	Name                    string `json:"name"`
-	ProfileImageUrl         string `json:"profile_image_url"`
-	Level                   int32  `json:"level"`
-	LevelImageUrl           string `json:"level_image_url"`
-	LevelStarImageUrl       string `json:"level_star_image_url"`
-	CompetitiveRank         int32  `json:"competitive_rank"`
-	CompetitiveRankImageUrl string `json:"competitive_rank_image_url"`
-	Detail                  string `json:"detail"`
+	Name                    string      `json:"name"`
+	ProfileImageUrl         string      `json:"profile_image_url"`
+	Level                   int32       `json:"level"`
+	LevelImageUrl           string      `json:"level_image_url"`
+	LevelStarImageUrl       string      `json:"level_star_image_url"`
+	CompetitiveRank         int32       `json:"competitive_rank"`
+	CompetitiveRankImageUrl string      `json:"competitive_rank_image_url"`
+	EndorsementLevel        int32       `json:"endorsement_level"`
+	EndorsementBreakdown    Endorsement `json:"endorsement_breakdown"`
+	Detail                  string      `json:"detail"`
+	Private                 bool        `json:"private"` // when true, stats and achievements are not available
 
 	// stats: quick/competitive play
 	QuickPlay       PlayStat `json:"quick_play"`
@@ -31,12 +35,19 @@ type Stat struct {
 	Achievements []AchievementCategory `json:"achievements"`
 }
 
+// ratios of received endorsements (0.0 ~ 1.0)
+type Endorsement struct {
+	Shotcaller    float32 `json:"shotcaller"`
+	Teammate      float32 `json:"teammate"`
+	Sportsmanship float32 `json:"sportsmanship"`
+}
+
 type PlayStat struct {
 	// featured stats
-	FeaturedStats map[string]string `json:"featured_stats"`
+	FeaturedStats KeyValues `json:"featured_stats"`
 
 	// top heroes
-	TopHeroes map[string][]Hero `json:"top_heroes"`
+	TopHeroes HeroComparisons `json:"top_heroes"`
 
 	// career stats
 	CareerStats []CareerStat `json:"career_stats"`
@@ -54,8 +65,8 @@ type CareerStat struct {
 }
 
 type CareerStatCategory struct {
-	Name   string            `json:"name"`
-	Values map[string]string `json:"values"`
+	Name   string    `json:"name"`
+	Values KeyValues `json:"values"`
 }
 
 type AchievementCategory struct {
@@ -0,0 +1,101 @@
+package stat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// value shown on the site when there is no data
+const NoValue = "--"
+
+// units of durations shown on the site (in supported languages)
+var durationUnits = map[string]time.Duration{
+	// en-us
+	"hour": time.Hour, "hours": time.Hour,
+	"minute": time.Minute, "minutes": time.Minute,
+	"second": time.Second, "seconds": time.Second,
+	// ko-kr
+	"시간": time.Hour,
+	"분":  time.Minute,
+	"초":  time.Second,
+	// de-de
+	"stunde": time.Hour, "stunden": time.Hour,
+	"minuten": time.Minute,
+	"sekunde": time.Second, "sekunden": time.Second,
+}
+
+// parse a numeric stat value into float64
+//
+// ex: "1,234" => 1234, "54%" => 54, "2.41" => 2.41, "--" => 0
+func ParseNumber(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	if s == NoValue {
+		return 0, nil
+	}
+
+	s = strings.TrimSuffix(s, "%")
+	s = strings.Replace(s, ",", "", -1) // XXX - remove unwanted ','
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("not a number: '%s'", value)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("not a finite number: '%s'", value)
+	}
+	return f, nil
+}
+
+// parse a duration stat value into time.Duration
+//
+// ex: "12 hours" => 12h, "45 minutes" => 45m, "12:34:56" => 12h34m56s, "01:09" => 1m9s, "--" => 0
+func ParseDuration(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+	if s == NoValue {
+		return 0, nil
+	}
+
+	// [hh:]mm:ss
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("not a duration: '%s'", value)
+		}
+
+		var seconds int64
+		for _, part := range parts {
+			n, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("not a duration: '%s'", value)
+			}
+			seconds = seconds*60 + int64(n)
+		}
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return 0, fmt.Errorf("duration out of range: '%s'", value)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	// number and unit, eg. "12 hours", "12시간"
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != ','
+	})
+	if i <= 0 {
+		return 0, fmt.Errorf("not a duration: '%s'", value)
+	}
+	n, err := ParseNumber(s[:i])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not a duration: '%s'", value)
+	}
+	unit, exists := durationUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !exists {
+		return 0, fmt.Errorf("unknown unit of duration: '%s'", value)
+	}
+	if n*float64(unit) >= math.MaxInt64 { // (float64(math.MaxInt64) is rounded up to 2^63)
+		return 0, fmt.Errorf("duration out of range: '%s'", value)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
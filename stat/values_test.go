@@ -0,0 +1,82 @@
+package stat
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseNumber(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"1,234":   1234,
+		"54%":     54,
+		"2.41":    2.41,
+		" 12 ":    12,
+		"--":      0,
+		"1,234.5": 1234.5,
+	} {
+		if n, err := ParseNumber(value); err != nil {
+			t.Errorf("failed to parse '%s': %s", value, err)
+		} else if n != expected {
+			t.Errorf("expected %f for '%s', got %f", expected, value, n)
+		}
+	}
+
+	for _, value := range []string{"", "abc", "12 hours", "01:09", "NaN", "Inf"} {
+		if _, err := ParseNumber(value); err == nil {
+			t.Errorf("expected an error for '%s'", value)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"12 hours":   12 * time.Hour,
+		"1 hour":     time.Hour,
+		"45 minutes": 45 * time.Minute,
+		"12:34:56":   12*time.Hour + 34*time.Minute + 56*time.Second,
+		"01:09":      time.Minute + 9*time.Second,
+		"--":         0,
+		"12시간":       12 * time.Hour,
+		"30분":        30 * time.Minute,
+		"3 Stunden":  3 * time.Hour,
+	} {
+		if d, err := ParseDuration(value); err != nil {
+			t.Errorf("failed to parse '%s': %s", value, err)
+		} else if d != expected {
+			t.Errorf("expected %s for '%s', got %s", expected, value, d)
+		}
+	}
+
+	for _, value := range []string{"", "hours", "12 fortnights", "1:2:3:4", "-1 hours", "1::2", "9223372036.854775808 seconds"} {
+		if _, err := ParseDuration(value); err == nil {
+			t.Errorf("expected an error for '%s'", value)
+		}
+	}
+}
+
+func FuzzParseNumber(f *testing.F) {
+	for _, seed := range []string{"1,234", "54%", "2.41", "--", "", "1e308", "-0", "NaN"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if n, err := ParseNumber(value); err == nil {
+			if math.IsNaN(n) || math.IsInf(n, 0) {
+				t.Errorf("non-finite number %f for '%s'", n, value)
+			}
+		}
+	})
+}
+
+func FuzzParseDuration(f *testing.F) {
+	for _, seed := range []string{"12 hours", "1 hour", "45 minutes", "12:34:56", "01:09", "--", "12시간", "3 Stunden", "99999999999999 hours"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if d, err := ParseDuration(value); err == nil && d < 0 {
+			t.Errorf("negative duration %s for '%s'", d, value)
+		}
+	})
+}
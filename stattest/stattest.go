@@ -0,0 +1,167 @@
+// Package stattest provides stored career pages and a http server serving them,
+// so codes using stat.FetchStat can be tested without network.
+//
+//	func TestSomething(t *testing.T) {
+//		stattest.UseServer(t)
+//
+//		fixture := stattest.FixturePcCompetitive
+//		s, err := stat.FetchStat(fixture.BattleTagString, fixture.BattleTagNumber, fixture.Platform, fixture.Region, fixture.Language)
+//		...
+//	}
+package stattest
+
+import (
+	"bytes"
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/meinside/overwatch-go/stat"
+)
+
+//go:embed fixtures/*.html
+var fixtureFiles embed.FS
+
+// a stored career page
+type Fixture struct {
+	Name            string
+	BattleTagString string
+	BattleTagNumber int
+	Platform        string
+	Region          string
+	Language        string
+	Filename        string
+}
+
+// stored career pages
+var (
+	FixturePcCompetitive = Fixture{
+		Name:            "pc_kr_en-us_competitive",
+		BattleTagString: "meinside",
+		BattleTagNumber: 3155,
+		Platform:        stat.PlatformPc,
+		Region:          "kr",
+		Language:        "en-us",
+		Filename:        "pc_kr_en-us_competitive.html",
+	}
+	FixturePcCompetitiveKorean = Fixture{
+		Name:            "pc_kr_ko-kr_competitive",
+		BattleTagString: "meinside",
+		BattleTagNumber: 3155,
+		Platform:        stat.PlatformPc,
+		Region:          "kr",
+		Language:        "ko-kr",
+		Filename:        "pc_kr_ko-kr_competitive.html",
+	}
+	FixturePcNoCompetitive = Fixture{
+		Name:            "pc_us_en-us_no_competitive",
+		BattleTagString: "casual",
+		BattleTagNumber: 1234,
+		Platform:        stat.PlatformPc,
+		Region:          "us",
+		Language:        "en-us",
+		Filename:        "pc_us_en-us_no_competitive.html",
+	}
+	FixtureXblConsole = Fixture{
+		Name:            "xbl_en-us_console",
+		BattleTagString: "meinside",
+		Platform:        stat.PlatformXbl,
+		Language:        "en-us",
+		Filename:        "xbl_en-us_console.html",
+	}
+	FixturePsnConsoleGerman = Fixture{
+		Name:            "psn_de-de_console",
+		BattleTagString: "meinside",
+		Platform:        stat.PlatformPsn,
+		Language:        "de-de",
+		Filename:        "psn_de-de_console.html",
+	}
+	FixturePcPrivate = Fixture{
+		Name:            "pc_eu_en-us_private",
+		BattleTagString: "hidden",
+		BattleTagNumber: 5678,
+		Platform:        stat.PlatformPc,
+		Region:          "eu",
+		Language:        "en-us",
+		Filename:        "pc_eu_en-us_private.html",
+	}
+)
+
+// all stored career pages
+var Fixtures = []Fixture{
+	FixturePcCompetitive,
+	FixturePcCompetitiveKorean,
+	FixturePcNoCompetitive,
+	FixtureXblConsole,
+	FixturePsnConsoleGerman,
+	FixturePcPrivate,
+}
+
+// url path of this fixture, same as the official site's
+func (f Fixture) Path() string {
+	return stat.GenPath(f.BattleTagString, f.BattleTagNumber, f.Platform, f.Region, f.Language)
+}
+
+// html bytes of this fixture
+func (f Fixture) Html() []byte {
+	bytes, err := fixtureFiles.ReadFile("fixtures/" + f.Filename)
+	if err != nil {
+		panic(err) // all fixtures are embedded, so it should not happen
+	}
+	return bytes
+}
+
+// parsed stat of this fixture (private ones too)
+//
+// fails the test when the fixture could not be parsed
+func (f Fixture) Stat(tb testing.TB) stat.Stat {
+	tb.Helper()
+
+	s, err := stat.ParseStat(bytes.NewReader(f.Html()), f.BattleTagString, f.BattleTagNumber, f.Platform, f.Region)
+	if err != nil && err != stat.ErrPrivateProfile {
+		tb.Fatalf("failed to parse fixture %s: %s", f.Name, err)
+	}
+	return s
+}
+
+// create a http handler which serves fixtures at the same paths as the official site
+//
+// requests for unknown paths will be responded with 404
+func NewHandler() http.Handler {
+	pages := map[string]Fixture{}
+	for _, fixture := range Fixtures {
+		pages[fixture.Path()] = fixture
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fixture, exists := pages[r.URL.Path]; exists {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(fixture.Html())
+		} else {
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// start a http server serving fixtures (should be closed after use)
+func NewServer() *httptest.Server {
+	return httptest.NewServer(NewHandler())
+}
+
+// start a http server serving fixtures, and point stat.BaseUrl to it
+//
+// both will be restored when the test finishes
+func UseServer(tb testing.TB) *httptest.Server {
+	server := NewServer()
+
+	original := stat.BaseUrl
+	stat.BaseUrl = server.URL
+
+	tb.Cleanup(func() {
+		stat.BaseUrl = original
+		server.Close()
+	})
+
+	return server
+}